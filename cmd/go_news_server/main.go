@@ -1,20 +1,37 @@
 package main
 
 import (
+	"bufio"
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"go_news_server/database/migrations"
+	"go_news_server/internal/api"
+	v1 "go_news_server/internal/api/v1"
+	"go_news_server/internal/events"
 	"go_news_server/internal/handlers"
+	"go_news_server/internal/middleware"
+	"go_news_server/internal/models"
 	"go_news_server/internal/repository"
 	"go_news_server/internal/routes"
 	"go_news_server/internal/server/utils"
 	"go_news_server/internal/services"
 	"go_news_server/pkg/config"
+	"go_news_server/pkg/database"
+	"go_news_server/pkg/locale"
 	"go_news_server/pkg/logging"
+	"go_news_server/pkg/sanitize"
+	"go_news_server/pkg/storage"
+	"go_news_server/pkg/textfilter"
+	"go_news_server/pkg/validate"
 	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
 	_ "github.com/lib/pq"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
@@ -33,6 +50,56 @@ var serverCmd = &cobra.Command{
 	},
 }
 
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Применить миграции базы данных",
+	Run: func(cmd *cobra.Command, args []string) {
+		runMigrations()
+	},
+}
+
+var (
+	importFormat                  string
+	importDryRun                  bool
+	importCreateMissingCategories bool
+	importBatchSize               int
+	exportFormat                  string
+	exportOutput                  string
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import [file]",
+	Short: "Импортировать новости из NDJSON или CSV файла (без аргумента или \"-\" - из stdin)",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		path := "-"
+		if len(args) > 0 {
+			path = args[0]
+		}
+		os.Exit(runImport(path))
+	},
+}
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Экспортировать новости с категориями в NDJSON или CSV",
+	Run: func(cmd *cobra.Command, args []string) {
+		os.Exit(runExport())
+	},
+}
+
+func init() {
+	importCmd.Flags().StringVar(&importFormat, "format", "", "формат: ndjson или csv (по умолчанию по расширению файла)")
+	importCmd.Flags().BoolVar(&importDryRun, "dry-run", false, "проверить импорт и откатить изменения")
+	importCmd.Flags().BoolVar(&importCreateMissingCategories, "create-missing-categories", false, "создавать отсутствующие категории")
+	importCmd.Flags().IntVar(&importBatchSize, "batch-size", 500, "количество строк в одной транзакции")
+
+	exportCmd.Flags().StringVar(&exportFormat, "format", models.FormatNDJSON, "формат: ndjson или csv")
+	exportCmd.Flags().StringVarP(&exportOutput, "output", "o", "-", "файл для записи (\"-\" - stdout)")
+
+	serverCmd.AddCommand(migrateCmd, importCmd, exportCmd)
+}
+
 func main() {
 	if err := serverCmd.Execute(); err != nil {
 		log.Printf("failed to execute command. err: %v", err)
@@ -40,7 +107,8 @@ func main() {
 	}
 }
 
-func runApplication() {
+// loadConfig загружает конфигурацию и настраивает логирование
+func loadConfig() *config.Config {
 	config, err := config.Load()
 	if err != nil {
 		fmt.Println(err)
@@ -63,6 +131,12 @@ func runApplication() {
 		ErrorMaxAge:     config.LogErrorMaxAge,
 		ErrorCompress:   config.LogErrorCompress,
 	})
+
+	return config
+}
+
+func runApplication() {
+	config := loadConfig()
 	defer func() {
 		if err := logging.DefaultLogger().Sync(); err != nil {
 		}
@@ -75,37 +149,369 @@ func runApplication() {
 			return &fxevent.ZapLogger{Logger: log.Named("fx")}
 		}),
 		fx.Provide(
+			newQueryLogger,
 			newDatabase,
+			newSanitizer,
 			newNewsRepository,
 			newNewsService,
 			newNewsHandler,
 			newCategoryRepository,
 			newCategoryService,
 			newCategoryHandler,
+			newTagRepository,
+			newTagService,
+			newTagHandler,
+			newAPIv1Handler,
+			newDocsHandler,
+			newStorage,
+			newMediaRepository,
+			newMediaService,
+			newMediaHandler,
+			newLocales,
+			newTranslationRepository,
+			newTranslationService,
+			newTranslationHandler,
+			newDebugHandler,
+			newWebhookRepository,
+			newWebhookService,
+			newWebhookHandler,
+			newWebhookWorker,
+			newFeedService,
+			newFeedHandler,
+			newSitemapService,
+			newSitemapHandler,
+			newNewsImportService,
+			newNewsImportHandler,
+			newEventBus,
+			newChangeListener,
+			newStreamService,
+			newRelatedService,
+			newViewRepository,
+			newViewService,
+			newViewHandler,
+			newCommentRepository,
+			newCommentService,
+			newCommentHandler,
+			newStreamHandler,
 			newServer,
 		),
 		fx.Invoke(
+			checkRequestValidation,
+			applyMigrations,
 			setupRoutes,
+			// фоновые компоненты запускаются через lifecycle, здесь они только создаются
+			func(*events.Listener) {},
+			func(*services.WebhookWorker) {},
 		),
 	)
 
 	app.Run()
 }
 
-// newDatabase создает подключение к базе данных
-func newDatabase(cfg *config.Config) *reform.DB {
+// runMigrations применяет миграции и завершает работу
+func runMigrations() {
+	config := loadConfig()
+	logger := logging.DefaultLogger()
+	defer func() {
+		if err := logger.Sync(); err != nil {
+		}
+	}()
+
+	sqlDB := openSQLDB(config, config.DataSourceName)
+	defer func() {
+		if err := sqlDB.Close(); err != nil {
+		}
+	}()
+
+	db := reform.NewDB(sqlDB, postgresql.Dialect, nil)
+	if err := migrations.Apply(context.Background(), db, logger); err != nil {
+		os.Exit(1)
+	}
+}
+
+// runImport импортирует новости из файла и печатает результат.
+// Возвращает код завершения: 1, если импорт не выполнен или в строках есть ошибки.
+func runImport(path string) int {
+	config := loadConfig()
 	logger := logging.DefaultLogger()
+	defer func() {
+		if err := logger.Sync(); err != nil {
+		}
+	}()
+
+	format := importFormat
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".ndjson", ".jsonl":
+			format = models.FormatNDJSON
+		case ".csv":
+			format = models.FormatCSV
+		}
+	}
+	if !services.IsNewsFormat(format) {
+		fmt.Fprintln(os.Stderr, "unknown import format, use --format=ndjson or --format=csv")
+		return 1
+	}
+
+	in := os.Stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		defer file.Close()
+		in = file
+	}
 
-	sqlDB, err := sql.Open("postgres", cfg.DataSourceName)
+	sqlDB := openSQLDB(config, config.DataSourceName)
+	defer func() {
+		if err := sqlDB.Close(); err != nil {
+		}
+	}()
+
+	db := reform.NewDB(sqlDB, postgresql.Dialect, nil)
+	service := newNewsImportService(newNewsRepository(db), newCategoryRepository(db), newSanitizer(config))
+
+	result, err := service.Import(context.Background(), format, in, models.NewsImportOptions{
+		DryRun:                  importDryRun,
+		CreateMissingCategories: importCreateMissingCategories,
+		BatchSize:               importBatchSize,
+	})
 	if err != nil {
-		logger.Errorw("Failed to open database", err)
-		panic(err)
+		logger.Errorw("News import failed", "error", err)
+		return 1
 	}
 
-	// Configure connection pool
-	sqlDB.SetMaxOpenConns(cfg.DBMaxOpenConns)
-	sqlDB.SetMaxIdleConns(cfg.DBMaxIdleConns)
-	sqlDB.SetConnMaxLifetime(time.Duration(cfg.DBConnMaxLifetime) * time.Minute)
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(result); err != nil {
+		return 1
+	}
+	if result.Failed > 0 {
+		return 1
+	}
+	return 0
+}
+
+// runExport выгружает новости в файл или stdout
+func runExport() int {
+	config := loadConfig()
+	logger := logging.DefaultLogger()
+	defer func() {
+		if err := logger.Sync(); err != nil {
+		}
+	}()
+
+	if !services.IsNewsFormat(exportFormat) {
+		fmt.Fprintln(os.Stderr, "unknown export format, use --format=ndjson or --format=csv")
+		return 1
+	}
+
+	out := os.Stdout
+	if exportOutput != "-" {
+		file, err := os.Create(exportOutput)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		defer file.Close()
+		out = file
+	}
+
+	sqlDB := openSQLDB(config, config.DataSourceName)
+	defer func() {
+		if err := sqlDB.Close(); err != nil {
+		}
+	}()
+
+	db := reform.NewDB(sqlDB, postgresql.Dialect, nil)
+	service := newNewsImportService(newNewsRepository(db), newCategoryRepository(db), newSanitizer(config))
+
+	w := bufio.NewWriter(out)
+	if err := service.Export(context.Background(), exportFormat, w); err != nil {
+		logger.Errorw("News export failed", "error", err)
+		return 1
+	}
+	if err := w.Flush(); err != nil {
+		logger.Errorw("News export failed", "error", err)
+		return 1
+	}
+	return 0
+}
+
+// checkRequestValidation проверяет теги validate тел запросов: ошибка в теге останавливает запуск,
+// а не проявляется при первом запросе
+func checkRequestValidation() error {
+	for _, req := range []any{
+		models.NewsEditRequest{},
+		models.CategoryCreateRequest{},
+		models.CategoryUpdateRequest{},
+		models.NewsTranslationRequest{},
+		models.CategoryTranslationRequest{},
+		v1.NewsUpdateRequest{},
+		v1.CategoryRequest{},
+		v1.CategoryMoveRequest{},
+	} {
+		if err := validate.Check(req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyMigrations применяет миграции при старте сервера, если это включено в конфигурации
+func applyMigrations(cfg *config.Config, db *reform.DB) error {
+	if !cfg.DBAutoMigrate {
+		return nil
+	}
+	return migrations.Apply(context.Background(), db, logging.DefaultLogger())
+}
+
+// newEventBus создает шину событий об изменениях данных
+func newEventBus() *events.Bus {
+	return events.NewBus()
+}
+
+// newChangeListener создает слушателя уведомлений PostgreSQL об изменениях новостей и категорий
+func newChangeListener(lc fx.Lifecycle, cfg *config.Config, bus *events.Bus) *events.Listener {
+	listener := events.NewListener(cfg.DataSourceName, bus, logging.DefaultLogger())
+
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			return listener.Start()
+		},
+		OnStop: func(context.Context) error {
+			return listener.Stop()
+		},
+	})
+
+	return listener
+}
+
+// newRelatedService создает кэш похожих новостей, сбрасываемый по событиям шины
+func newRelatedService(lc fx.Lifecycle, cfg *config.Config, repo *repository.NewsRepository, bus *events.Bus) *services.RelatedService {
+	service := services.NewRelatedService(repo, bus, services.RelatedConfig{
+		TTL:        time.Duration(cfg.RelatedCacheTTL) * time.Second,
+		MaxEntries: cfg.RelatedCacheMaxEntries,
+	})
+
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			service.Start()
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			service.Stop()
+			return nil
+		},
+	})
+
+	return service
+}
+
+// newViewRepository создает репозиторий просмотров новостей
+func newViewRepository(db *reform.DB) *repository.ViewRepository {
+	return &repository.ViewRepository{DB: db}
+}
+
+// newViewService создает учет просмотров новостей. При остановке оставшиеся просмотры записываются в базу.
+func newViewService(lc fx.Lifecycle, cfg *config.Config, repo *repository.ViewRepository) *services.ViewService {
+	service := services.NewViewService(repo, services.ViewConfig{
+		DedupWindow:   time.Duration(cfg.ViewsDedupWindow) * time.Second,
+		FlushInterval: time.Duration(cfg.ViewsFlushInterval) * time.Second,
+	}, logging.DefaultLogger())
+
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			service.Start()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			service.Stop(ctx)
+			return nil
+		},
+	})
+
+	return service
+}
+
+// newViewHandler создает обработчик просмотров новостей
+func newViewHandler(service *services.ViewService) *handlers.ViewHandler {
+	return &handlers.ViewHandler{Service: service}
+}
+
+// newCommentRepository создает репозиторий комментариев
+func newCommentRepository(db *reform.DB) *repository.CommentRepository {
+	return &repository.CommentRepository{DB: db}
+}
+
+// newCommentService создает сервис комментариев с фильтром запрещенных слов и ссылок
+func newCommentService(cfg *config.Config, repo *repository.CommentRepository) *services.CommentService {
+	return services.NewCommentService(repo, textfilter.New(cfg.CommentsBlockedWords, cfg.CommentsAllowLinks), services.CommentConfig{
+		Premoderation: cfg.CommentsPremoderation,
+		RateLimit:     cfg.CommentsRateLimit,
+		RateWindow:    time.Duration(cfg.CommentsRateWindow) * time.Second,
+		MaxLength:     cfg.CommentsMaxLength,
+	})
+}
+
+// newCommentHandler создает обработчик комментариев
+func newCommentHandler(cfg *config.Config, service *services.CommentService) *handlers.CommentHandler {
+	if cfg.ReaderJWTSecret == "" {
+		logging.DefaultLogger().Warn("READER_JWT_SECRET is not set, POST /news/:id/comments is disabled")
+	}
+	return &handlers.CommentHandler{Service: service}
+}
+
+// newStreamService создает сервис потока изменений для Server-Sent Events.
+// Клиенты отключаются до остановки HTTP сервера, иначе открытые потоки задержали бы его завершение.
+func newStreamService(
+	lc fx.Lifecycle,
+	cfg *config.Config,
+	newsRepo *repository.NewsRepository,
+	categoryRepo *repository.CategoryRepository,
+	bus *events.Bus,
+) *services.StreamService {
+	service := services.NewStreamService(newsRepo, categoryRepo, bus, services.StreamServiceConfig{
+		BufferSize: cfg.StreamBufferSize,
+	}, logging.DefaultLogger())
+
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			service.Start()
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			service.Stop()
+			return nil
+		},
+	})
+
+	return service
+}
+
+// newStreamHandler создает обработчик потока изменений
+func newStreamHandler(cfg *config.Config, service *services.StreamService) *handlers.StreamHandler {
+	return &handlers.StreamHandler{
+		Service:           service,
+		HeartbeatInterval: time.Duration(cfg.StreamHeartbeatInterval) * time.Second,
+	}
+}
+
+// newQueryLogger создает логгер SQL запросов со сбором статистики
+func newQueryLogger(cfg *config.Config, logger *zap.Logger) *logging.QueryLogger {
+	return logging.NewQueryLogger(logger, logging.QueryLoggerConfig{
+		SlowThreshold: time.Duration(cfg.DBSlowQueryThreshold) * time.Millisecond,
+		LogArgs:       cfg.DBLogQueryArgs,
+	})
+}
+
+// newDatabase создает подключение к базе данных
+func newDatabase(lc fx.Lifecycle, cfg *config.Config, queryLogger *logging.QueryLogger) *reform.DB {
+	logger := logging.DefaultLogger()
+
+	sqlDB := openSQLDB(cfg, cfg.DataSourceName)
 
 	// Test the connection
 	if err := sqlDB.Ping(); err != nil {
@@ -113,18 +519,79 @@ func newDatabase(cfg *config.Config) *reform.DB {
 		panic(err)
 	}
 
-	db := reform.NewDB(sqlDB, postgresql.Dialect, reform.NewPrintfLogger(log.Printf))
+	if len(cfg.DBReplicaDataSourceNames) == 0 {
+		db := reform.NewDB(sqlDB, postgresql.Dialect, queryLogger)
+		db.TXAttempts = cfg.DBTxAttempts
+		return db
+	}
+
+	// Читающие запросы распределяются по репликам, их доступность проверяется при старте
+	replicas := make(map[string]*sql.DB, len(cfg.DBReplicaDataSourceNames))
+	for i, dsn := range cfg.DBReplicaDataSourceNames {
+		replicas[fmt.Sprintf("replica-%d", i+1)] = openSQLDB(cfg, dsn)
+	}
+
+	replicaDB := database.NewReplicaDB(sqlDB, replicas, database.ReplicaConfig{
+		HealthCheckInterval: time.Duration(cfg.DBReplicaHealthCheckInterval) * time.Second,
+	}, logger)
+
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			replicaDB.Start()
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			return replicaDB.Close()
+		},
+	})
+
+	logger.Infof("Read queries are routed to %d replica(s)", len(replicas))
+	db := reform.NewDBFromInterface(replicaDB, postgresql.Dialect, queryLogger)
+	db.TXAttempts = cfg.DBTxAttempts
 	return db
 }
 
+// openSQLDB открывает пул соединений с базой данных
+func openSQLDB(cfg *config.Config, dataSourceName string) *sql.DB {
+	logger := logging.DefaultLogger()
+
+	sqlDB, err := sql.Open("postgres", dataSourceName)
+	if err != nil {
+		logger.Errorw("Failed to open database", err)
+		panic(err)
+	}
+
+	// Configure connection pool
+	sqlDB.SetMaxOpenConns(cfg.DBMaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.DBMaxIdleConns)
+	sqlDB.SetConnMaxLifetime(time.Duration(cfg.DBConnMaxLifetime) * time.Minute)
+
+	return sqlDB
+}
+
 // newNewsRepository создает репозиторий для новостей
 func newNewsRepository(db *reform.DB) *repository.NewsRepository {
 	return &repository.NewsRepository{DB: db}
 }
 
+// newSanitizer создает очистку HTML содержимого новостей
+func newSanitizer(cfg *config.Config) *sanitize.Sanitizer {
+	return sanitize.New(sanitize.Config{
+		StrictTags:  cfg.SanitizeStrictTags,
+		RelaxedTags: cfg.SanitizeRelaxedTags,
+	})
+}
+
 // newNewsService создает сервис для новостей
-func newNewsService(repo *repository.NewsRepository) *services.NewsService {
-	return &services.NewsService{Repository: repo}
+func newNewsService(
+	repo *repository.NewsRepository,
+	sanitizer *sanitize.Sanitizer,
+	media *services.MediaService,
+	translations *services.TranslationService,
+	related *services.RelatedService,
+	views *services.ViewService,
+) *services.NewsService {
+	return &services.NewsService{Repository: repo, Sanitizer: sanitizer, Media: media, Translations: translations, Related: related, Views: views}
 }
 
 // newNewsHandler создает обработчик для новостей
@@ -138,8 +605,8 @@ func newCategoryRepository(db *reform.DB) *repository.CategoryRepository {
 }
 
 // newCategoryService создает сервис для категорий
-func newCategoryService(repo *repository.CategoryRepository) *services.CategoryService {
-	return services.NewCategoryService(repo)
+func newCategoryService(repo *repository.CategoryRepository, translations *services.TranslationService) *services.CategoryService {
+	return services.NewCategoryService(repo, translations)
 }
 
 // newCategoryHandler создает обработчик для категорий
@@ -147,34 +614,242 @@ func newCategoryHandler(service *services.CategoryService) *handlers.CategoryHan
 	return handlers.NewCategoryHandler(service)
 }
 
+// newTagRepository создает репозиторий для тегов
+func newTagRepository(db *reform.DB) *repository.TagRepository {
+	return &repository.TagRepository{DB: db}
+}
+
+// newTagService создает сервис для тегов
+func newTagService(repo *repository.TagRepository) *services.TagService {
+	return services.NewTagService(repo)
+}
+
+// newTagHandler создает обработчик для тегов
+func newTagHandler(service *services.TagService) *handlers.TagHandler {
+	return handlers.NewTagHandler(service)
+}
+
+// newAPIv1Handler создает обработчик /api/v1
+func newAPIv1Handler(news *services.NewsService, categories *services.CategoryService, tags *services.TagService) *v1.Handler {
+	return &v1.Handler{News: news, Categories: categories, Tags: tags}
+}
+
+// newDocsHandler создает обработчик описания API: /openapi.json и Swagger UI на /docs
+func newDocsHandler(cfg *config.Config) (*handlers.DocsHandler, error) {
+	return handlers.NewDocsHandler(api.OpenAPI(cfg.PublicBaseURL), cfg.DocsAssetsURL)
+}
+
+// newStorage создает хранилище изображений новостей по MEDIA_STORAGE
+func newStorage(cfg *config.Config) (storage.Storage, error) {
+	switch cfg.MediaStorage {
+	case config.MediaStorageLocal:
+		return storage.NewLocal(cfg.MediaLocalDir, cfg.MediaBaseURL), nil
+	case config.MediaStorageS3:
+		if cfg.S3Endpoint == "" || cfg.S3Bucket == "" {
+			return nil, fmt.Errorf("MEDIA_STORAGE=s3 requires S3_ENDPOINT and S3_BUCKET")
+		}
+		return storage.NewS3(storage.S3Config{
+			Endpoint:  cfg.S3Endpoint,
+			Region:    cfg.S3Region,
+			Bucket:    cfg.S3Bucket,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+			PathStyle: cfg.S3PathStyle,
+			PublicURL: cfg.S3PublicURL,
+		}, nil), nil
+	default:
+		return nil, fmt.Errorf("unknown MEDIA_STORAGE %q, use %q or %q", cfg.MediaStorage, config.MediaStorageLocal, config.MediaStorageS3)
+	}
+}
+
+// newMediaRepository создает репозиторий для изображений новостей
+func newMediaRepository(db *reform.DB) *repository.MediaRepository {
+	return &repository.MediaRepository{DB: db}
+}
+
+// newMediaService создает сервис изображений новостей
+func newMediaService(cfg *config.Config, repo *repository.MediaRepository, store storage.Storage) *services.MediaService {
+	return services.NewMediaService(repo, store, services.MediaConfig{
+		MaxSize:       cfg.MediaMaxSize,
+		ThumbnailSize: cfg.MediaThumbnailSize,
+	}, logging.DefaultLogger())
+}
+
+// newMediaHandler создает обработчик изображений новостей
+func newMediaHandler(service *services.MediaService) *handlers.MediaHandler {
+	return &handlers.MediaHandler{Service: service}
+}
+
+// newLocales создает список языков и цепочки замены переводов из LOCALES и LOCALE_FALLBACKS
+func newLocales(cfg *config.Config) (*locale.Locales, error) {
+	return locale.New(cfg.Locales, cfg.LocaleFallbacks)
+}
+
+// newTranslationRepository создает репозиторий для переводов
+func newTranslationRepository(db *reform.DB) *repository.TranslationRepository {
+	return &repository.TranslationRepository{DB: db}
+}
+
+// newTranslationService создает сервис переводов новостей и категорий
+func newTranslationService(repo *repository.TranslationRepository, locales *locale.Locales, sanitizer *sanitize.Sanitizer) *services.TranslationService {
+	return services.NewTranslationService(repo, locales, sanitizer)
+}
+
+// newTranslationHandler создает обработчик переводов
+func newTranslationHandler(service *services.TranslationService) *handlers.TranslationHandler {
+	return &handlers.TranslationHandler{Service: service}
+}
+
+// newDebugHandler создает обработчик отладочных эндпоинтов
+func newDebugHandler(queryLogger *logging.QueryLogger) *handlers.DebugHandler {
+	return &handlers.DebugHandler{QueryLogger: queryLogger}
+}
+
+// newWebhookRepository создает репозиторий для вебхуков
+func newWebhookRepository(db *reform.DB) *repository.WebhookRepository {
+	return &repository.WebhookRepository{DB: db}
+}
+
+// newWebhookService создает сервис для вебхуков
+func newWebhookService(repo *repository.WebhookRepository) *services.WebhookService {
+	return services.NewWebhookService(repo)
+}
+
+// newWebhookHandler создает обработчик для вебхуков
+func newWebhookHandler(service *services.WebhookService) *handlers.WebhookHandler {
+	return handlers.NewWebhookHandler(service)
+}
+
+// newWebhookWorker создает фоновую доставку вебхуков из outbox
+func newWebhookWorker(lc fx.Lifecycle, cfg *config.Config, repo *repository.WebhookRepository) *services.WebhookWorker {
+	worker := services.NewWebhookWorker(repo, services.WebhookWorkerConfig{
+		PollInterval: time.Duration(cfg.WebhookPollInterval) * time.Second,
+		MaxAttempts:  cfg.WebhookMaxAttempts,
+		BackoffBase:  time.Duration(cfg.WebhookBackoffBase) * time.Second,
+		BackoffMax:   time.Duration(cfg.WebhookBackoffMax) * time.Second,
+		Timeout:      time.Duration(cfg.WebhookTimeout) * time.Second,
+	}, logging.DefaultLogger())
+
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			worker.Start()
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			worker.Stop()
+			return nil
+		},
+	})
+
+	return worker
+}
+
+// newFeedService создает сервис RSS и Atom лент
+func newFeedService(cfg *config.Config, newsRepo *repository.NewsRepository, categoryRepo *repository.CategoryRepository) *services.FeedService {
+	return services.NewFeedService(newsRepo, categoryRepo, services.FeedConfig{
+		BaseURL: cfg.PublicBaseURL,
+		Title:   cfg.FeedTitle,
+		Items:   cfg.FeedItems,
+	})
+}
+
+// newFeedHandler создает обработчик лент
+func newFeedHandler(service *services.FeedService) *handlers.FeedHandler {
+	return &handlers.FeedHandler{Service: service}
+}
+
+// newSitemapService создает сервис sitemap
+func newSitemapService(cfg *config.Config, newsRepo *repository.NewsRepository, categoryRepo *repository.CategoryRepository) *services.SitemapService {
+	return services.NewSitemapService(newsRepo, categoryRepo, services.SitemapConfig{
+		BaseURL: cfg.PublicBaseURL,
+	})
+}
+
+// newSitemapHandler создает обработчик sitemap
+func newSitemapHandler(service *services.SitemapService) *handlers.SitemapHandler {
+	return &handlers.SitemapHandler{Service: service, Logger: logging.DefaultLogger()}
+}
+
+// newNewsImportService создает сервис импорта и экспорта новостей
+func newNewsImportService(newsRepo *repository.NewsRepository, categoryRepo *repository.CategoryRepository, sanitizer *sanitize.Sanitizer) *services.NewsImportService {
+	return services.NewNewsImportService(newsRepo, categoryRepo, sanitizer, logging.DefaultLogger())
+}
+
+// newNewsImportHandler создает обработчик импорта и экспорта новостей
+func newNewsImportHandler(service *services.NewsImportService) *handlers.NewsImportHandler {
+	return &handlers.NewsImportHandler{Service: service, Logger: logging.DefaultLogger()}
+}
+
 // setupRoutes настраивает маршруты приложения
 func setupRoutes(
 	app *fiber.App,
 	newsHandler *handlers.NewsHandlers,
 	categoryHandler *handlers.CategoryHandler,
+	tagHandler *handlers.TagHandler,
+	mediaHandler *handlers.MediaHandler,
+	translationHandler *handlers.TranslationHandler,
+	viewHandler *handlers.ViewHandler,
+	commentHandler *handlers.CommentHandler,
+	debugHandler *handlers.DebugHandler,
+	streamHandler *handlers.StreamHandler,
+	webhookHandler *handlers.WebhookHandler,
+	feedHandler *handlers.FeedHandler,
+	sitemapHandler *handlers.SitemapHandler,
+	importHandler *handlers.NewsImportHandler,
+	apiV1Handler *v1.Handler,
+	docsHandler *handlers.DocsHandler,
+	locales *locale.Locales,
 	cfg *config.Config,
 ) {
-	routes.PublicRoutes(app, newsHandler)
-	routes.PrivateRoutes(app, newsHandler, cfg)
-	routes.SetupCategoryRoutes(app, categoryHandler)
-	routes.NotFoundRoute(app)
+	routes.Setup(app, routes.Handlers{
+		News:        newsHandler,
+		Category:    categoryHandler,
+		Tag:         tagHandler,
+		Media:       mediaHandler,
+		Translation: translationHandler,
+		View:        viewHandler,
+		Comment:     commentHandler,
+		Debug:       debugHandler,
+		Stream:      streamHandler,
+		Webhook:     webhookHandler,
+		Feed:        feedHandler,
+		Sitemap:     sitemapHandler,
+		Import:      importHandler,
+		APIv1:       apiV1Handler,
+		Docs:        docsHandler,
+	}, locales, cfg)
 }
 
-func newServer(lc fx.Lifecycle, cfg *config.Config) *fiber.App {
+func newServer(lc fx.Lifecycle, cfg *config.Config, media *services.MediaService) *fiber.App {
 	logger := logging.DefaultLogger()
 
-	fiberConfig := config.FiberConfig(cfg.ServerReadTimeout)
+	// тело запроса должно вмещать загружаемое изображение вместе с остальными полями формы
+	bodyLimit := max(int(media.MaxSize())+1<<20, fiber.DefaultBodyLimit)
+	fiberConfig := config.FiberConfig(cfg.ServerReadTimeout, bodyLimit, handlers.ErrorHandler(logger))
 	app := fiber.New(fiberConfig)
+	// ID запроса попадает в заголовок X-Request-ID и в ответы об ошибках
+	app.Use(requestid.New(requestid.Config{ContextKey: handlers.RequestIDKey}))
+	// изменяющие запросы читают с primary: реплика может еще не получить их запись
+	app.Use(middleware.PrimaryForWrites())
 
 	// Настраиваем lifecycle для graceful shutdown
 	lc.Append(fx.Hook{
 		OnStart: func(context.Context) error {
-			// Start server (with or without graceful shutdown).
-			if os.Getenv("STAGE_STATUS") == "dev" {
-				utils.StartServer(app, logger, cfg)
-			} else {
-				utils.StartServerWithGracefulShutdown(app, logger, cfg)
+			// Адрес занимается сразу, чтобы ошибка (например, занятый порт) остановила запуск приложения,
+			// а запросы обрабатываются в горутине, чтобы не задерживать остальные OnStart хуки.
+			ln, err := utils.Listen(cfg)
+			if err != nil {
+				return err
 			}
+
+			// Start server (with or without graceful shutdown).
+			go func() {
+				if os.Getenv("STAGE_STATUS") == "dev" {
+					utils.StartServer(app, ln, logger)
+				} else {
+					utils.StartServerWithGracefulShutdown(app, ln, logger)
+				}
+			}()
 			logger.Infof("Start to rest api server :%d", cfg.ServerPort)
 			return nil
 		},
@@ -0,0 +1,47 @@
+-- Уведомления об изменениях новостей и категорий через LISTEN/NOTIFY.
+-- В payload передаются только идентификаторы: размер сообщения NOTIFY ограничен 8000 байт.
+CREATE OR REPLACE FUNCTION notify_news_change() RETURNS trigger AS $$
+DECLARE
+    payload json;
+    rec RECORD;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        rec := OLD;
+    ELSE
+        rec := NEW;
+    END IF;
+
+    IF TG_TABLE_NAME = 'NewsCategories' THEN
+        payload := json_build_object(
+            'table', TG_TABLE_NAME,
+            'action', TG_OP,
+            'news_id', rec."NewsId",
+            'category_id', rec."CategoryId"
+        );
+    ELSE
+        payload := json_build_object(
+            'table', TG_TABLE_NAME,
+            'action', TG_OP,
+            'id', rec."Id"
+        );
+    END IF;
+
+    PERFORM pg_notify('news_changes', payload::text);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS news_change_notify ON "News";
+CREATE TRIGGER news_change_notify
+    AFTER INSERT OR UPDATE OR DELETE ON "News"
+    FOR EACH ROW EXECUTE FUNCTION notify_news_change();
+
+DROP TRIGGER IF EXISTS categories_change_notify ON "Categories";
+CREATE TRIGGER categories_change_notify
+    AFTER INSERT OR UPDATE OR DELETE ON "Categories"
+    FOR EACH ROW EXECUTE FUNCTION notify_news_change();
+
+DROP TRIGGER IF EXISTS news_categories_change_notify ON "NewsCategories";
+CREATE TRIGGER news_categories_change_notify
+    AFTER INSERT OR UPDATE OR DELETE ON "NewsCategories"
+    FOR EACH ROW EXECUTE FUNCTION notify_news_change();
@@ -0,0 +1,43 @@
+-- Подписки партнеров на изменения новостей и категорий
+CREATE TABLE IF NOT EXISTS "Webhooks" (
+    "Id" BIGSERIAL PRIMARY KEY,
+    "Url" TEXT NOT NULL,
+    "Secret" VARCHAR(255) NOT NULL,
+    "EventTypes" TEXT[] NOT NULL DEFAULT '{}',
+    "CategoryIds" BIGINT[] NOT NULL DEFAULT '{}',
+    "Active" BOOLEAN NOT NULL DEFAULT TRUE,
+    "CreatedAt" TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    "UpdatedAt" TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Transactional outbox: события записываются в той же транзакции, что и изменение данных,
+-- и затем раскладываются по подпискам фоновым обработчиком
+CREATE TABLE IF NOT EXISTS "WebhookOutbox" (
+    "Id" BIGSERIAL PRIMARY KEY,
+    "EventType" VARCHAR(50) NOT NULL,
+    "CategoryIds" BIGINT[] NOT NULL DEFAULT '{}',
+    "Payload" JSONB NOT NULL,
+    "CreatedAt" TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    "ProcessedAt" TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_outbox_unprocessed ON "WebhookOutbox"("Id") WHERE "ProcessedAt" IS NULL;
+
+-- Журнал доставок: одна запись на пару (событие, подписка)
+CREATE TABLE IF NOT EXISTS "WebhookDeliveries" (
+    "Id" BIGSERIAL PRIMARY KEY,
+    "WebhookId" BIGINT NOT NULL REFERENCES "Webhooks"("Id") ON DELETE CASCADE,
+    "OutboxId" BIGINT NOT NULL REFERENCES "WebhookOutbox"("Id") ON DELETE CASCADE,
+    "EventType" VARCHAR(50) NOT NULL,
+    "Status" VARCHAR(20) NOT NULL DEFAULT 'pending',
+    "Attempts" INT NOT NULL DEFAULT 0,
+    "NextAttemptAt" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "LastStatusCode" INT,
+    "LastError" TEXT,
+    "CreatedAt" TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    "UpdatedAt" TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE ("WebhookId", "OutboxId")
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_pending ON "WebhookDeliveries"("NextAttemptAt") WHERE "Status" = 'pending';
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook_id ON "WebhookDeliveries"("WebhookId", "Id" DESC);
@@ -0,0 +1,7 @@
+-- Время создания и последнего изменения новости для лент и sitemap.
+-- Для существующих новостей используется время применения миграции.
+ALTER TABLE "News"
+    ADD COLUMN IF NOT EXISTS "CreatedAt" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS "UpdatedAt" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_news_created_at ON "News"("CreatedAt" DESC);
@@ -0,0 +1,8 @@
+-- Текст новости без HTML разметки для поиска и лент, заполняется сервисом при записи.
+-- Для существующих новостей теги удаляются приближенно, точный текст появится при следующем изменении.
+ALTER TABLE "News"
+    ADD COLUMN IF NOT EXISTS "ContentText" TEXT NOT NULL DEFAULT '';
+
+UPDATE "News"
+SET "ContentText" = btrim(regexp_replace(regexp_replace("Content", '<[^>]*>', ' ', 'g'), '\s+', ' ', 'g'))
+WHERE "ContentText" = '';
@@ -0,0 +1,8 @@
+-- Формат исходного содержимого новости. Для Markdown исходный текст хранится в "ContentSource",
+-- а "Content" содержит сформированный из него и очищенный HTML.
+ALTER TABLE "News"
+    ADD COLUMN IF NOT EXISTS "ContentFormat" TEXT NOT NULL DEFAULT 'html',
+    ADD COLUMN IF NOT EXISTS "ContentSource" TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE "News" DROP CONSTRAINT IF EXISTS news_content_format_check;
+ALTER TABLE "News" ADD CONSTRAINT news_content_format_check CHECK ("ContentFormat" IN ('html', 'markdown'));
@@ -0,0 +1,130 @@
+-- Slug новостей и категорий для URL. Приложение формирует slug из "Title"/"Name" пакетом pkg/slug;
+-- функция slugify повторяет его транслитерацию для заполнения существующих строк и вставок в обход приложения
+-- (например, тестовых категорий из schema.sql). Латиница с диакритикой здесь не приводится к ASCII.
+-- Прежние slug хранятся в таблицах истории, чтобы старые ссылки перенаправлялись на новый адрес.
+ALTER TABLE "News" ADD COLUMN IF NOT EXISTS "Slug" TEXT;
+ALTER TABLE "Categories" ADD COLUMN IF NOT EXISTS "Slug" TEXT;
+
+CREATE TABLE IF NOT EXISTS "NewsSlugHistory" (
+    "Slug" TEXT PRIMARY KEY,
+    "NewsId" BIGINT NOT NULL REFERENCES "News"("Id") ON DELETE CASCADE,
+    "CreatedAt" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS "CategorySlugHistory" (
+    "Slug" TEXT PRIMARY KEY,
+    "CategoryId" BIGINT NOT NULL REFERENCES "Categories"("Id") ON DELETE CASCADE,
+    "CreatedAt" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_news_slug_history_news_id ON "NewsSlugHistory"("NewsId");
+CREATE INDEX IF NOT EXISTS idx_category_slug_history_category_id ON "CategorySlugHistory"("CategoryId");
+
+CREATE OR REPLACE FUNCTION slugify(value TEXT, fallback TEXT) RETURNS TEXT AS $$
+DECLARE
+    s TEXT := lower(coalesce(value, ''));
+BEGIN
+    s := replace(s, 'щ', 'shch');
+    s := replace(s, 'ж', 'zh');
+    s := replace(s, 'х', 'kh');
+    s := replace(s, 'ц', 'ts');
+    s := replace(s, 'ч', 'ch');
+    s := replace(s, 'ш', 'sh');
+    s := replace(s, 'ю', 'yu');
+    s := replace(s, 'я', 'ya');
+    s := replace(s, 'ї', 'yi');
+    s := replace(s, 'є', 'ye');
+    -- символы без пары в translate удаляются
+    s := translate(s, 'абвгдеёзийклмнопрстуфыэіґъь''’', 'abvgdeeziyklmnoprstufyeig');
+    s := btrim(regexp_replace(s, '[^a-z0-9]+', '-', 'g'), '-');
+    IF length(s) > 80 THEN
+        s := left(s, 80);
+        IF strpos(substr(s, 42), '-') > 0 THEN
+            s := regexp_replace(s, '-[^-]*$', '');
+        END IF;
+        s := btrim(s, '-');
+    END IF;
+    IF s = '' THEN
+        RETURN fallback;
+    END IF;
+    RETURN s;
+END;
+$$ LANGUAGE plpgsql IMMUTABLE;
+
+-- Свободный slug: base или base-2, base-3... не занятый ни текущими, ни прежними slug
+CREATE OR REPLACE FUNCTION news_free_slug(base TEXT) RETURNS TEXT AS $$
+DECLARE
+    candidate TEXT := base;
+    n INT := 1;
+BEGIN
+    WHILE EXISTS (SELECT 1 FROM "News" WHERE "Slug" = candidate)
+       OR EXISTS (SELECT 1 FROM "NewsSlugHistory" WHERE "Slug" = candidate) LOOP
+        n := n + 1;
+        candidate := base || '-' || n;
+    END LOOP;
+    RETURN candidate;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION category_free_slug(base TEXT) RETURNS TEXT AS $$
+DECLARE
+    candidate TEXT := base;
+    n INT := 1;
+BEGIN
+    WHILE EXISTS (SELECT 1 FROM "Categories" WHERE "Slug" = candidate)
+       OR EXISTS (SELECT 1 FROM "CategorySlugHistory" WHERE "Slug" = candidate) LOOP
+        n := n + 1;
+        candidate := base || '-' || n;
+    END LOOP;
+    RETURN candidate;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION news_slug_default() RETURNS trigger AS $$
+BEGIN
+    IF NEW."Slug" IS NULL OR NEW."Slug" = '' THEN
+        NEW."Slug" := news_free_slug(slugify(NEW."Title", 'news'));
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION category_slug_default() RETURNS trigger AS $$
+BEGIN
+    IF NEW."Slug" IS NULL OR NEW."Slug" = '' THEN
+        NEW."Slug" := category_free_slug(slugify(NEW."Name", 'category'));
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS news_slug_default ON "News";
+CREATE TRIGGER news_slug_default
+    BEFORE INSERT ON "News"
+    FOR EACH ROW EXECUTE FUNCTION news_slug_default();
+
+DROP TRIGGER IF EXISTS category_slug_default ON "Categories";
+CREATE TRIGGER category_slug_default
+    BEFORE INSERT ON "Categories"
+    FOR EACH ROW EXECUTE FUNCTION category_slug_default();
+
+-- Существующие строки получают slug по одной в порядке ID: при совпадении slug без суффикса остается у более старой
+DO $$
+DECLARE
+    r RECORD;
+BEGIN
+    FOR r IN SELECT "Id", "Title" FROM "News" WHERE "Slug" IS NULL ORDER BY "Id" LOOP
+        UPDATE "News" SET "Slug" = news_free_slug(slugify(r."Title", 'news')) WHERE "Id" = r."Id";
+    END LOOP;
+
+    FOR r IN SELECT "Id", "Name" FROM "Categories" WHERE "Slug" IS NULL ORDER BY "Id" LOOP
+        UPDATE "Categories" SET "Slug" = category_free_slug(slugify(r."Name", 'category')) WHERE "Id" = r."Id";
+    END LOOP;
+END;
+$$;
+
+ALTER TABLE "News" ALTER COLUMN "Slug" SET NOT NULL;
+ALTER TABLE "Categories" ALTER COLUMN "Slug" SET NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_news_slug ON "News"("Slug");
+CREATE UNIQUE INDEX IF NOT EXISTS idx_categories_slug ON "Categories"("Slug");
@@ -0,0 +1,16 @@
+-- Иерархия категорий: родитель и позиция среди соседних категорий (с 0, без пропусков).
+-- Приложение при удалении категории переносит ее дочерние категории к ее родителю, ON DELETE SET NULL - на случай удаления в обход приложения.
+ALTER TABLE "Categories"
+    ADD COLUMN IF NOT EXISTS "ParentId" BIGINT REFERENCES "Categories"("Id") ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS "Position" INT NOT NULL DEFAULT 0;
+
+ALTER TABLE "Categories" DROP CONSTRAINT IF EXISTS categories_parent_check;
+ALTER TABLE "Categories" ADD CONSTRAINT categories_parent_check CHECK ("ParentId" <> "Id");
+
+CREATE INDEX IF NOT EXISTS idx_categories_parent_position ON "Categories"("ParentId", "Position");
+
+-- Существующие категории становятся корневыми в порядке названий
+UPDATE "Categories" c
+SET "Position" = o.position
+FROM (SELECT "Id", row_number() OVER (ORDER BY "Name") - 1 AS position FROM "Categories" WHERE "ParentId" IS NULL) o
+WHERE c."Id" = o."Id";
@@ -0,0 +1,17 @@
+-- Свободные теги новостей в дополнение к категориям. Названия хранятся нормализованными
+-- (нижний регистр, без "#" в начале и лишних пробелов), поэтому уникальны без учета регистра.
+CREATE TABLE IF NOT EXISTS "Tags" (
+    "Id" BIGSERIAL PRIMARY KEY,
+    "Name" TEXT NOT NULL UNIQUE,
+    "CreatedAt" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS "NewsTags" (
+    "NewsId" BIGINT NOT NULL REFERENCES "News"("Id") ON DELETE CASCADE,
+    "TagId" BIGINT NOT NULL REFERENCES "Tags"("Id") ON DELETE CASCADE,
+    PRIMARY KEY ("NewsId", "TagId")
+);
+
+CREATE INDEX IF NOT EXISTS idx_news_tags_tag_id ON "NewsTags"("TagId");
+-- Поиск тегов по префиксу (LIKE 'prefix%') независимо от правил сортировки базы
+CREATE INDEX IF NOT EXISTS idx_tags_name_prefix ON "Tags"("Name" text_pattern_ops);
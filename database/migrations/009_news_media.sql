@@ -0,0 +1,21 @@
+-- Изображения новостей. Файлы лежат в хранилище (локальный каталог или S3), здесь - их ключи и свойства.
+-- "Position" - порядок изображений новости с 0, обложкой может быть не больше одного изображения.
+CREATE TABLE IF NOT EXISTS "NewsMedia" (
+    "Id" BIGSERIAL PRIMARY KEY,
+    "NewsId" BIGINT NOT NULL REFERENCES "News"("Id") ON DELETE CASCADE,
+    "StorageKey" TEXT NOT NULL,
+    "ThumbnailKey" TEXT NOT NULL,
+    "ContentType" TEXT NOT NULL,
+    "Size" BIGINT NOT NULL,
+    "Width" INT NOT NULL,
+    "Height" INT NOT NULL,
+    "ThumbnailWidth" INT NOT NULL,
+    "ThumbnailHeight" INT NOT NULL,
+    "Alt" TEXT NOT NULL DEFAULT '',
+    "Position" INT NOT NULL DEFAULT 0,
+    "IsCover" BOOLEAN NOT NULL DEFAULT FALSE,
+    "CreatedAt" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_news_media_news_position ON "NewsMedia"("NewsId", "Position");
+CREATE UNIQUE INDEX IF NOT EXISTS idx_news_media_cover ON "NewsMedia"("NewsId") WHERE "IsCover";
@@ -0,0 +1,24 @@
+-- Переводы новостей и категорий. Исходные "News" и "Categories" хранят язык по умолчанию
+-- (первый в LOCALES), здесь - остальные языки. Locale - нормализованный тег вида "en" или "en-GB".
+CREATE TABLE IF NOT EXISTS "NewsTranslations" (
+    "NewsId" BIGINT NOT NULL REFERENCES "News"("Id") ON DELETE CASCADE,
+    "Locale" VARCHAR(35) NOT NULL,
+    "Title" VARCHAR(255) NOT NULL,
+    "Content" TEXT NOT NULL,
+    "ContentFormat" TEXT NOT NULL DEFAULT 'html' CHECK ("ContentFormat" IN ('html', 'markdown')),
+    "ContentSource" TEXT NOT NULL DEFAULT '',
+    "ContentText" TEXT NOT NULL DEFAULT '',
+    "CreatedAt" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "UpdatedAt" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("NewsId", "Locale")
+);
+
+CREATE TABLE IF NOT EXISTS "CategoryTranslations" (
+    "CategoryId" BIGINT NOT NULL REFERENCES "Categories"("Id") ON DELETE CASCADE,
+    "Locale" VARCHAR(35) NOT NULL,
+    "Name" VARCHAR(100) NOT NULL,
+    "Description" TEXT NOT NULL DEFAULT '',
+    "CreatedAt" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "UpdatedAt" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("CategoryId", "Locale")
+);
@@ -0,0 +1,5 @@
+-- Похожие новости: сходство заголовков считается по триграммам (pg_trgm), поэтому
+-- "матч" и "матча" близки без словарей морфологии. GIN индекс ускоряет поиск кандидатов по оператору %.
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS idx_news_title_trgm ON "News" USING gin ("Title" gin_trgm_ops);
@@ -0,0 +1,16 @@
+-- Просмотры новостей. Сервер собирает просмотры в памяти и записывает их пачками.
+-- "NewsViews" - просмотры по часам для популярного за период, часы старше самого длинного периода удаляются;
+-- "NewsViewTotals" - просмотры за все время.
+CREATE TABLE IF NOT EXISTS "NewsViews" (
+    "NewsId" BIGINT NOT NULL REFERENCES "News"("Id") ON DELETE CASCADE,
+    "Hour" TIMESTAMPTZ NOT NULL,
+    "Count" BIGINT NOT NULL,
+    PRIMARY KEY ("NewsId", "Hour")
+);
+
+CREATE INDEX IF NOT EXISTS idx_news_views_hour ON "NewsViews"("Hour");
+
+CREATE TABLE IF NOT EXISTS "NewsViewTotals" (
+    "NewsId" BIGINT PRIMARY KEY REFERENCES "News"("Id") ON DELETE CASCADE,
+    "Views" BIGINT NOT NULL
+);
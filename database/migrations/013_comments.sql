@@ -0,0 +1,28 @@
+-- Комментарии читателей к новостям. "ParentId" - комментарий, на который дан ответ.
+-- Читателям видны только одобренные комментарии ("Status" = 'approved'), остальные ждут модерации или отклонены.
+-- "UserId" и "UserName" - идентификатор и имя читателя из JWT.
+CREATE TABLE IF NOT EXISTS "Comments" (
+    "Id" BIGSERIAL PRIMARY KEY,
+    "NewsId" BIGINT NOT NULL REFERENCES "News"("Id") ON DELETE CASCADE,
+    "ParentId" BIGINT REFERENCES "Comments"("Id") ON DELETE CASCADE,
+    "UserId" TEXT NOT NULL,
+    "UserName" TEXT NOT NULL DEFAULT '',
+    "Content" TEXT NOT NULL,
+    "Status" VARCHAR(16) NOT NULL DEFAULT 'pending' CHECK ("Status" IN ('pending', 'approved', 'rejected')),
+    "FilterReason" VARCHAR(32) NOT NULL DEFAULT '',
+    "CreatedAt" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "ModeratedAt" TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_comments_news_roots ON "Comments"("NewsId", "Id" DESC) WHERE "ParentId" IS NULL AND "Status" = 'approved';
+CREATE INDEX IF NOT EXISTS idx_comments_parent ON "Comments"("ParentId");
+CREATE INDEX IF NOT EXISTS idx_comments_status ON "Comments"("Status", "Id");
+CREATE INDEX IF NOT EXISTS idx_comments_user_created ON "Comments"("UserId", "CreatedAt");
+
+-- Читатели, которым запрещено комментировать
+CREATE TABLE IF NOT EXISTS "CommentBans" (
+    "UserId" TEXT PRIMARY KEY,
+    "Reason" TEXT NOT NULL DEFAULT '',
+    "CommentId" BIGINT REFERENCES "Comments"("Id") ON DELETE SET NULL,
+    "CreatedAt" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,81 @@
+// Package migrations содержит SQL миграции схемы, применяемые поверх database/schema.sql.
+package migrations
+
+import (
+	"context"
+	"embed"
+	"sort"
+
+	"go.uber.org/zap"
+	"gopkg.in/reform.v1"
+)
+
+//go:embed *.sql
+var files embed.FS
+
+// lockID - ключ advisory lock, чтобы миграции не применялись одновременно несколькими экземплярами
+const lockID = 7243001
+
+// Apply применяет еще не примененные миграции в порядке имен файлов.
+// Каждая миграция выполняется в отдельной транзакции и записывается в таблицу "SchemaMigrations".
+func Apply(ctx context.Context, db *reform.DB, logger *zap.SugaredLogger) error {
+	_, err := db.WithContext(ctx).Exec(`
+		CREATE TABLE IF NOT EXISTS "SchemaMigrations" (
+			"Version" VARCHAR(255) PRIMARY KEY,
+			"AppliedAt" TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`)
+	if err != nil {
+		return err
+	}
+
+	entries, err := files.ReadDir(".")
+	if err != nil {
+		return err
+	}
+
+	versions := make([]string, 0, len(entries))
+	for _, e := range entries {
+		versions = append(versions, e.Name())
+	}
+	sort.Strings(versions)
+
+	for _, version := range versions {
+		content, err := files.ReadFile(version)
+		if err != nil {
+			return err
+		}
+
+		var applied bool
+		err = db.InTransactionContext(ctx, nil, func(tx *reform.TX) error {
+			if _, err := tx.Exec(`SELECT pg_advisory_xact_lock($1)`, lockID); err != nil {
+				return err
+			}
+
+			var exists bool
+			err := tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM "SchemaMigrations" WHERE "Version" = $1)`, version).Scan(&exists)
+			if err != nil || exists {
+				return err
+			}
+
+			if _, err := tx.Exec(string(content)); err != nil {
+				return err
+			}
+			if _, err := tx.Exec(`INSERT INTO "SchemaMigrations" ("Version") VALUES ($1)`, version); err != nil {
+				return err
+			}
+
+			applied = true
+			return nil
+		})
+		if err != nil {
+			logger.Errorw("Failed to apply migration", "version", version, "error", err)
+			return err
+		}
+
+		if applied {
+			logger.Infow("Migration applied", "version", version)
+		}
+	}
+
+	return nil
+}
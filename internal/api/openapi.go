@@ -0,0 +1,846 @@
+// Package api описывает HTTP API сервера документом OpenAPI. Обработчики и DTO версий API
+// находятся в пакетах internal/api/vN.
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	v1 "go_news_server/internal/api/v1"
+	"go_news_server/internal/handlers"
+	"go_news_server/internal/models"
+	"go_news_server/internal/services"
+	"go_news_server/pkg/logging"
+	"go_news_server/pkg/openapi"
+)
+
+// KeyAuth - схема авторизации закрытых маршрутов: Authorization: Bearer <SECRET_KEY>
+const KeyAuth = "keyauth"
+
+// ReaderAuth - схема авторизации читателя: Authorization: Bearer <JWT, подписанный READER_JWT_SECRET>
+const ReaderAuth = "readerauth"
+
+// Теги операций
+const (
+	tagNews         = "news"
+	tagCategories   = "categories"
+	tagTags         = "tags"
+	tagViews        = "views"
+	tagComments     = "comments"
+	tagMedia        = "media"
+	tagTranslations = "translations"
+	tagWebhooks     = "webhooks"
+	tagImport       = "import"
+	tagFeeds        = "feeds"
+	tagLegacy       = "legacy"
+	tagDebug        = "debug"
+	tagDocs         = "docs"
+)
+
+// OpenAPI строит описание всех маршрутов сервера: /api/v1, устаревшие маршруты без версии, комментарии,
+// изображения, переводы, вебхуки, импорт, поток событий, ленты и sitemap, закрытые маршруты отладки.
+// serverURL - адрес сервера в servers, пустой - не указывается.
+func OpenAPI(serverURL string) *openapi.Document {
+	doc := openapi.New(openapi.Info{
+		Title:   "Go News Server API",
+		Version: "1.0.0",
+		Description: "Новые клиенты используют /api/v1. Маршруты без версии помечены deprecated и отвечают " +
+			"с заголовками Deprecation и Link rel=\"successor-version\". Ошибки возвращаются в формате RFC 7807.",
+	})
+	if serverURL != "" {
+		doc.Servers = []openapi.Server{{URL: serverURL}}
+	}
+	doc.Tags = []openapi.Tag{
+		{Name: tagNews, Description: "Новости"},
+		{Name: tagCategories, Description: "Категории"},
+		{Name: tagTags, Description: "Теги"},
+		{Name: tagViews, Description: "Просмотры"},
+		{Name: tagComments, Description: "Комментарии читателей и модерация"},
+		{Name: tagMedia, Description: "Изображения новостей"},
+		{Name: tagTranslations, Description: "Переводы новостей и категорий"},
+		{Name: tagWebhooks, Description: "Вебхуки"},
+		{Name: tagImport, Description: "Импорт и экспорт новостей"},
+		{Name: tagFeeds, Description: "Поток событий, ленты RSS и Atom, sitemap"},
+		{Name: tagLegacy, Description: "Маршруты без версии"},
+		{Name: tagDebug, Description: "Отладка"},
+		{Name: tagDocs, Description: "Описание API"},
+	}
+	doc.Components.SecuritySchemes[KeyAuth] = openapi.SecurityScheme{
+		Type:        "http",
+		Scheme:      "bearer",
+		Description: "Значение SECRET_KEY сервера",
+	}
+	doc.Components.SecuritySchemes[ReaderAuth] = openapi.SecurityScheme{
+		Type:        "http",
+		Scheme:      "bearer",
+		Description: "JWT читателя, подписанный READER_JWT_SECRET (HS256): sub - ID читателя, name - имя",
+	}
+
+	// модели маршрутов без версии называются с префиксом Legacy, чтобы не совпадать с DTO /api/v1
+	doc.Register("LegacyNews", models.News{})
+	doc.Register("LegacyCategory", models.Category{})
+	doc.Register("LegacyCategoryNode", models.CategoryNode{})
+	doc.Register("LegacyTag", models.Tag{})
+	doc.Register("LegacyCategoryMoveRequest", models.CategoryMoveRequest{})
+
+	s := &spec{doc: doc}
+	s.newsV1()
+	s.categoriesV1()
+	s.legacyNews()
+	s.legacyCategories()
+	s.views()
+	s.comments()
+	s.media()
+	s.translations()
+	s.webhooks()
+	s.newsImport()
+	s.feeds()
+	s.debug()
+	s.docs()
+	return doc
+}
+
+type spec struct {
+	doc *openapi.Document
+}
+
+// add добавляет операцию с ответом об ошибке по умолчанию
+func (s *spec) add(method, path string, op *openapi.Operation) {
+	op.Responses["default"] = openapi.Response{
+		Description: "Ошибка",
+		Content:     map[string]openapi.MediaType{handlers.ProblemContentType: {Schema: s.doc.Schema(models.Problem{})}},
+	}
+	s.doc.Add(method, path, op)
+}
+
+func (s *spec) newsV1() {
+	news := s.doc.Schema(v1.Response[[]v1.News]{})
+	newsUpdate := body(s.doc.Schema(v1.NewsUpdateRequest{}))
+
+	s.add(http.MethodGet, "/api/v1/news", &openapi.Operation{
+		Tags: []string{tagNews}, OperationID: "listNews", Summary: "Страница новостей",
+		Description: "Новости в порядке ID. cursor и offset вместе не задаются.",
+		Parameters:  []openapi.Parameter{limit(10, v1.MaxPageLimit), cursor(), offset(), categoryID(), tag(), format(false), lang()},
+		Responses:   ok("Новости, meta.next_cursor - следующая страница", news),
+	})
+	s.add(http.MethodGet, "/api/v1/news/popular", &openapi.Operation{
+		Tags: []string{tagNews}, OperationID: "listPopularNews", Summary: "Популярные новости за период",
+		Parameters: []openapi.Parameter{window(), categoryID(), limit(10, services.MaxPopularLimit), format(false), lang()},
+		Responses:  ok("Новости по убыванию числа просмотров", news),
+	})
+	s.add(http.MethodGet, "/api/v1/news/by-slug/:slug", &openapi.Operation{
+		Tags: []string{tagNews}, OperationID: "getNewsBySlug", Summary: "Новость по slug",
+		Parameters: []openapi.Parameter{slug(), format(false), lang()},
+		Responses:  withRedirect(ok("Новость", s.doc.Schema(v1.Response[v1.News]{}))),
+	})
+	s.add(http.MethodGet, "/api/v1/news/:id/related", &openapi.Operation{
+		Tags: []string{tagNews}, OperationID: "listRelatedNews", Summary: "Похожие новости",
+		Parameters: []openapi.Parameter{id("ID новости"), limit(5, services.MaxRelatedLimit), format(false), lang()},
+		Responses:  ok("Новости", news),
+	})
+	s.add(http.MethodGet, "/api/v1/news/:id/categories", &openapi.Operation{
+		Tags: []string{tagNews}, OperationID: "listNewsCategories", Summary: "Категории новости",
+		Parameters: []openapi.Parameter{id("ID новости"), lang()},
+		Responses:  ok("Категории", s.doc.Schema(v1.Response[[]v1.Category]{})),
+	})
+	s.add(http.MethodPatch, "/api/v1/news/:id", &openapi.Operation{
+		Tags: []string{tagNews}, OperationID: "updateNews", Summary: "Изменение новости",
+		Description: "Незаданные поля не меняются. HTML очищается по строгому списку разрешенных тегов.",
+		Parameters:  []openapi.Parameter{id("ID новости")},
+		RequestBody: newsUpdate,
+		Responses:   noContent("Новость изменена"),
+	})
+
+	s.add(http.MethodGet, "/api/v1/private/news", &openapi.Operation{
+		Tags: []string{tagNews}, OperationID: "listPrivateNews", Summary: "Страница новостей с исходным Markdown",
+		Description: "Новости в порядке ID. cursor и offset вместе не задаются.",
+		Parameters:  []openapi.Parameter{limit(10, v1.MaxPageLimit), cursor(), offset(), categoryID(), tag(), format(true), lang()},
+		Responses:   ok("Новости, meta.next_cursor - следующая страница", news),
+		Security:    keyAuth(),
+	})
+	s.add(http.MethodGet, "/api/v1/private/news/popular", &openapi.Operation{
+		Tags: []string{tagNews}, OperationID: "listPrivatePopularNews", Summary: "Популярные новости с числом просмотров",
+		Parameters: []openapi.Parameter{window(), categoryID(), limit(10, services.MaxPopularLimit), format(true), lang()},
+		Responses:  ok("Новости с views", news),
+		Security:   keyAuth(),
+	})
+	s.add(http.MethodPatch, "/api/v1/private/news/:id", &openapi.Operation{
+		Tags: []string{tagNews}, OperationID: "updatePrivateNews", Summary: "Изменение новости редактором",
+		Description: "Незаданные поля не меняются. HTML очищается по мягкому списку разрешенных тегов.",
+		Parameters:  []openapi.Parameter{id("ID новости")},
+		RequestBody: newsUpdate,
+		Responses:   noContent("Новость изменена"),
+		Security:    keyAuth(),
+	})
+}
+
+func (s *spec) categoriesV1() {
+	category := s.doc.Schema(v1.Response[v1.Category]{})
+	categories := s.doc.Schema(v1.Response[[]v1.Category]{})
+	categoryRequest := body(s.doc.Schema(v1.CategoryRequest{}))
+
+	s.add(http.MethodGet, "/api/v1/categories", &openapi.Operation{
+		Tags: []string{tagCategories}, OperationID: "listCategories", Summary: "Страница категорий",
+		Parameters: []openapi.Parameter{limit(10, v1.MaxPageLimit), offset(), lang()},
+		Responses:  ok("Категории, meta.total - общее число категорий", categories),
+	})
+	s.add(http.MethodPost, "/api/v1/categories", &openapi.Operation{
+		Tags: []string{tagCategories}, OperationID: "createCategory", Summary: "Создание категории",
+		RequestBody: categoryRequest,
+		Responses: map[string]openapi.Response{strconv.Itoa(http.StatusCreated): {
+			Description: "Категория создана",
+			Headers:     map[string]openapi.Header{"Location": {Description: "Адрес категории", Schema: openapi.String()}},
+			Content:     jsonContent(category),
+		}},
+	})
+	s.add(http.MethodGet, "/api/v1/categories/tree", &openapi.Operation{
+		Tags: []string{tagCategories}, OperationID: "getCategoryTree", Summary: "Дерево категорий",
+		Parameters: []openapi.Parameter{lang()},
+		Responses:  ok("Корневые категории с дочерними", s.doc.Schema(v1.Response[[]v1.CategoryNode]{})),
+	})
+	s.add(http.MethodGet, "/api/v1/categories/by-slug/:slug", &openapi.Operation{
+		Tags: []string{tagCategories}, OperationID: "getCategoryBySlug", Summary: "Категория по slug",
+		Parameters: []openapi.Parameter{slug(), lang()},
+		Responses:  withRedirect(ok("Категория", category)),
+	})
+	s.add(http.MethodGet, "/api/v1/categories/:id", &openapi.Operation{
+		Tags: []string{tagCategories}, OperationID: "getCategory", Summary: "Категория по ID",
+		Parameters: []openapi.Parameter{id("ID категории"), lang()},
+		Responses:  ok("Категория", category),
+	})
+	s.add(http.MethodPut, "/api/v1/categories/:id", &openapi.Operation{
+		Tags: []string{tagCategories}, OperationID: "updateCategory", Summary: "Изменение категории",
+		Parameters:  []openapi.Parameter{id("ID категории")},
+		RequestBody: categoryRequest,
+		Responses:   ok("Категория", category),
+	})
+	s.add(http.MethodDelete, "/api/v1/categories/:id", &openapi.Operation{
+		Tags: []string{tagCategories}, OperationID: "deleteCategory", Summary: "Удаление категории",
+		Parameters: []openapi.Parameter{id("ID категории")},
+		Responses:  noContent("Категория удалена"),
+	})
+	s.add(http.MethodGet, "/api/v1/categories/:id/descendants", &openapi.Operation{
+		Tags: []string{tagCategories}, OperationID: "listCategoryDescendants", Summary: "Все потомки категории",
+		Parameters: []openapi.Parameter{id("ID категории"), lang()},
+		Responses:  ok("Категории", categories),
+	})
+	s.add(http.MethodPut, "/api/v1/categories/:id/move", &openapi.Operation{
+		Tags: []string{tagCategories}, OperationID: "moveCategory", Summary: "Перенос и изменение позиции категории",
+		Parameters:  []openapi.Parameter{id("ID категории")},
+		RequestBody: body(s.doc.Schema(v1.CategoryMoveRequest{})),
+		Responses:   ok("Категория", category),
+	})
+
+	s.add(http.MethodGet, "/api/v1/tags", &openapi.Operation{
+		Tags: []string{tagTags}, OperationID: "listTags", Summary: "Теги с числом новостей",
+		Parameters: []openapi.Parameter{limit(50, 200), prefix()},
+		Responses:  ok("Теги", s.doc.Schema(v1.Response[[]v1.Tag]{})),
+	})
+}
+
+func (s *spec) legacyNews() {
+	newsList := openapi.Object(map[string]*openapi.Schema{"Success": openapi.Boolean(), "News": openapi.Array(s.doc.Schema(models.News{}))})
+	success := openapi.Object(map[string]*openapi.Schema{"Success": openapi.Boolean()})
+	edit := body(s.doc.Schema(models.NewsEditRequest{}))
+
+	s.add(http.MethodGet, "/list", &openapi.Operation{
+		Tags: []string{tagLegacy}, OperationID: "legacyListNews", Summary: "Список новостей", Deprecated: true,
+		Description: successor("GET /api/v1/news"),
+		Parameters:  []openapi.Parameter{limit(10, 0), offset(), categoryID(), tag(), format(false), lang()},
+		Responses:   ok("Новости", newsList),
+	})
+	s.add(http.MethodGet, "/news/by-slug/:slug", &openapi.Operation{
+		Tags: []string{tagLegacy}, OperationID: "legacyGetNewsBySlug", Summary: "Новость по slug", Deprecated: true,
+		Description: successor("GET /api/v1/news/by-slug/{slug}"),
+		Parameters:  []openapi.Parameter{slug(), format(false), lang()},
+		Responses: withRedirect(ok("Новость", openapi.Object(map[string]*openapi.Schema{
+			"Success": openapi.Boolean(),
+			"News":    s.doc.Schema(models.News{}),
+		}))),
+	})
+	s.add(http.MethodGet, "/news/:id/related", &openapi.Operation{
+		Tags: []string{tagLegacy}, OperationID: "legacyListRelatedNews", Summary: "Похожие новости", Deprecated: true,
+		Description: successor("GET /api/v1/news/{id}/related"),
+		Parameters:  []openapi.Parameter{id("ID новости"), limit(5, services.MaxRelatedLimit), format(false), lang()},
+		Responses:   ok("Новости", newsList),
+	})
+	s.add(http.MethodPost, "/edit/:Id", &openapi.Operation{
+		Tags: []string{tagLegacy}, OperationID: "legacyEditNews", Summary: "Изменение новости", Deprecated: true,
+		Description: successor("PATCH /api/v1/news/{id}"),
+		Parameters:  []openapi.Parameter{id("ID новости", "Id")},
+		RequestBody: edit,
+		Responses:   ok("Новость изменена", success),
+	})
+
+	s.add(http.MethodGet, "/private/list", &openapi.Operation{
+		Tags: []string{tagLegacy}, OperationID: "legacyListPrivateNews", Summary: "Список новостей с исходным Markdown", Deprecated: true,
+		Description: successor("GET /api/v1/private/news"),
+		Parameters:  []openapi.Parameter{limit(10, 0), offset(), categoryID(), tag(), format(true), lang()},
+		Responses:   ok("Новости", newsList),
+		Security:    keyAuth(),
+	})
+	s.add(http.MethodPost, "/private/edit/:Id", &openapi.Operation{
+		Tags: []string{tagLegacy}, OperationID: "legacyEditPrivateNews", Summary: "Изменение новости редактором", Deprecated: true,
+		Description: successor("PATCH /api/v1/private/news/{id}"),
+		Parameters:  []openapi.Parameter{id("ID новости", "Id")},
+		RequestBody: edit,
+		Responses:   ok("Новость изменена", success),
+		Security:    keyAuth(),
+	})
+}
+
+func (s *spec) legacyCategories() {
+	category := s.doc.Schema(models.CategoryResponse{})
+	categories := s.doc.Schema(models.CategoriesResponse{})
+
+	s.add(http.MethodPost, "/categories", &openapi.Operation{
+		Tags: []string{tagLegacy}, OperationID: "legacyCreateCategory", Summary: "Создание категории", Deprecated: true,
+		Description: successor("POST /api/v1/categories"),
+		RequestBody: body(s.doc.Schema(models.CategoryCreateRequest{})),
+		Responses:   map[string]openapi.Response{strconv.Itoa(http.StatusCreated): {Description: "Категория создана", Content: jsonContent(category)}},
+	})
+	s.add(http.MethodGet, "/categories", &openapi.Operation{
+		Tags: []string{tagLegacy}, OperationID: "legacyListCategories", Summary: "Список категорий", Deprecated: true,
+		Description: successor("GET /api/v1/categories"),
+		Parameters:  []openapi.Parameter{limit(10, 0), offset(), lang()},
+		Responses:   ok("Категории", categories),
+	})
+	s.add(http.MethodGet, "/categories/tree", &openapi.Operation{
+		Tags: []string{tagLegacy}, OperationID: "legacyGetCategoryTree", Summary: "Дерево категорий", Deprecated: true,
+		Description: successor("GET /api/v1/categories/tree"),
+		Parameters:  []openapi.Parameter{lang()},
+		Responses:   ok("Дерево категорий", s.doc.Schema(models.CategoryTreeResponse{})),
+	})
+	s.add(http.MethodGet, "/categories/by-slug/:slug", &openapi.Operation{
+		Tags: []string{tagLegacy}, OperationID: "legacyGetCategoryBySlug", Summary: "Категория по slug", Deprecated: true,
+		Description: successor("GET /api/v1/categories/by-slug/{slug}"),
+		Parameters:  []openapi.Parameter{slug(), lang()},
+		Responses:   withRedirect(ok("Категория", category)),
+	})
+	s.add(http.MethodGet, "/categories/:id", &openapi.Operation{
+		Tags: []string{tagLegacy}, OperationID: "legacyGetCategory", Summary: "Категория по ID", Deprecated: true,
+		Description: successor("GET /api/v1/categories/{id}"),
+		Parameters:  []openapi.Parameter{id("ID категории"), lang()},
+		Responses:   ok("Категория", category),
+	})
+	s.add(http.MethodPut, "/categories/:id", &openapi.Operation{
+		Tags: []string{tagLegacy}, OperationID: "legacyUpdateCategory", Summary: "Изменение категории", Deprecated: true,
+		Description: successor("PUT /api/v1/categories/{id}"),
+		Parameters:  []openapi.Parameter{id("ID категории")},
+		RequestBody: body(s.doc.Schema(models.CategoryUpdateRequest{})),
+		Responses:   ok("Категория", category),
+	})
+	s.add(http.MethodDelete, "/categories/:id", &openapi.Operation{
+		Tags: []string{tagLegacy}, OperationID: "legacyDeleteCategory", Summary: "Удаление категории", Deprecated: true,
+		Description: successor("DELETE /api/v1/categories/{id}"),
+		Parameters:  []openapi.Parameter{id("ID категории")},
+		Responses:   deleted("Категория удалена"),
+	})
+	s.add(http.MethodGet, "/categories/:id/descendants", &openapi.Operation{
+		Tags: []string{tagLegacy}, OperationID: "legacyListCategoryDescendants", Summary: "Все потомки категории", Deprecated: true,
+		Description: successor("GET /api/v1/categories/{id}/descendants"),
+		Parameters:  []openapi.Parameter{id("ID категории"), lang()},
+		Responses:   ok("Категории", categories),
+	})
+	s.add(http.MethodPut, "/categories/:id/move", &openapi.Operation{
+		Tags: []string{tagLegacy}, OperationID: "legacyMoveCategory", Summary: "Перенос и изменение позиции категории", Deprecated: true,
+		Description: successor("PUT /api/v1/categories/{id}/move"),
+		Parameters:  []openapi.Parameter{id("ID категории")},
+		RequestBody: body(s.doc.Schema(models.CategoryMoveRequest{})),
+		Responses:   ok("Категория", category),
+	})
+	s.add(http.MethodGet, "/news/:id/categories", &openapi.Operation{
+		Tags: []string{tagLegacy}, OperationID: "legacyListNewsCategories", Summary: "Категории новости", Deprecated: true,
+		Description: successor("GET /api/v1/news/{id}/categories"),
+		Parameters:  []openapi.Parameter{id("ID новости"), lang()},
+		Responses:   ok("Категории", categories),
+	})
+
+	s.add(http.MethodGet, "/tags", &openapi.Operation{
+		Tags: []string{tagLegacy}, OperationID: "legacyListTags", Summary: "Теги с числом новостей", Deprecated: true,
+		Description: successor("GET /api/v1/tags"),
+		Parameters:  []openapi.Parameter{limit(50, 0), prefix()},
+		Responses:   ok("Теги", s.doc.Schema(models.TagsResponse{})),
+	})
+}
+
+func (s *spec) views() {
+	newsList := openapi.Object(map[string]*openapi.Schema{"Success": openapi.Boolean(), "News": openapi.Array(s.doc.Schema(models.News{}))})
+
+	s.add(http.MethodPost, "/news/:id/view", &openapi.Operation{
+		Tags: []string{tagViews}, OperationID: "recordView", Summary: "Учет просмотра новости",
+		Description: "Повторные просмотры клиента в пределах окна не учитываются.",
+		Parameters:  []openapi.Parameter{id("ID новости")},
+		Responses: map[string]openapi.Response{strconv.Itoa(http.StatusAccepted): {
+			Description: "Просмотр принят",
+			Content:     jsonContent(openapi.Object(map[string]*openapi.Schema{"success": openapi.Boolean(), "counted": openapi.Boolean()})),
+		}},
+	})
+	s.add(http.MethodGet, "/news/popular", &openapi.Operation{
+		Tags: []string{tagLegacy}, OperationID: "legacyListPopularNews", Summary: "Популярные новости за период", Deprecated: true,
+		Description: successor("GET /api/v1/news/popular"),
+		Parameters:  []openapi.Parameter{window(), categoryID(), limit(10, services.MaxPopularLimit), format(false), lang()},
+		Responses:   ok("Новости", newsList),
+	})
+	s.add(http.MethodGet, "/private/news/popular", &openapi.Operation{
+		Tags: []string{tagLegacy}, OperationID: "legacyListPrivatePopularNews", Summary: "Популярные новости с числом просмотров", Deprecated: true,
+		Description: successor("GET /api/v1/private/news/popular"),
+		Parameters:  []openapi.Parameter{window(), categoryID(), limit(10, services.MaxPopularLimit), format(true), lang()},
+		Responses:   ok("Новости", newsList),
+		Security:    keyAuth(),
+	})
+	s.add(http.MethodGet, "/private/news/:id/views", &openapi.Operation{
+		Tags: []string{tagViews}, OperationID: "getNewsViews", Summary: "Счетчики просмотров новости",
+		Parameters: []openapi.Parameter{id("ID новости")},
+		Responses:  ok("Просмотры за периоды и за все время", s.doc.Schema(models.NewsViewsResponse{})),
+		Security:   keyAuth(),
+	})
+}
+
+func (s *spec) comments() {
+	comment := s.doc.Schema(models.CommentResponse{})
+	comments := s.doc.Schema(models.CommentsResponse{})
+
+	s.add(http.MethodGet, "/news/:id/comments", &openapi.Operation{
+		Tags: []string{tagComments}, OperationID: "listComments", Summary: "Ветки одобренных комментариев новости",
+		Description: "Комментарии верхнего уровня от новых к старым с ответами в replies.",
+		Parameters:  []openapi.Parameter{id("ID новости"), cursor(), limit(20, 0)},
+		Responses:   ok("Комментарии, next_cursor - следующая страница", comments),
+	})
+	s.add(http.MethodPost, "/news/:id/comments", &openapi.Operation{
+		Tags: []string{tagComments}, OperationID: "createComment", Summary: "Комментарий читателя",
+		Description: "Маршрут есть, только если задан READER_JWT_SECRET. Комментарий, отправленный фильтром " +
+			"или премодерацией на проверку, получает статус pending и ответ 202.",
+		Parameters:  []openapi.Parameter{id("ID новости")},
+		RequestBody: body(s.doc.Schema(models.CommentRequest{})),
+		Responses: map[string]openapi.Response{
+			strconv.Itoa(http.StatusCreated):  {Description: "Комментарий опубликован", Content: jsonContent(comment)},
+			strconv.Itoa(http.StatusAccepted): {Description: "Комментарий ожидает модерации", Content: jsonContent(comment)},
+		},
+		Security: []map[string][]string{{ReaderAuth: {}}},
+	})
+
+	s.add(http.MethodGet, "/private/comments", &openapi.Operation{
+		Tags: []string{tagComments}, OperationID: "listModerationQueue", Summary: "Комментарии по статусу модерации",
+		Parameters: []openapi.Parameter{
+			{Name: "status", In: "query", Description: "Статус, по умолчанию pending", Schema: &openapi.Schema{Type: "string", Enum: []any{
+				models.CommentStatusPending, models.CommentStatusApproved, models.CommentStatusRejected,
+			}}},
+			cursor(), limit(50, 0),
+		},
+		Responses: ok("Комментарии, next_cursor - следующая страница", comments),
+		Security:  keyAuth(),
+	})
+	s.add(http.MethodPost, "/private/comments/:id/approve", &openapi.Operation{
+		Tags: []string{tagComments}, OperationID: "approveComment", Summary: "Одобрение комментария",
+		Parameters: []openapi.Parameter{id("ID комментария")},
+		Responses:  ok("Комментарий", comment),
+		Security:   keyAuth(),
+	})
+	s.add(http.MethodPost, "/private/comments/:id/reject", &openapi.Operation{
+		Tags: []string{tagComments}, OperationID: "rejectComment", Summary: "Отклонение комментария",
+		Parameters: []openapi.Parameter{id("ID комментария")},
+		Responses:  ok("Комментарий", comment),
+		Security:   keyAuth(),
+	})
+	s.add(http.MethodPost, "/private/comments/:id/ban", &openapi.Operation{
+		Tags: []string{tagComments}, OperationID: "banCommentAuthor", Summary: "Блокировка автора комментария",
+		Description: "Автор больше не может комментировать, его комментарии, ожидающие модерации, отклоняются.",
+		Parameters:  []openapi.Parameter{id("ID комментария")},
+		RequestBody: &openapi.RequestBody{Content: jsonContent(s.doc.Schema(models.CommentBanRequest{}))},
+		Responses:   ok("Автор заблокирован", s.doc.Schema(models.CommentBanResponse{})),
+		Security:    keyAuth(),
+	})
+	s.add(http.MethodDelete, "/private/comments/bans/:userId", &openapi.Operation{
+		Tags: []string{tagComments}, OperationID: "unbanCommentAuthor", Summary: "Снятие блокировки автора",
+		Parameters: []openapi.Parameter{{Name: "userId", In: "path", Description: "ID читателя из JWT", Required: true, Schema: openapi.String()}},
+		Responses:  deleted("Блокировка снята"),
+		Security:   keyAuth(),
+	})
+}
+
+func (s *spec) media() {
+	media := s.doc.Schema(models.NewsMediaResponse{})
+
+	s.add(http.MethodPost, "/private/news/:id/media", &openapi.Operation{
+		Tags: []string{tagMedia}, OperationID: "uploadNewsMedia", Summary: "Загрузка изображения новости",
+		Description: "Размер и тип файла проверяются по содержимому, миниатюра создается при загрузке.",
+		Parameters:  []openapi.Parameter{id("ID новости")},
+		RequestBody: &openapi.RequestBody{Required: true, Content: map[string]openapi.MediaType{"multipart/form-data": {
+			Schema: openapi.Object(map[string]*openapi.Schema{
+				"file":  {Type: "string", Format: "binary"},
+				"alt":   openapi.String(),
+				"cover": openapi.Boolean(),
+			}, "file"),
+		}}},
+		Responses: map[string]openapi.Response{strconv.Itoa(http.StatusCreated): {Description: "Изображение загружено", Content: jsonContent(media)}},
+		Security:  keyAuth(),
+	})
+	s.add(http.MethodPut, "/private/news/:id/media/:mediaId", &openapi.Operation{
+		Tags: []string{tagMedia}, OperationID: "updateNewsMedia", Summary: "Подпись, позиция и обложка изображения",
+		Parameters:  []openapi.Parameter{id("ID новости"), mediaID()},
+		RequestBody: body(s.doc.Schema(models.NewsMediaUpdateRequest{})),
+		Responses:   ok("Изображение", media),
+		Security:    keyAuth(),
+	})
+	s.add(http.MethodDelete, "/private/news/:id/media/:mediaId", &openapi.Operation{
+		Tags: []string{tagMedia}, OperationID: "deleteNewsMedia", Summary: "Удаление изображения",
+		Parameters: []openapi.Parameter{id("ID новости"), mediaID()},
+		Responses:  deleted("Изображение удалено"),
+		Security:   keyAuth(),
+	})
+}
+
+func (s *spec) translations() {
+	s.add(http.MethodGet, "/private/news/:id/translations", &openapi.Operation{
+		Tags: []string{tagTranslations}, OperationID: "listNewsTranslations", Summary: "Переводы новости",
+		Parameters: []openapi.Parameter{id("ID новости")},
+		Responses:  ok("Переводы", s.doc.Schema(models.NewsTranslationsResponse{})),
+		Security:   keyAuth(),
+	})
+	s.add(http.MethodPut, "/private/news/:id/translations/:locale", &openapi.Operation{
+		Tags: []string{tagTranslations}, OperationID: "putNewsTranslation", Summary: "Создание или замена перевода новости",
+		Description: "HTML очищается по мягкому списку разрешенных тегов.",
+		Parameters:  []openapi.Parameter{id("ID новости"), locale()},
+		RequestBody: body(s.doc.Schema(models.NewsTranslationRequest{})),
+		Responses:   ok("Перевод", s.doc.Schema(models.NewsTranslationResponse{})),
+		Security:    keyAuth(),
+	})
+	s.add(http.MethodDelete, "/private/news/:id/translations/:locale", &openapi.Operation{
+		Tags: []string{tagTranslations}, OperationID: "deleteNewsTranslation", Summary: "Удаление перевода новости",
+		Parameters: []openapi.Parameter{id("ID новости"), locale()},
+		Responses:  deleted("Перевод удален"),
+		Security:   keyAuth(),
+	})
+
+	s.add(http.MethodPut, "/categories/:id/translations/:locale", &openapi.Operation{
+		Tags: []string{tagTranslations}, OperationID: "putCategoryTranslation", Summary: "Создание или замена перевода категории",
+		Parameters:  []openapi.Parameter{id("ID категории"), locale()},
+		RequestBody: body(s.doc.Schema(models.CategoryTranslationRequest{})),
+		Responses:   ok("Перевод", s.doc.Schema(models.CategoryTranslationResponse{})),
+	})
+	s.add(http.MethodDelete, "/categories/:id/translations/:locale", &openapi.Operation{
+		Tags: []string{tagTranslations}, OperationID: "deleteCategoryTranslation", Summary: "Удаление перевода категории",
+		Parameters: []openapi.Parameter{id("ID категории"), locale()},
+		Responses:  deleted("Перевод удален"),
+	})
+}
+
+func (s *spec) webhooks() {
+	webhook := openapi.Object(map[string]*openapi.Schema{"success": openapi.Boolean(), "webhook": s.doc.Schema(models.Webhook{})})
+	webhookRequest := body(s.doc.Schema(models.WebhookRequest{}))
+
+	s.add(http.MethodPost, "/private/webhooks", &openapi.Operation{
+		Tags: []string{tagWebhooks}, OperationID: "createWebhook", Summary: "Создание подписки",
+		Description: "Секрет подписи возвращается только в этом ответе. Пустой event_types - все события, " +
+			"пустой category_ids - все категории.",
+		RequestBody: webhookRequest,
+		Responses:   map[string]openapi.Response{strconv.Itoa(http.StatusCreated): {Description: "Подписка создана", Content: jsonContent(webhook)}},
+		Security:    keyAuth(),
+	})
+	s.add(http.MethodGet, "/private/webhooks", &openapi.Operation{
+		Tags: []string{tagWebhooks}, OperationID: "listWebhooks", Summary: "Все подписки",
+		Responses: ok("Подписки", openapi.Object(map[string]*openapi.Schema{
+			"success":  openapi.Boolean(),
+			"webhooks": openapi.Array(s.doc.Schema(models.Webhook{})),
+		})),
+		Security: keyAuth(),
+	})
+	s.add(http.MethodPost, "/private/webhooks/deliveries/:id/retry", &openapi.Operation{
+		Tags: []string{tagWebhooks}, OperationID: "retryWebhookDelivery", Summary: "Повтор доставки из dead letter",
+		Parameters: []openapi.Parameter{id("ID доставки")},
+		Responses: ok("Доставка снова в очереди", openapi.Object(map[string]*openapi.Schema{
+			"success":  openapi.Boolean(),
+			"delivery": s.doc.Schema(models.WebhookDelivery{}),
+		})),
+		Security: keyAuth(),
+	})
+	s.add(http.MethodGet, "/private/webhooks/:id", &openapi.Operation{
+		Tags: []string{tagWebhooks}, OperationID: "getWebhook", Summary: "Подписка по ID",
+		Parameters: []openapi.Parameter{id("ID подписки")},
+		Responses:  ok("Подписка", webhook),
+		Security:   keyAuth(),
+	})
+	s.add(http.MethodPut, "/private/webhooks/:id", &openapi.Operation{
+		Tags: []string{tagWebhooks}, OperationID: "updateWebhook", Summary: "Изменение подписки",
+		Parameters:  []openapi.Parameter{id("ID подписки")},
+		RequestBody: webhookRequest,
+		Responses:   ok("Подписка", webhook),
+		Security:    keyAuth(),
+	})
+	s.add(http.MethodDelete, "/private/webhooks/:id", &openapi.Operation{
+		Tags: []string{tagWebhooks}, OperationID: "deleteWebhook", Summary: "Удаление подписки",
+		Parameters: []openapi.Parameter{id("ID подписки")},
+		Responses:  deleted("Подписка удалена"),
+		Security:   keyAuth(),
+	})
+	s.add(http.MethodGet, "/private/webhooks/:id/deliveries", &openapi.Operation{
+		Tags: []string{tagWebhooks}, OperationID: "listWebhookDeliveries", Summary: "Журнал доставок подписки",
+		Parameters: []openapi.Parameter{
+			id("ID подписки"),
+			{Name: "status", In: "query", Description: "Статус доставки", Schema: &openapi.Schema{Type: "string", Enum: []any{
+				models.DeliveryPending, models.DeliverySucceeded, models.DeliveryDead,
+			}}},
+			limit(50, 0), offset(),
+		},
+		Responses: ok("Доставки, total - общее число", openapi.Object(map[string]*openapi.Schema{
+			"success":    openapi.Boolean(),
+			"deliveries": openapi.Array(s.doc.Schema(models.WebhookDelivery{})),
+			"total":      openapi.Integer(),
+		})),
+		Security: keyAuth(),
+	})
+}
+
+func (s *spec) newsImport() {
+	formats := []any{models.FormatNDJSON, models.FormatCSV}
+
+	s.add(http.MethodPost, "/private/news/import", &openapi.Operation{
+		Tags: []string{tagImport}, OperationID: "importNews", Summary: "Импорт новостей из NDJSON или CSV",
+		Description: "Формат задается ?format или Content-Type. Строки с ошибками попадают в result.errors, " +
+			"остальные импортируются пакетами по batch_size строк в транзакции.",
+		Parameters: []openapi.Parameter{
+			{Name: "format", In: "query", Description: "Формат тела, иначе по Content-Type", Schema: &openapi.Schema{Type: "string", Enum: formats}},
+			{Name: "dry_run", In: "query", Description: "Проверить импорт и откатить изменения", Schema: openapi.Boolean()},
+			{Name: "create_missing_categories", In: "query", Description: "Создать категории, заданные отсутствующим названием", Schema: openapi.Boolean()},
+			{Name: "batch_size", In: "query", Description: "Строк в одной транзакции", Schema: openapi.Integer()},
+		},
+		RequestBody: &openapi.RequestBody{Required: true, Content: map[string]openapi.MediaType{
+			"application/x-ndjson": {Schema: openapi.String()},
+			"text/csv":             {Schema: openapi.String()},
+		}},
+		Responses: ok("Результат импорта", openapi.Object(map[string]*openapi.Schema{
+			"success": openapi.Boolean(),
+			"result":  s.doc.Schema(models.NewsImportResult{}),
+		})),
+		Security: keyAuth(),
+	})
+	s.add(http.MethodGet, "/private/news/export", &openapi.Operation{
+		Tags: []string{tagImport}, OperationID: "exportNews", Summary: "Выгрузка всех новостей с категориями",
+		Parameters: []openapi.Parameter{{Name: "format", In: "query", Description: "Формат, по умолчанию ndjson", Schema: &openapi.Schema{Type: "string", Enum: formats}}},
+		Responses: map[string]openapi.Response{strconv.Itoa(http.StatusOK): {
+			Description: "Файл выгрузки, пригодный для импорта",
+			Content: map[string]openapi.MediaType{
+				"application/x-ndjson": {Schema: openapi.String()},
+				"text/csv":             {Schema: openapi.String()},
+			},
+		}},
+		Security: keyAuth(),
+	})
+}
+
+func (s *spec) feeds() {
+	s.add(http.MethodGet, "/news/stream", &openapi.Operation{
+		Tags: []string{tagFeeds}, OperationID: "streamNews", Summary: "Изменения новостей и категорий (Server-Sent Events)",
+		Description: "Поток возобновляется с события после Last-Event-ID. Если события уже вытеснены из буфера, " +
+			"первым приходит событие resync.",
+		Parameters: []openapi.Parameter{
+			{Name: "category", In: "query", Description: "ID категорий через запятую", Schema: openapi.String()},
+			{Name: "Last-Event-ID", In: "header", Description: "ID последнего полученного события", Schema: openapi.String()},
+			{Name: "last_event_id", In: "query", Description: "ID последнего полученного события, если заголовок задать нельзя", Schema: openapi.String()},
+		},
+		Responses: map[string]openapi.Response{strconv.Itoa(http.StatusOK): {
+			Description: "Поток событий",
+			Content:     map[string]openapi.MediaType{"text/event-stream": {Schema: openapi.String()}},
+		}},
+	})
+
+	s.add(http.MethodGet, "/feeds/rss.xml", &openapi.Operation{
+		Tags: []string{tagFeeds}, OperationID: "getRSSFeed", Summary: "Лента RSS 2.0 последних новостей",
+		Responses: xml("Лента RSS", true, "application/rss+xml"),
+	})
+	s.add(http.MethodGet, "/feeds/atom.xml", &openapi.Operation{
+		Tags: []string{tagFeeds}, OperationID: "getAtomFeed", Summary: "Лента Atom последних новостей",
+		Responses: xml("Лента Atom", true, "application/atom+xml"),
+	})
+	s.add(http.MethodGet, "/categories/:id/feed.xml", &openapi.Operation{
+		Tags: []string{tagFeeds}, OperationID: "getCategoryFeed", Summary: "Лента новостей категории",
+		Parameters: []openapi.Parameter{
+			id("ID категории"),
+			{Name: "format", In: "query", Description: "Формат ленты, по умолчанию rss", Schema: &openapi.Schema{Type: "string", Enum: []any{"rss", "atom"}}},
+		},
+		Responses: xml("Лента RSS или Atom", true, "application/rss+xml", "application/atom+xml"),
+	})
+
+	s.add(http.MethodGet, "/sitemap.xml", &openapi.Operation{
+		Tags: []string{tagFeeds}, OperationID: "getSitemap", Summary: "Sitemap или индекс sitemap",
+		Description: "Если страниц больше 50 000, возвращается индекс со ссылками на /sitemaps/categories-{chunk}.xml и /sitemaps/news-{chunk}.xml.",
+		Responses:   xml("Sitemap", false, "application/xml"),
+	})
+	s.add(http.MethodGet, "/sitemaps/categories-:chunk.xml", &openapi.Operation{
+		Tags: []string{tagFeeds}, OperationID: "getCategoriesSitemap", Summary: "Sitemap категорий из диапазона ID",
+		Parameters: []openapi.Parameter{{Name: "chunk", In: "path", Description: "Номер части индекса sitemap", Required: true, Schema: openapi.Integer()}},
+		Responses:  xml("Sitemap", false, "application/xml"),
+	})
+	s.add(http.MethodGet, "/sitemaps/news-:chunk.xml", &openapi.Operation{
+		Tags: []string{tagFeeds}, OperationID: "getNewsSitemap", Summary: "Sitemap новостей из диапазона ID",
+		Parameters: []openapi.Parameter{{Name: "chunk", In: "path", Description: "Номер части индекса sitemap", Required: true, Schema: openapi.Integer()}},
+		Responses:  xml("Sitemap", false, "application/xml"),
+	})
+}
+
+func (s *spec) debug() {
+	s.add(http.MethodGet, "/private/debug/queries", &openapi.Operation{
+		Tags: []string{tagDebug}, OperationID: "getQueryStats", Summary: "Статистика SQL запросов",
+		Parameters: []openapi.Parameter{{Name: "limit", In: "query", Description: "Количество запросов", Schema: openapi.Integer()}},
+		Responses: ok("Запросы по убыванию общего времени", openapi.Object(map[string]*openapi.Schema{
+			"success": openapi.Boolean(),
+			"queries": openapi.Array(s.doc.Schema(logging.QueryStat{})),
+		})),
+		Security: keyAuth(),
+	})
+	s.add(http.MethodDelete, "/private/debug/queries", &openapi.Operation{
+		Tags: []string{tagDebug}, OperationID: "resetQueryStats", Summary: "Очистка статистики SQL запросов",
+		Responses: ok("Статистика очищена", openapi.Object(map[string]*openapi.Schema{"success": openapi.Boolean()})),
+		Security:  keyAuth(),
+	})
+}
+
+func (s *spec) docs() {
+	s.add(http.MethodGet, "/openapi.json", &openapi.Operation{
+		Tags: []string{tagDocs}, OperationID: "getOpenAPI", Summary: "Этот документ OpenAPI",
+		Responses: ok("Документ OpenAPI 3.1", &openapi.Schema{Type: "object"}),
+	})
+	s.add(http.MethodGet, "/docs", &openapi.Operation{
+		Tags: []string{tagDocs}, OperationID: "getDocs", Summary: "Swagger UI",
+		Responses: map[string]openapi.Response{strconv.Itoa(http.StatusOK): {
+			Description: "Страница Swagger UI",
+			Content:     map[string]openapi.MediaType{"text/html": {Schema: openapi.String()}},
+		}},
+	})
+	s.add(http.MethodGet, handlers.DocsAssetsPath+"/:file", &openapi.Operation{
+		Tags: []string{tagDocs}, OperationID: "getDocsAsset", Summary: "Встроенные файлы Swagger UI",
+		Parameters: []openapi.Parameter{{Name: "file", In: "path", Required: true, Schema: &openapi.Schema{Type: "string", Enum: []any{
+			"swagger-ui.css", "swagger-ui-bundle.js",
+		}}}},
+		Responses: map[string]openapi.Response{strconv.Itoa(http.StatusOK): {
+			Description: "Стили или скрипт Swagger UI",
+			Content: map[string]openapi.MediaType{
+				"text/css":        {Schema: openapi.String()},
+				"text/javascript": {Schema: openapi.String()},
+			},
+		}},
+	})
+}
+
+func id(description string, name ...string) openapi.Parameter {
+	param := openapi.Parameter{Name: "id", In: "path", Description: description, Required: true, Schema: &openapi.Schema{Type: "integer", Format: "int64"}}
+	if len(name) > 0 {
+		param.Name = name[0]
+	}
+	return param
+}
+
+func slug() openapi.Parameter {
+	return openapi.Parameter{Name: "slug", In: "path", Description: "Текущий или прежний slug", Required: true, Schema: openapi.String()}
+}
+
+// limit - размер страницы; maxLimit 0 - без ограничения
+func limit(defaultLimit, maxLimit int64) openapi.Parameter {
+	one := int64(1)
+	schema := &openapi.Schema{Type: "integer", Minimum: &one}
+	if maxLimit > 0 {
+		schema.Maximum = &maxLimit
+	}
+	return openapi.Parameter{Name: "limit", In: "query", Description: "Количество записей, по умолчанию " + strconv.FormatInt(defaultLimit, 10), Schema: schema}
+}
+
+func offset() openapi.Parameter {
+	zero := int64(0)
+	return openapi.Parameter{Name: "offset", In: "query", Description: "Смещение", Schema: &openapi.Schema{Type: "integer", Minimum: &zero}}
+}
+
+func categoryID() openapi.Parameter {
+	return openapi.Parameter{Name: "category_id", In: "query", Description: "Новости категории и ее дочерних категорий", Schema: &openapi.Schema{Type: "integer", Format: "int64"}}
+}
+
+func tag() openapi.Parameter {
+	return openapi.Parameter{Name: "tag", In: "query", Description: "Новости с тегом", Schema: openapi.String()}
+}
+
+func cursor() openapi.Parameter {
+	return openapi.Parameter{Name: "cursor", In: "query", Description: "next_cursor предыдущей страницы", Schema: openapi.String()}
+}
+
+func mediaID() openapi.Parameter {
+	return openapi.Parameter{Name: "mediaId", In: "path", Description: "ID изображения", Required: true, Schema: &openapi.Schema{Type: "integer", Format: "int64"}}
+}
+
+func locale() openapi.Parameter {
+	return openapi.Parameter{Name: "locale", In: "path", Description: "Язык перевода", Required: true, Schema: openapi.String()}
+}
+
+func prefix() openapi.Parameter {
+	return openapi.Parameter{Name: "prefix", In: "query", Description: "Теги, начинающиеся с prefix", Schema: openapi.String()}
+}
+
+func window() openapi.Parameter {
+	return openapi.Parameter{Name: "window", In: "query", Description: "Период, по умолчанию 24h", Schema: &openapi.Schema{Type: "string", Enum: []any{"1h", "24h", "7d", "30d"}}}
+}
+
+// format - формат содержимого новостей, withSource - доступен исходный Markdown
+func format(withSource bool) openapi.Parameter {
+	formats := []any{models.ContentFormatHTML, models.ContentFormatText}
+	if withSource {
+		formats = append(formats, models.ContentFormatMarkdownSource)
+	}
+	return openapi.Parameter{Name: "format", In: "query", Description: "Формат content, по умолчанию html", Schema: &openapi.Schema{Type: "string", Enum: formats}}
+}
+
+func lang() openapi.Parameter {
+	return openapi.Parameter{Name: "lang", In: "query", Description: "Язык ответа, иначе выбирается по Accept-Language", Schema: openapi.String()}
+}
+
+func keyAuth() []map[string][]string {
+	return []map[string][]string{{KeyAuth: {}}}
+}
+
+func successor(operation string) string {
+	return "Устарел, заменен " + operation + "."
+}
+
+func jsonContent(schema *openapi.Schema) map[string]openapi.MediaType {
+	return map[string]openapi.MediaType{"application/json": {Schema: schema}}
+}
+
+func body(schema *openapi.Schema) *openapi.RequestBody {
+	return &openapi.RequestBody{Required: true, Content: jsonContent(schema)}
+}
+
+func ok(description string, schema *openapi.Schema) map[string]openapi.Response {
+	return map[string]openapi.Response{strconv.Itoa(http.StatusOK): {Description: description, Content: jsonContent(schema)}}
+}
+
+// deleted - ответ об удалении с сообщением
+func deleted(description string) map[string]openapi.Response {
+	return ok(description, openapi.Object(map[string]*openapi.Schema{
+		"success": openapi.Boolean(),
+		"message": openapi.String(),
+	}))
+}
+
+// xml - ответ в формате XML; conditional - поддерживаются ETag и Last-Modified с ответом 304
+func xml(description string, conditional bool, contentTypes ...string) map[string]openapi.Response {
+	content := map[string]openapi.MediaType{}
+	for _, contentType := range contentTypes {
+		content[contentType] = openapi.MediaType{Schema: openapi.String()}
+	}
+	responses := map[string]openapi.Response{strconv.Itoa(http.StatusOK): {Description: description, Content: content}}
+	if conditional {
+		responses[strconv.Itoa(http.StatusNotModified)] = openapi.Response{Description: "Лента не изменилась с версии из If-None-Match или If-Modified-Since"}
+	}
+	return responses
+}
+
+func noContent(description string) map[string]openapi.Response {
+	return map[string]openapi.Response{strconv.Itoa(http.StatusNoContent): {Description: description}}
+}
+
+// withRedirect добавляет ответ 301 для прежнего slug
+func withRedirect(responses map[string]openapi.Response) map[string]openapi.Response {
+	responses[strconv.Itoa(http.StatusMovedPermanently)] = openapi.Response{
+		Description: "Slug изменился, Location - адрес по текущему slug",
+		Headers:     map[string]openapi.Header{"Location": {Schema: openapi.String()}},
+	}
+	return responses
+}
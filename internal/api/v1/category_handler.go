@@ -0,0 +1,201 @@
+package v1
+
+import (
+	"go_news_server/internal/handlers"
+	"go_news_server/internal/models"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ListCategories возвращает страницу категорий с общим числом категорий в meta.total
+// GET /api/v1/categories
+func (h *Handler) ListCategories(c *fiber.Ctx) error {
+	limit, offset, err := pageParams(c, 10)
+	if err != nil {
+		return err
+	}
+
+	categories, total, err := h.Categories.GetAllCategories(localeContext(c), limit, offset)
+	if err != nil {
+		return err
+	}
+	return c.JSON(Response[[]Category]{Data: newCategories(categories), Meta: &Meta{Limit: limit, Offset: offset, Total: &total}})
+}
+
+// GetCategoryTree возвращает дерево категорий
+// GET /api/v1/categories/tree
+func (h *Handler) GetCategoryTree(c *fiber.Ctx) error {
+	tree, err := h.Categories.GetCategoryTree(localeContext(c))
+	if err != nil {
+		return err
+	}
+	return c.JSON(Response[[]CategoryNode]{Data: newCategoryTree(tree)})
+}
+
+// GetCategory возвращает категорию по ID
+// GET /api/v1/categories/:id
+func (h *Handler) GetCategory(c *fiber.Ctx) error {
+	id, err := idParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	category, err := h.Categories.GetCategoryByID(localeContext(c), id)
+	if err != nil {
+		return err
+	}
+	setContentLanguage(c, category.Locale)
+	return c.JSON(Response[Category]{Data: newCategory(*category)})
+}
+
+// GetCategoryBySlug возвращает категорию по slug, для устаревшего slug отвечает 301 на адрес по текущему
+// GET /api/v1/categories/by-slug/:slug
+func (h *Handler) GetCategoryBySlug(c *fiber.Ctx) error {
+	category, current, err := h.Categories.GetCategoryBySlug(localeContext(c), c.Params("slug"))
+	if err != nil {
+		return err
+	}
+	if category == nil {
+		return c.Redirect(redirectLocation(c, "/api/v1/categories/by-slug/", current), fiber.StatusMovedPermanently)
+	}
+
+	setContentLanguage(c, category.Locale)
+	return c.JSON(Response[Category]{Data: newCategory(*category)})
+}
+
+// GetCategoryDescendants возвращает всех потомков категории
+// GET /api/v1/categories/:id/descendants
+func (h *Handler) GetCategoryDescendants(c *fiber.Ctx) error {
+	id, err := idParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	categories, err := h.Categories.GetCategoryDescendants(localeContext(c), id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(Response[[]Category]{Data: newCategories(categories)})
+}
+
+// GetNewsCategories возвращает категории новости
+// GET /api/v1/news/:id/categories
+func (h *Handler) GetNewsCategories(c *fiber.Ctx) error {
+	id, err := idParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	categories, err := h.Categories.GetCategoriesByNewsID(localeContext(c), id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(Response[[]Category]{Data: newCategories(categories)})
+}
+
+// CreateCategory создает категорию, отвечает 201 с Location новой категории
+// POST /api/v1/categories
+func (h *Handler) CreateCategory(c *fiber.Ctx) error {
+	var req CategoryRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+	if err := handlers.ValidateRequest(&req); err != nil {
+		return err
+	}
+
+	category, err := h.Categories.CreateCategory(c.Context(), &models.CategoryCreateRequest{
+		Name:        req.Name,
+		Description: req.Description,
+		ParentId:    req.ParentID,
+	})
+	if err != nil {
+		return err
+	}
+
+	c.Location("/api/v1/categories/" + strconv.FormatInt(category.Id, 10))
+	return c.Status(fiber.StatusCreated).JSON(Response[Category]{Data: newCategory(*category)})
+}
+
+// UpdateCategory изменяет название, описание и родителя категории
+// PUT /api/v1/categories/:id
+func (h *Handler) UpdateCategory(c *fiber.Ctx) error {
+	id, err := idParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	var req CategoryRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+	if err := handlers.ValidateRequest(&req); err != nil {
+		return err
+	}
+
+	category, err := h.Categories.UpdateCategory(c.Context(), id, &models.CategoryUpdateRequest{
+		Name:        req.Name,
+		Description: req.Description,
+		ParentId:    req.ParentID,
+	})
+	if err != nil {
+		return err
+	}
+	return c.JSON(Response[Category]{Data: newCategory(*category)})
+}
+
+// MoveCategory переносит категорию в дереве и/или меняет ее позицию
+// PUT /api/v1/categories/:id/move
+func (h *Handler) MoveCategory(c *fiber.Ctx) error {
+	id, err := idParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	var req CategoryMoveRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+	if err := handlers.ValidateRequest(&req); err != nil {
+		return err
+	}
+
+	category, err := h.Categories.MoveCategory(c.Context(), id, &models.CategoryMoveRequest{
+		ParentId: req.ParentID,
+		Position: req.Position,
+	})
+	if err != nil {
+		return err
+	}
+	return c.JSON(Response[Category]{Data: newCategory(*category)})
+}
+
+// DeleteCategory удаляет категорию
+// DELETE /api/v1/categories/:id
+func (h *Handler) DeleteCategory(c *fiber.Ctx) error {
+	id, err := idParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	if err := h.Categories.DeleteCategory(c.Context(), id); err != nil {
+		return err
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// ListTags возвращает используемые теги с числом новостей, ?prefix= - подсказки по началу названия
+// GET /api/v1/tags
+func (h *Handler) ListTags(c *fiber.Ctx) error {
+	limit, err := limitQuery(c, 50, 200)
+	if err != nil {
+		return err
+	}
+
+	tags, err := h.Tags.GetTags(c.Context(), c.Query("prefix"), limit)
+	if err != nil {
+		return err
+	}
+	return c.JSON(Response[[]Tag]{Data: newTags(tags)})
+}
@@ -0,0 +1,198 @@
+// Package v1 - первая версия API (/api/v1). Запросы и ответы описываются собственными DTO в snake_case
+// и не зависят от моделей базы данных: модели можно менять, не ломая клиентов версии.
+// Несовместимые изменения схемы делаются в следующей версии (/api/v2), а не здесь.
+package v1
+
+import (
+	"go_news_server/internal/models"
+	"time"
+)
+
+// Response - ответ API: данные в data, параметры страницы списка в meta
+type Response[T any] struct {
+	Data T     `json:"data"`
+	Meta *Meta `json:"meta,omitempty"`
+}
+
+// Meta - параметры страницы списка. Total заполняется, если общее число записей известно.
+// NextCursor передается в ?cursor= для следующей страницы, пустой - страниц больше нет или список не поддерживает курсоры.
+type Meta struct {
+	Limit      int    `json:"limit"`
+	Offset     int    `json:"offset"`
+	Total      *int64 `json:"total,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// News - новость
+type News struct {
+	ID      int64  `json:"id"`
+	Title   string `json:"title"`
+	Slug    string `json:"slug"`
+	Content string `json:"content"`
+	// ContentFormat - формат, в котором автор передал содержимое: html или markdown
+	ContentFormat string `json:"content_format"`
+	// ContentSource - исходный Markdown, только на закрытых эндпоинтах
+	ContentSource string    `json:"content_source,omitempty"`
+	Categories    []int64   `json:"categories"`
+	Tags          []string  `json:"tags"`
+	Media         []Media   `json:"media"`
+	Locale        string    `json:"locale,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	// Views - просмотры за период в списке популярных новостей или за все время в закрытом списке,
+	// только на закрытых эндпоинтах
+	Views *int64 `json:"views,omitempty"`
+}
+
+// Media - изображение новости
+type Media struct {
+	ID              int64  `json:"id"`
+	URL             string `json:"url"`
+	ThumbnailURL    string `json:"thumbnail_url"`
+	ContentType     string `json:"content_type"`
+	Width           int    `json:"width"`
+	Height          int    `json:"height"`
+	ThumbnailWidth  int    `json:"thumbnail_width"`
+	ThumbnailHeight int    `json:"thumbnail_height"`
+	Alt             string `json:"alt"`
+	Position        int    `json:"position"`
+	IsCover         bool   `json:"is_cover"`
+}
+
+// NewsUpdateRequest - изменение новости, незаданные поля не меняются
+type NewsUpdateRequest struct {
+	Title   *string `json:"title" validate:"omitempty,max=255"`
+	Content *string `json:"content"`
+	// ContentFormat - html или markdown, по умолчанию формат, в котором новость хранится сейчас
+	ContentFormat *string `json:"content_format" validate:"omitempty,oneof=html markdown"`
+	// Categories - ID категорий, [] удаляет все категории
+	Categories []int64 `json:"categories" validate:"dive,min=1"`
+	// Tags - теги новости, отсутствующие создаются, [] удаляет все теги
+	Tags []string `json:"tags"`
+}
+
+// Category - категория
+type Category struct {
+	ID          int64     `json:"id"`
+	Name        string    `json:"name"`
+	Slug        string    `json:"slug"`
+	Description string    `json:"description"`
+	ParentID    *int64    `json:"parent_id"`
+	Position    int       `json:"position"`
+	Locale      string    `json:"locale,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// CategoryNode - категория с дочерними категориями
+type CategoryNode struct {
+	Category
+	Children []CategoryNode `json:"children"`
+}
+
+// CategoryRequest - создание или изменение категории
+type CategoryRequest struct {
+	Name        string `json:"name" validate:"required,max=100"`
+	Description string `json:"description"`
+	// ParentID - родитель; при изменении не задан - без изменений, 0 - перенос в корень
+	ParentID *int64 `json:"parent_id"`
+}
+
+// CategoryMoveRequest - перенос категории в дереве
+type CategoryMoveRequest struct {
+	// ParentID - новый родитель, null или 0 - корень
+	ParentID *int64 `json:"parent_id"`
+	// Position - позиция среди дочерних категорий нового родителя, не задана - последней
+	Position *int `json:"position" validate:"omitempty,min=0"`
+}
+
+// Tag - тег с числом новостей
+type Tag struct {
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+}
+
+func newNews(news models.News) News {
+	dto := News{
+		ID:            news.Id,
+		Title:         news.Title,
+		Slug:          news.Slug,
+		Content:       news.Content,
+		ContentFormat: news.ContentFormat,
+		ContentSource: news.ContentSource,
+		Categories:    news.Categories,
+		Tags:          news.Tags,
+		Media:         make([]Media, len(news.Media)),
+		Locale:        news.Locale,
+		CreatedAt:     news.CreatedAt,
+		UpdatedAt:     news.UpdatedAt,
+	}
+	if dto.Categories == nil {
+		dto.Categories = []int64{}
+	}
+	if dto.Tags == nil {
+		dto.Tags = []string{}
+	}
+	for i, media := range news.Media {
+		dto.Media[i] = Media{
+			ID:              media.Id,
+			URL:             media.URL,
+			ThumbnailURL:    media.ThumbnailURL,
+			ContentType:     media.ContentType,
+			Width:           media.Width,
+			Height:          media.Height,
+			ThumbnailWidth:  media.ThumbnailWidth,
+			ThumbnailHeight: media.ThumbnailHeight,
+			Alt:             media.Alt,
+			Position:        media.Position,
+			IsCover:         media.IsCover,
+		}
+	}
+	return dto
+}
+
+func newNewsList(newsList []models.News) []News {
+	result := make([]News, len(newsList))
+	for i, news := range newsList {
+		result[i] = newNews(news)
+	}
+	return result
+}
+
+func newCategory(category models.Category) Category {
+	return Category{
+		ID:          category.Id,
+		Name:        category.Name,
+		Slug:        category.Slug,
+		Description: category.Description,
+		ParentID:    category.ParentId,
+		Position:    category.Position,
+		Locale:      category.Locale,
+		CreatedAt:   category.CreatedAt,
+		UpdatedAt:   category.UpdatedAt,
+	}
+}
+
+func newCategories(categories []models.Category) []Category {
+	result := make([]Category, len(categories))
+	for i, category := range categories {
+		result[i] = newCategory(category)
+	}
+	return result
+}
+
+func newCategoryTree(nodes []*models.CategoryNode) []CategoryNode {
+	result := make([]CategoryNode, len(nodes))
+	for i, node := range nodes {
+		result[i] = CategoryNode{Category: newCategory(node.Category), Children: newCategoryTree(node.Children)}
+	}
+	return result
+}
+
+func newTags(tags []models.Tag) []Tag {
+	result := make([]Tag, len(tags))
+	for i, tag := range tags {
+		result[i] = Tag{Name: tag.Name, Count: tag.Count}
+	}
+	return result
+}
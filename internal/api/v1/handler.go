@@ -0,0 +1,284 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go_news_server/internal/handlers"
+	"go_news_server/internal/middleware"
+	"go_news_server/internal/models"
+	"go_news_server/internal/services"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// MaxPageLimit - наибольший размер страницы списка (?limit=)
+const MaxPageLimit = 100
+
+// Handler обрабатывает запросы /api/v1. Ошибки возвращаются общему обработчику ошибок (application/problem+json).
+type Handler struct {
+	News       services.NewsServiceInterface
+	Categories services.CategoryServiceInterface
+	Tags       *services.TagService
+}
+
+// ListNews возвращает страницу новостей в порядке ID.
+// ?limit, ?cursor - meta.next_cursor предыдущей страницы или ?offset, ?category_id - новости категории
+// и ее дочерних категорий, ?tag, ?format=html|text
+// GET /api/v1/news
+func (h *Handler) ListNews(c *fiber.Ctx) error {
+	return h.listNews(c, false)
+}
+
+// ListPrivateNews возвращает страницу новостей для редакторов вместе с исходным Markdown (content_source)
+// и числом просмотров за все время (views), доступен также ?format=markdown-source
+// GET /api/v1/private/news
+func (h *Handler) ListPrivateNews(c *fiber.Ctx) error {
+	return h.listNews(c, true)
+}
+
+func (h *Handler) listNews(c *fiber.Ctx, withSource bool) error {
+	limit, offset, err := pageParams(c, 10)
+	if err != nil {
+		return err
+	}
+	filter := models.NewsFilter{Tag: c.Query("tag")}
+	if filter.CategoryID, err = idQuery(c, "category_id"); err != nil {
+		return err
+	}
+	if filter.AfterID, err = idQuery(c, "cursor"); err != nil {
+		return err
+	}
+	if filter.AfterID != 0 && offset != 0 {
+		return fiber.NewError(fiber.StatusBadRequest, "cursor and offset cannot be used together")
+	}
+
+	// лишняя новость показывает, есть ли следующая страница
+	newsList, err := h.News.GetNewsList(localeContext(c), filter, limit+1, offset)
+	if err != nil {
+		return err
+	}
+	var next string
+	if len(newsList) > limit {
+		newsList = newsList[:limit]
+		next = strconv.FormatInt(newsList[limit-1].Id, 10)
+	}
+	if err := services.ApplyContentFormat(newsList, c.Query("format"), withSource); err != nil {
+		return err
+	}
+
+	result := newNewsList(newsList)
+	if withSource {
+		if err := h.News.AddViewTotals(c.Context(), newsList); err != nil {
+			return err
+		}
+		for i := range result {
+			result[i].Views = &newsList[i].Views
+		}
+	}
+	return c.JSON(Response[[]News]{Data: result, Meta: &Meta{Limit: limit, Offset: offset, NextCursor: next}})
+}
+
+// GetNewsBySlug возвращает новость по slug, для устаревшего slug отвечает 301 на адрес по текущему
+// GET /api/v1/news/by-slug/:slug
+func (h *Handler) GetNewsBySlug(c *fiber.Ctx) error {
+	news, current, err := h.News.GetNewsBySlug(localeContext(c), c.Params("slug"))
+	if err != nil {
+		return err
+	}
+	if news == nil {
+		return c.Redirect(redirectLocation(c, "/api/v1/news/by-slug/", current), fiber.StatusMovedPermanently)
+	}
+
+	newsList := []models.News{*news}
+	if err := services.ApplyContentFormat(newsList, c.Query("format"), false); err != nil {
+		return err
+	}
+	setContentLanguage(c, news.Locale)
+	return c.JSON(Response[News]{Data: newNews(newsList[0])})
+}
+
+// GetRelatedNews возвращает новости, похожие на новость :id, ?limit - не больше services.MaxRelatedLimit
+// GET /api/v1/news/:id/related
+func (h *Handler) GetRelatedNews(c *fiber.Ctx) error {
+	id, err := idParam(c, "id")
+	if err != nil {
+		return err
+	}
+	limit, err := limitQuery(c, 5, services.MaxRelatedLimit)
+	if err != nil {
+		return err
+	}
+
+	newsList, err := h.News.GetRelatedNews(localeContext(c), id, limit)
+	if err != nil {
+		return err
+	}
+	if err := services.ApplyContentFormat(newsList, c.Query("format"), false); err != nil {
+		return err
+	}
+	return c.JSON(Response[[]News]{Data: newNewsList(newsList)})
+}
+
+// GetPopularNews возвращает самые просматриваемые новости за ?window= (1h, 24h, 7d, 30d; по умолчанию 24h)
+// GET /api/v1/news/popular
+func (h *Handler) GetPopularNews(c *fiber.Ctx) error {
+	return h.popularNews(c, false)
+}
+
+// GetPrivatePopularNews возвращает популярные новости вместе с числом просмотров за период (views)
+// GET /api/v1/private/news/popular
+func (h *Handler) GetPrivatePopularNews(c *fiber.Ctx) error {
+	return h.popularNews(c, true)
+}
+
+func (h *Handler) popularNews(c *fiber.Ctx, withViews bool) error {
+	limit, err := limitQuery(c, 10, services.MaxPopularLimit)
+	if err != nil {
+		return err
+	}
+	categoryID, err := idQuery(c, "category_id")
+	if err != nil {
+		return err
+	}
+
+	newsList, err := h.News.GetPopularNews(localeContext(c), c.Query("window", "24h"), categoryID, limit)
+	if err != nil {
+		return err
+	}
+	if err := services.ApplyContentFormat(newsList, c.Query("format"), withViews); err != nil {
+		return err
+	}
+
+	result := newNewsList(newsList)
+	if withViews {
+		for i := range result {
+			result[i].Views = &newsList[i].Views
+		}
+	}
+	return c.JSON(Response[[]News]{Data: result})
+}
+
+// UpdateNews изменяет новость, незаданные поля не меняются. Политику очистки HTML задает маршрут.
+// PATCH /api/v1/news/:id, PATCH /api/v1/private/news/:id
+func (h *Handler) UpdateNews(c *fiber.Ctx) error {
+	id, err := idParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	var req NewsUpdateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+	if err := handlers.ValidateRequest(&req); err != nil {
+		return err
+	}
+
+	news := &models.News{Id: id, Tags: req.Tags}
+	if req.Title != nil {
+		news.Title = *req.Title
+	}
+	if req.Content != nil {
+		news.Content = *req.Content
+	}
+	if req.ContentFormat != nil {
+		news.ContentFormat = *req.ContentFormat
+	}
+
+	var ctx context.Context = c.Context()
+	if policy, ok := c.Locals(middleware.ContentPolicyKey).(string); ok {
+		ctx = services.WithContentPolicy(ctx, policy)
+	}
+	if err := h.News.UpdateNews(ctx, news, req.Categories); err != nil {
+		return v1FieldNames(err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// v1FieldNames переводит имена полей ошибок сервиса (Categories[0]) в имена полей схемы /api/v1 (categories[0])
+func v1FieldNames(err error) error {
+	var validationErr *models.ValidationError
+	if !errors.As(err, &validationErr) {
+		return err
+	}
+	for i, field := range validationErr.Fields {
+		if rest, ok := strings.CutPrefix(field.Field, "Categories"); ok {
+			validationErr.Fields[i].Field = "categories" + rest
+		}
+	}
+	return err
+}
+
+// pageParams разбирает ?limit (1..MaxPageLimit, по умолчанию defaultLimit) и ?offset (>= 0)
+func pageParams(c *fiber.Ctx, defaultLimit int) (limit, offset int, err error) {
+	if limit, err = limitQuery(c, defaultLimit, MaxPageLimit); err != nil {
+		return 0, 0, err
+	}
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		offset, err = strconv.Atoi(offsetStr)
+		if err != nil || offset < 0 {
+			return 0, 0, fiber.NewError(fiber.StatusBadRequest, "offset must be a non-negative integer")
+		}
+	}
+	return limit, offset, nil
+}
+
+func limitQuery(c *fiber.Ctx, defaultLimit, maxLimit int) (int, error) {
+	limitStr := c.Query("limit")
+	if limitStr == "" {
+		return defaultLimit, nil
+	}
+	limit, err := strconv.Atoi(limitStr)
+	if err != nil || limit <= 0 || limit > maxLimit {
+		return 0, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("limit must be between 1 and %d", maxLimit))
+	}
+	return limit, nil
+}
+
+// idQuery разбирает необязательный положительный ID из параметра запроса, 0 - параметр не задан
+func idQuery(c *fiber.Ctx, name string) (int64, error) {
+	value := c.Query(name)
+	if value == "" {
+		return 0, nil
+	}
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, fiber.NewError(fiber.StatusBadRequest, "Invalid "+name)
+	}
+	return id, nil
+}
+
+func idParam(c *fiber.Ctx, name string) (int64, error) {
+	id, err := strconv.ParseInt(c.Params(name), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, fiber.NewError(fiber.StatusBadRequest, "Invalid "+name)
+	}
+	return id, nil
+}
+
+// localeContext возвращает контекст с языком ответа, выбранным middleware.Locale
+func localeContext(c *fiber.Ctx) context.Context {
+	if tag, ok := c.Locals(middleware.LocaleKey).(string); ok {
+		return services.WithLocale(c.Context(), tag)
+	}
+	return c.Context()
+}
+
+func setContentLanguage(c *fiber.Ctx, tag string) {
+	if tag != "" {
+		c.Set(fiber.HeaderContentLanguage, tag)
+	}
+}
+
+// redirectLocation формирует адрес по текущему slug с сохранением параметров запроса
+func redirectLocation(c *fiber.Ctx, prefix, slug string) string {
+	location := prefix + url.PathEscape(slug)
+	if query := c.Context().QueryArgs().String(); query != "" {
+		location += "?" + query
+	}
+	return location
+}
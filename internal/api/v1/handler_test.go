@@ -0,0 +1,160 @@
+package v1
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go_news_server/internal/api/v1/v1test"
+	"go_news_server/internal/handlers"
+	"go_news_server/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func newTestApp(service *v1test.NewsService) *fiber.App {
+	app := fiber.New(fiber.Config{ErrorHandler: handlers.ErrorHandler(zap.NewNop().Sugar())})
+	h := &Handler{News: service}
+	app.Get("/api/v1/news", h.ListNews)
+	app.Get("/api/v1/news/by-slug/:slug", h.GetNewsBySlug)
+	app.Patch("/api/v1/news/:id", h.UpdateNews)
+	return app
+}
+
+func TestListNews(t *testing.T) {
+	service := new(v1test.NewsService)
+	created := time.Date(2025, 7, 20, 9, 56, 38, 0, time.UTC)
+	service.On("GetNewsList", models.NewsFilter{CategoryID: 3}, 3, 4).Return([]models.News{
+		{Id: 1, Title: "Новость", Slug: "novost", Content: "<p>Текст</p>", ContentFormat: "html", ContentSource: "скрыт", CreatedAt: created, UpdatedAt: created},
+	}, nil)
+	app := newTestApp(service)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/api/v1/news?limit=2&offset=4&category_id=3", nil))
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var body map[string]any
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, map[string]any{"limit": 2.0, "offset": 4.0}, body["meta"])
+	assert.Equal(t, []any{map[string]any{
+		"id":             1.0,
+		"title":          "Новость",
+		"slug":           "novost",
+		"content":        "<p>Текст</p>",
+		"content_format": "html",
+		"categories":     []any{},
+		"tags":           []any{},
+		"media":          []any{},
+		"created_at":     "2025-07-20T09:56:38Z",
+		"updated_at":     "2025-07-20T09:56:38Z",
+	}}, body["data"])
+	service.AssertExpectations(t)
+}
+
+func TestListNewsCursor(t *testing.T) {
+	service := new(v1test.NewsService)
+	service.On("GetNewsList", models.NewsFilter{AfterID: 5}, 3, 0).Return([]models.News{{Id: 6}, {Id: 7}, {Id: 8}}, nil)
+	app := newTestApp(service)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/api/v1/news?limit=2&cursor=5", nil))
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var body Response[[]News]
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Len(t, body.Data, 2)
+	assert.Equal(t, "7", body.Meta.NextCursor)
+	service.AssertExpectations(t)
+
+	// курсор и смещение вместе не задаются
+	resp, err = app.Test(httptest.NewRequest("GET", "/api/v1/news?cursor=5&offset=10", nil))
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestListNewsInvalidLimit(t *testing.T) {
+	app := newTestApp(new(v1test.NewsService))
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/api/v1/news?limit=1000", nil))
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, handlers.ProblemContentType, resp.Header.Get("Content-Type"))
+}
+
+func TestGetNewsBySlugRedirect(t *testing.T) {
+	service := new(v1test.NewsService)
+	service.On("GetNewsBySlug", "old").Return(nil, "новая", nil)
+	app := newTestApp(service)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/api/v1/news/by-slug/old?format=text", nil))
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusMovedPermanently, resp.StatusCode)
+	assert.Equal(t, "/api/v1/news/by-slug/%D0%BD%D0%BE%D0%B2%D0%B0%D1%8F?format=text", resp.Header.Get(fiber.HeaderLocation))
+}
+
+func TestUpdateNews(t *testing.T) {
+	service := new(v1test.NewsService)
+	title := "Заголовок"
+	service.On("UpdateNews", &models.News{Id: 5, Title: title}, []int64(nil)).Return(nil)
+	app := newTestApp(service)
+
+	// категории не переданы и не меняются
+	body, _ := json.Marshal(map[string]any{"title": title})
+	req := httptest.NewRequest("PATCH", "/api/v1/news/5", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	body, _ = json.Marshal(map[string]any{"content_format": "rst", "tags": []string{""}, "categories": []int64{0}})
+	req = httptest.NewRequest("PATCH", "/api/v1/news/5", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err = app.Test(req)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+
+	service.AssertExpectations(t)
+}
+
+func TestUpdateNewsErrors(t *testing.T) {
+	service := new(v1test.NewsService)
+	service.On("UpdateNews", &models.News{Id: 404}, []int64{1}).Return(&models.NotFoundError{Message: "News not found"})
+	service.On("UpdateNews", &models.News{Id: 5}, []int64{1, 99}).
+		Return(models.NewFieldError("Categories[1]", models.FieldCodeNotFound, "Category 99 not found"))
+	app := newTestApp(service)
+
+	patch := func(path string, body any) *http.Response {
+		data, _ := json.Marshal(body)
+		req := httptest.NewRequest("PATCH", path, bytes.NewReader(data))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		return resp
+	}
+
+	resp := patch("/api/v1/news/404", map[string]any{"categories": []int64{1}})
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	// имена полей - как в схеме /api/v1
+	resp = patch("/api/v1/news/5", map[string]any{"categories": []int64{1, 99}})
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+	var problem models.Problem
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&problem))
+	assert.Equal(t, "categories[1]", problem.Errors[0].Field)
+
+	service.AssertExpectations(t)
+}
@@ -0,0 +1,41 @@
+package v1
+
+import (
+	"go_news_server/internal/middleware"
+	"go_news_server/pkg/config"
+	"go_news_server/pkg/locale"
+	"go_news_server/pkg/sanitize"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Routes настраивает маршруты версии в группе router (/api/v1)
+func Routes(router fiber.Router, h *Handler, locales *locale.Locales, cfg *config.Config) {
+	localized := middleware.Locale(locales)
+
+	news := router.Group("/news")
+	news.Get("/", localized, h.ListNews)                                        // Страница новостей
+	news.Get("/popular", localized, h.GetPopularNews)                           // Популярные новости за период
+	news.Get("/by-slug/:slug", localized, h.GetNewsBySlug)                      // Новость по slug
+	news.Get("/:id/related", localized, h.GetRelatedNews)                       // Похожие новости
+	news.Get("/:id/categories", localized, h.GetNewsCategories)                 // Категории новости
+	news.Patch("/:id", middleware.ContentPolicy(sanitize.Strict), h.UpdateNews) // Изменение новости
+
+	categories := router.Group("/categories")
+	categories.Get("/", localized, h.ListCategories)                        // Страница категорий
+	categories.Post("/", h.CreateCategory)                                  // Создание категории
+	categories.Get("/tree", localized, h.GetCategoryTree)                   // Дерево категорий
+	categories.Get("/by-slug/:slug", localized, h.GetCategoryBySlug)        // Категория по slug
+	categories.Get("/:id", localized, h.GetCategory)                        // Категория по ID
+	categories.Put("/:id", h.UpdateCategory)                                // Изменение категории
+	categories.Delete("/:id", h.DeleteCategory)                             // Удаление категории
+	categories.Get("/:id/descendants", localized, h.GetCategoryDescendants) // Все потомки категории
+	categories.Put("/:id/move", h.MoveCategory)                             // Перенос и изменение позиции
+
+	router.Get("/tags", h.ListTags) // Теги с числом новостей
+
+	private := router.Group("/private", middleware.KeyProtected(cfg.SecretKey))
+	private.Get("/news", localized, h.ListPrivateNews)                                   // Новости с исходным Markdown
+	private.Get("/news/popular", localized, h.GetPrivatePopularNews)                     // Популярные новости с просмотрами
+	private.Patch("/news/:id", middleware.ContentPolicy(sanitize.Relaxed), h.UpdateNews) // Изменение новости редактором
+}
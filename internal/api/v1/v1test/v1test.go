@@ -0,0 +1,117 @@
+// Package v1test содержит тестовые двойники сервисов для тестов /api/v1 и клиента pkg/client
+package v1test
+
+import (
+	"context"
+
+	"go_news_server/internal/models"
+	"go_news_server/internal/services"
+
+	"github.com/stretchr/testify/mock"
+)
+
+var (
+	_ services.NewsServiceInterface     = (*NewsService)(nil)
+	_ services.CategoryServiceInterface = (*CategoryService)(nil)
+)
+
+// NewsService - mock services.NewsServiceInterface. Аргументы ожидаются без ctx.
+type NewsService struct {
+	mock.Mock
+}
+
+func (m *NewsService) UpdateNews(ctx context.Context, news *models.News, categories []int64) error {
+	return m.Called(news, categories).Error(0)
+}
+
+func (m *NewsService) GetNewsList(ctx context.Context, filter models.NewsFilter, limit, offset int) ([]models.News, error) {
+	args := m.Called(filter, limit, offset)
+	newsList, _ := args.Get(0).([]models.News)
+	return newsList, args.Error(1)
+}
+
+func (m *NewsService) GetNewsBySlug(ctx context.Context, slug string) (*models.News, string, error) {
+	args := m.Called(slug)
+	news, _ := args.Get(0).(*models.News)
+	return news, args.String(1), args.Error(2)
+}
+
+func (m *NewsService) GetRelatedNews(ctx context.Context, id int64, limit int) ([]models.News, error) {
+	args := m.Called(id, limit)
+	newsList, _ := args.Get(0).([]models.News)
+	return newsList, args.Error(1)
+}
+
+func (m *NewsService) GetPopularNews(ctx context.Context, window string, categoryID int64, limit int) ([]models.News, error) {
+	args := m.Called(window, categoryID, limit)
+	newsList, _ := args.Get(0).([]models.News)
+	return newsList, args.Error(1)
+}
+
+func (m *NewsService) AddViewTotals(ctx context.Context, newsList []models.News) error {
+	return m.Called(newsList).Error(0)
+}
+
+// CategoryService - mock services.CategoryServiceInterface. Аргументы ожидаются без ctx.
+type CategoryService struct {
+	mock.Mock
+}
+
+func (m *CategoryService) CreateCategory(ctx context.Context, req *models.CategoryCreateRequest) (*models.Category, error) {
+	args := m.Called(req)
+	category, _ := args.Get(0).(*models.Category)
+	return category, args.Error(1)
+}
+
+func (m *CategoryService) GetCategoryByID(ctx context.Context, id int64) (*models.Category, error) {
+	args := m.Called(id)
+	category, _ := args.Get(0).(*models.Category)
+	return category, args.Error(1)
+}
+
+func (m *CategoryService) GetCategoryBySlug(ctx context.Context, slug string) (*models.Category, string, error) {
+	args := m.Called(slug)
+	category, _ := args.Get(0).(*models.Category)
+	return category, args.String(1), args.Error(2)
+}
+
+func (m *CategoryService) GetAllCategories(ctx context.Context, limit, offset int) ([]models.Category, int64, error) {
+	args := m.Called(limit, offset)
+	categories, _ := args.Get(0).([]models.Category)
+	total, _ := args.Get(1).(int64)
+	return categories, total, args.Error(2)
+}
+
+func (m *CategoryService) UpdateCategory(ctx context.Context, id int64, req *models.CategoryUpdateRequest) (*models.Category, error) {
+	args := m.Called(id, req)
+	category, _ := args.Get(0).(*models.Category)
+	return category, args.Error(1)
+}
+
+func (m *CategoryService) DeleteCategory(ctx context.Context, id int64) error {
+	return m.Called(id).Error(0)
+}
+
+func (m *CategoryService) GetCategoriesByNewsID(ctx context.Context, newsID int64) ([]models.Category, error) {
+	args := m.Called(newsID)
+	categories, _ := args.Get(0).([]models.Category)
+	return categories, args.Error(1)
+}
+
+func (m *CategoryService) GetCategoryTree(ctx context.Context) ([]*models.CategoryNode, error) {
+	args := m.Called()
+	tree, _ := args.Get(0).([]*models.CategoryNode)
+	return tree, args.Error(1)
+}
+
+func (m *CategoryService) GetCategoryDescendants(ctx context.Context, id int64) ([]models.Category, error) {
+	args := m.Called(id)
+	categories, _ := args.Get(0).([]models.Category)
+	return categories, args.Error(1)
+}
+
+func (m *CategoryService) MoveCategory(ctx context.Context, id int64, req *models.CategoryMoveRequest) (*models.Category, error) {
+	args := m.Called(id, req)
+	category, _ := args.Get(0).(*models.Category)
+	return category, args.Error(1)
+}
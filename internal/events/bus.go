@@ -0,0 +1,128 @@
+package events
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Таблицы, изменения которых публикуются в шину
+const (
+	TableNews           = "News"
+	TableCategories     = "Categories"
+	TableNewsCategories = "NewsCategories"
+)
+
+// Действия над записями
+const (
+	ActionInsert = "INSERT"
+	ActionUpdate = "UPDATE"
+	ActionDelete = "DELETE"
+	// ActionResync публикуется после переподключения к базе: уведомления за время разрыва потеряны,
+	// подписчикам следует сбросить свое состояние (кэши и т.п.)
+	ActionResync = "RESYNC"
+)
+
+// Event изменение записи в базе данных
+type Event struct {
+	Table      string    `json:"table"`
+	Action     string    `json:"action"`
+	Id         int64     `json:"id,omitempty"`
+	NewsId     int64     `json:"news_id,omitempty"`
+	CategoryId int64     `json:"category_id,omitempty"`
+	ReceivedAt time.Time `json:"received_at"`
+}
+
+// Subscription подписка на события шины
+type Subscription struct {
+	bus     *Bus
+	ch      chan Event
+	dropped atomic.Int64
+}
+
+// C возвращает канал событий. Канал закрывается при отписке или остановке шины.
+func (s *Subscription) C() <-chan Event {
+	return s.ch
+}
+
+// Dropped возвращает количество событий, пропущенных из-за переполнения буфера подписчика
+func (s *Subscription) Dropped() int64 {
+	return s.dropped.Load()
+}
+
+// Close отменяет подписку
+func (s *Subscription) Close() {
+	s.bus.unsubscribe(s)
+}
+
+// Bus внутренняя pub/sub шина событий об изменениях данных.
+// Публикация не блокируется медленными подписчиками: при переполнении буфера событие для них пропускается.
+type Bus struct {
+	mu     sync.RWMutex
+	subs   map[*Subscription]struct{}
+	closed bool
+}
+
+// NewBus создает шину событий
+func NewBus() *Bus {
+	return &Bus{
+		subs: make(map[*Subscription]struct{}),
+	}
+}
+
+// Subscribe создает подписку с буфером заданного размера
+func (b *Bus) Subscribe(buffer int) *Subscription {
+	s := &Subscription{
+		bus: b,
+		ch:  make(chan Event, buffer),
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		close(s.ch)
+		return s
+	}
+	b.subs[s] = struct{}{}
+	return s
+}
+
+// Publish отправляет событие всем подписчикам
+func (b *Bus) Publish(e Event) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for s := range b.subs {
+		select {
+		case s.ch <- e:
+		default:
+			s.dropped.Add(1)
+		}
+	}
+}
+
+// Close закрывает каналы всех подписчиков
+func (b *Bus) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return
+	}
+	b.closed = true
+	for s := range b.subs {
+		close(s.ch)
+		delete(b.subs, s)
+	}
+}
+
+func (b *Bus) unsubscribe(s *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.subs[s]; ok {
+		close(s.ch)
+		delete(b.subs, s)
+	}
+}
@@ -0,0 +1,30 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBus(t *testing.T) {
+	bus := NewBus()
+	sub := bus.Subscribe(1)
+	slow := bus.Subscribe(0)
+
+	bus.Publish(Event{Table: TableNews, Action: ActionUpdate, Id: 1})
+
+	e := <-sub.C()
+	assert.Equal(t, int64(1), e.Id)
+	assert.Equal(t, int64(1), slow.Dropped())
+
+	sub.Close()
+	_, ok := <-sub.C()
+	assert.False(t, ok)
+
+	bus.Close()
+	_, ok = <-slow.C()
+	assert.False(t, ok)
+
+	_, ok = <-bus.Subscribe(1).C()
+	assert.False(t, ok)
+}
@@ -0,0 +1,117 @@
+package events
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+// Channel канал PostgreSQL, в который триггеры публикуют изменения (см. database/migrations)
+const Channel = "news_changes"
+
+const (
+	minReconnectInterval = 10 * time.Second
+	maxReconnectInterval = time.Minute
+	// pingInterval - как часто проверять соединение при отсутствии уведомлений
+	pingInterval = 90 * time.Second
+)
+
+// Listener слушает уведомления PostgreSQL и публикует их в Bus.
+// Соединение восстанавливается автоматически, после восстановления публикуется ActionResync.
+type Listener struct {
+	dataSourceName string
+	bus            *Bus
+	logger         *zap.SugaredLogger
+
+	listener *pq.Listener
+	stop     chan struct{}
+	wg       sync.WaitGroup
+}
+
+// NewListener создает слушателя уведомлений
+func NewListener(dataSourceName string, bus *Bus, logger *zap.SugaredLogger) *Listener {
+	return &Listener{
+		dataSourceName: dataSourceName,
+		bus:            bus,
+		logger:         logger,
+		stop:           make(chan struct{}),
+	}
+}
+
+// Start подключается к базе и начинает слушать канал
+func (l *Listener) Start() error {
+	l.listener = pq.NewListener(l.dataSourceName, minReconnectInterval, maxReconnectInterval, l.onEvent)
+	if err := l.listener.Listen(Channel); err != nil {
+		_ = l.listener.Close()
+		return err
+	}
+
+	l.wg.Add(1)
+	go l.run()
+
+	l.logger.Infow("Listening for database changes", "channel", Channel)
+	return nil
+}
+
+// Stop закрывает соединение и шину событий
+func (l *Listener) Stop() error {
+	close(l.stop)
+	err := l.listener.Close()
+	l.wg.Wait()
+	l.bus.Close()
+	return err
+}
+
+func (l *Listener) onEvent(event pq.ListenerEventType, err error) {
+	switch event {
+	case pq.ListenerEventDisconnected:
+		l.logger.Warnw("Database listener disconnected", "error", err)
+	case pq.ListenerEventConnectionAttemptFailed:
+		l.logger.Warnw("Database listener failed to reconnect", "error", err)
+	case pq.ListenerEventReconnected:
+		l.logger.Info("Database listener reconnected")
+	}
+}
+
+func (l *Listener) run() {
+	defer l.wg.Done()
+
+	ticker := time.NewTicker(pingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case n, ok := <-l.listener.Notify:
+			if !ok {
+				return
+			}
+
+			// nil приходит после переподключения: уведомления за время разрыва потеряны
+			if n == nil {
+				l.bus.Publish(Event{Action: ActionResync, ReceivedAt: time.Now()})
+				continue
+			}
+
+			var e Event
+			if err := json.Unmarshal([]byte(n.Extra), &e); err != nil {
+				l.logger.Errorw("Failed to parse database notification", "payload", n.Extra, "error", err)
+				continue
+			}
+			e.ReceivedAt = time.Now()
+			l.bus.Publish(e)
+
+		case <-ticker.C:
+			go func() {
+				if err := l.listener.Ping(); err != nil {
+					l.logger.Warnw("Database listener ping failed", "error", err)
+				}
+			}()
+
+		case <-l.stop:
+			return
+		}
+	}
+}
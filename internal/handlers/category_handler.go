@@ -25,39 +25,16 @@ func (h *CategoryHandler) CreateCategory(c *fiber.Ctx) error {
 	var req models.CategoryCreateRequest
 
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"success": false,
-			"message": "Invalid request body",
-		})
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
 	}
 
-	// Валидация
-	if req.Name == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"success": false,
-			"message": "Category name is required",
-		})
-	}
-
-	if len(req.Name) > 100 {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"success": false,
-			"message": "Category name must be less than 100 characters",
-		})
+	if err := ValidateRequest(&req); err != nil {
+		return err
 	}
 
 	category, err := h.categoryService.CreateCategory(c.Context(), &req)
 	if err != nil {
-		if _, ok := err.(*models.ValidationError); ok {
-			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
-				"success": false,
-				"message": err.Error(),
-			})
-		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"success": false,
-			"message": "Failed to create category",
-		})
+		return err
 	}
 
 	return c.Status(fiber.StatusCreated).JSON(models.CategoryResponse{
@@ -72,26 +49,34 @@ func (h *CategoryHandler) GetCategoryByID(c *fiber.Ctx) error {
 	idStr := c.Params("id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"success": false,
-			"message": "Invalid category ID",
-		})
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid category ID")
 	}
 
-	category, err := h.categoryService.GetCategoryByID(c.Context(), id)
+	category, err := h.categoryService.GetCategoryByID(withLocale(c.Context(), c), id)
 	if err != nil {
-		if _, ok := err.(*models.NotFoundError); ok {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"success": false,
-				"message": err.Error(),
-			})
-		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"success": false,
-			"message": "Failed to get category",
-		})
+		return err
+	}
+
+	setContentLanguage(c, category.Locale)
+	return c.JSON(models.CategoryResponse{
+		Success:  true,
+		Category: category,
+	})
+}
+
+// GetCategoryBySlug получает категорию по slug, для устаревшего slug отвечает 301 с адресом по текущему
+// GET /categories/by-slug/:slug
+func (h *CategoryHandler) GetCategoryBySlug(c *fiber.Ctx) error {
+	category, current, err := h.categoryService.GetCategoryBySlug(withLocale(c.Context(), c), c.Params("slug"))
+	if err != nil {
+		return err
+	}
+
+	if category == nil {
+		return c.Redirect(slugLocation(c, "/categories/by-slug/", current), fiber.StatusMovedPermanently)
 	}
 
+	setContentLanguage(c, category.Locale)
 	return c.JSON(models.CategoryResponse{
 		Success:  true,
 		Category: category,
@@ -116,12 +101,9 @@ func (h *CategoryHandler) GetAllCategories(c *fiber.Ctx) error {
 		}
 	}
 
-	categories, total, err := h.categoryService.GetAllCategories(c.Context(), limit, offset)
+	categories, total, err := h.categoryService.GetAllCategories(withLocale(c.Context(), c), limit, offset)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"success": false,
-			"message": "Failed to get categories",
-		})
+		return err
 	}
 
 	return c.JSON(models.CategoriesResponse{
@@ -137,53 +119,21 @@ func (h *CategoryHandler) UpdateCategory(c *fiber.Ctx) error {
 	idStr := c.Params("id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"success": false,
-			"message": "Invalid category ID",
-		})
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid category ID")
 	}
 
 	var req models.CategoryUpdateRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"success": false,
-			"message": "Invalid request body",
-		})
-	}
-
-	// Валидация
-	if req.Name == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"success": false,
-			"message": "Category name is required",
-		})
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
 	}
 
-	if len(req.Name) > 100 {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"success": false,
-			"message": "Category name must be less than 100 characters",
-		})
+	if err := ValidateRequest(&req); err != nil {
+		return err
 	}
 
 	category, err := h.categoryService.UpdateCategory(c.Context(), id, &req)
 	if err != nil {
-		if _, ok := err.(*models.NotFoundError); ok {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"success": false,
-				"message": err.Error(),
-			})
-		}
-		if _, ok := err.(*models.ValidationError); ok {
-			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
-				"success": false,
-				"message": err.Error(),
-			})
-		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"success": false,
-			"message": "Failed to update category",
-		})
+		return err
 	}
 
 	return c.JSON(models.CategoryResponse{
@@ -198,24 +148,12 @@ func (h *CategoryHandler) DeleteCategory(c *fiber.Ctx) error {
 	idStr := c.Params("id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"success": false,
-			"message": "Invalid category ID",
-		})
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid category ID")
 	}
 
 	err = h.categoryService.DeleteCategory(c.Context(), id)
 	if err != nil {
-		if _, ok := err.(*models.NotFoundError); ok {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"success": false,
-				"message": err.Error(),
-			})
-		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"success": false,
-			"message": "Failed to delete category",
-		})
+		return err
 	}
 
 	return c.JSON(fiber.Map{
@@ -230,18 +168,12 @@ func (h *CategoryHandler) GetCategoriesByNewsID(c *fiber.Ctx) error {
 	newsIDStr := c.Params("id")
 	newsID, err := strconv.ParseInt(newsIDStr, 10, 64)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"success": false,
-			"message": "Invalid news ID",
-		})
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid news ID")
 	}
 
-	categories, err := h.categoryService.GetCategoriesByNewsID(c.Context(), newsID)
+	categories, err := h.categoryService.GetCategoriesByNewsID(withLocale(c.Context(), c), newsID)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"success": false,
-			"message": "Failed to get categories for news",
-		})
+		return err
 	}
 
 	return c.JSON(models.CategoriesResponse{
@@ -250,3 +182,61 @@ func (h *CategoryHandler) GetCategoriesByNewsID(c *fiber.Ctx) error {
 		Total:      int64(len(categories)),
 	})
 }
+
+// GetCategoryTree получает дерево категорий
+// GET /categories/tree
+func (h *CategoryHandler) GetCategoryTree(c *fiber.Ctx) error {
+	tree, err := h.categoryService.GetCategoryTree(withLocale(c.Context(), c))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(models.CategoryTreeResponse{
+		Success:    true,
+		Categories: tree,
+	})
+}
+
+// GetCategoryDescendants получает всех потомков категории
+// GET /categories/:id/descendants
+func (h *CategoryHandler) GetCategoryDescendants(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid category ID")
+	}
+
+	categories, err := h.categoryService.GetCategoryDescendants(withLocale(c.Context(), c), id)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(models.CategoriesResponse{
+		Success:    true,
+		Categories: categories,
+		Total:      int64(len(categories)),
+	})
+}
+
+// MoveCategory переносит категорию в дереве и/или меняет ее позицию
+// PUT /categories/:id/move
+func (h *CategoryHandler) MoveCategory(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid category ID")
+	}
+
+	var req models.CategoryMoveRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	category, err := h.categoryService.MoveCategory(c.Context(), id, &req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(models.CategoryResponse{
+		Success:  true,
+		Category: category,
+	})
+}
@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"context"
+	"go_news_server/internal/middleware"
+	"go_news_server/internal/models"
+	"go_news_server/internal/services"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type CommentHandler struct {
+	Service *services.CommentService
+}
+
+// CreateComment добавляет комментарий читателя к новости, parent_id - ответ на комментарий.
+// Читатель определяется по JWT (middleware.Reader).
+// POST /news/:id/comments
+func (h *CommentHandler) CreateComment(c *fiber.Ctx) error {
+	newsID, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid news ID")
+	}
+
+	reader, ok := c.Locals(middleware.ReaderKey).(models.Reader)
+	if !ok {
+		return fiber.NewError(fiber.StatusUnauthorized, "Authentication required")
+	}
+
+	var req models.CommentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	comment, err := h.Service.CreateComment(c.Context(), newsID, reader, &req)
+	if err != nil {
+		return err
+	}
+
+	status := fiber.StatusCreated
+	if comment.Status == models.CommentStatusPending {
+		status = fiber.StatusAccepted
+	}
+	return c.Status(status).JSON(models.CommentResponse{
+		Success: true,
+		Comment: comment,
+	})
+}
+
+// GetComments получает одобренные ветки комментариев новости от новых к старым, ?cursor - следующая страница
+// GET /news/:id/comments
+func (h *CommentHandler) GetComments(c *fiber.Ctx) error {
+	newsID, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid news ID")
+	}
+
+	comments, next, err := h.Service.GetComments(c.Context(), newsID, c.Query("cursor"), c.QueryInt("limit", 20))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(models.CommentsResponse{
+		Success:    true,
+		Comments:   comments,
+		NextCursor: next,
+	})
+}
+
+// GetModerationQueue получает комментарии по статусу (?status=pending|approved|rejected, по умолчанию pending)
+// от старых к новым
+// GET /private/comments
+func (h *CommentHandler) GetModerationQueue(c *fiber.Ctx) error {
+	comments, next, err := h.Service.GetModerationQueue(c.Context(), c.Query("status"), c.Query("cursor"), c.QueryInt("limit", 50))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(models.CommentsResponse{
+		Success:    true,
+		Comments:   comments,
+		NextCursor: next,
+	})
+}
+
+// Approve одобряет комментарий
+// POST /private/comments/:id/approve
+func (h *CommentHandler) Approve(c *fiber.Ctx) error {
+	return h.moderate(c, h.Service.Approve)
+}
+
+// Reject отклоняет комментарий
+// POST /private/comments/:id/reject
+func (h *CommentHandler) Reject(c *fiber.Ctx) error {
+	return h.moderate(c, h.Service.Reject)
+}
+
+func (h *CommentHandler) moderate(c *fiber.Ctx, action func(ctx context.Context, id int64) (*models.Comment, error)) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid comment ID")
+	}
+
+	comment, err := action(c.Context(), id)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(models.CommentResponse{
+		Success: true,
+		Comment: comment,
+	})
+}
+
+// Ban запрещает комментировать автору комментария и отклоняет его комментарии, ожидающие модерации
+// POST /private/comments/:id/ban
+func (h *CommentHandler) Ban(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid comment ID")
+	}
+
+	// тело необязательно
+	var req models.CommentBanRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+		}
+	}
+
+	result, err := h.Service.Ban(c.Context(), id, req.Reason)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(result)
+}
+
+// Unban снимает запрет комментировать
+// DELETE /private/comments/bans/:userId
+func (h *CommentHandler) Unban(c *fiber.Ctx) error {
+	if err := h.Service.Unban(c.Context(), c.Params("userId")); err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Ban removed successfully",
+	})
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"go_news_server/pkg/logging"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type DebugHandler struct {
+	QueryLogger *logging.QueryLogger
+}
+
+// GetQueryStats возвращает статистику SQL запросов
+// GET /private/debug/queries
+func (h *DebugHandler) GetQueryStats(c *fiber.Ctx) error {
+	stats := h.QueryLogger.Stats()
+
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l >= 0 && l < len(stats) {
+			stats = stats[:l]
+		}
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"queries": stats,
+	})
+}
+
+// ResetQueryStats очищает статистику SQL запросов
+// DELETE /private/debug/queries
+func (h *DebugHandler) ResetQueryStats(c *fiber.Ctx) error {
+	h.QueryLogger.Reset()
+
+	return c.JSON(fiber.Map{
+		"success": true,
+	})
+}
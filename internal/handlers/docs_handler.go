@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"html/template"
+
+	"go_news_server/pkg/openapi"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// swaggerUI - файлы swagger-ui-dist 5.18.2 (лицензия Apache-2.0 в swaggerui/LICENSE)
+//
+//go:embed swaggerui/swagger-ui.css swaggerui/swagger-ui-bundle.js
+var swaggerUI embed.FS
+
+// DocsAssetsPath - адрес встроенных файлов Swagger UI
+const DocsAssetsPath = "/docs/assets"
+
+// docsAssets - встроенные файлы Swagger UI по имени и их типы
+var docsAssets = map[string]string{
+	"swagger-ui.css":       "text/css; charset=utf-8",
+	"swagger-ui-bundle.js": "text/javascript; charset=utf-8",
+}
+
+// docsPage - страница Swagger UI. Скрипты и стили загружаются из каталога swagger-ui-dist по адресу AssetsURL.
+var docsPage = template.Must(template.New("docs").Parse(`<!DOCTYPE html>
+<html lang="ru">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<link rel="stylesheet" href="{{.AssetsURL}}/swagger-ui.css">
+</head>
+<body>
+<div id="swagger-ui"></div>
+<script src="{{.AssetsURL}}/swagger-ui-bundle.js" crossorigin></script>
+<script>
+window.ui = SwaggerUIBundle({url: {{.SpecURL}}, dom_id: "#swagger-ui", deepLinking: true});
+</script>
+</body>
+</html>
+`))
+
+// DocsHandler отдает описание API в формате OpenAPI и страницу Swagger UI
+type DocsHandler struct {
+	spec []byte
+	page []byte
+}
+
+// NewDocsHandler сериализует документ один раз при запуске. assetsURL - адрес каталога swagger-ui-dist,
+// пустой - встроенные файлы по адресу DocsAssetsPath.
+func NewDocsHandler(doc *openapi.Document, assetsURL string) (*DocsHandler, error) {
+	if assetsURL == "" {
+		assetsURL = DocsAssetsPath
+	}
+
+	spec, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	var page bytes.Buffer
+	err = docsPage.Execute(&page, struct{ Title, AssetsURL, SpecURL string }{doc.Info.Title, assetsURL, "/openapi.json"})
+	if err != nil {
+		return nil, err
+	}
+
+	return &DocsHandler{spec: spec, page: page.Bytes()}, nil
+}
+
+// GetOpenAPI возвращает документ OpenAPI 3.1
+// GET /openapi.json
+func (h *DocsHandler) GetOpenAPI(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSONCharsetUTF8)
+	return c.Send(h.spec)
+}
+
+// GetDocs возвращает страницу Swagger UI
+// GET /docs
+func (h *DocsHandler) GetDocs(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+	return c.Send(h.page)
+}
+
+// GetAsset возвращает встроенный файл Swagger UI
+// GET /docs/assets/:file
+func (h *DocsHandler) GetAsset(c *fiber.Ctx) error {
+	file := c.Params("file")
+	contentType, ok := docsAssets[file]
+	if !ok {
+		return fiber.NewError(fiber.StatusNotFound, "Asset not found")
+	}
+
+	data, err := swaggerUI.ReadFile("swaggerui/" + file)
+	if err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderContentType, contentType)
+	c.Set(fiber.HeaderCacheControl, "public, max-age=86400")
+	return c.Send(data)
+}
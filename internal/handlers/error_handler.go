@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"errors"
+	"go_news_server/internal/models"
+	"math"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"go.uber.org/zap"
+)
+
+// ProblemContentType - тип содержимого ответов об ошибках (RFC 7807)
+const ProblemContentType = "application/problem+json"
+
+// RequestIDKey - ключ c.Locals с ID запроса, который задает middleware requestid
+const RequestIDKey = "requestid"
+
+// ErrorHandler отвечает на все ошибки обработчиков в формате application/problem+json.
+// models.ValidationError с ошибками полей отдается как 422, без них - как 400.
+// Типизированные ошибки сервисов (models.ValidationError, NotFoundError и т.д.) и *fiber.Error
+// отдаются с их сообщением, остальные - как 500 без подробностей: они только пишутся в лог.
+func ErrorHandler(logger *zap.SugaredLogger) fiber.ErrorHandler {
+	return func(c *fiber.Ctx, err error) error {
+		problem := NewProblem(err)
+		problem.Instance = c.Path()
+		problem.RequestId, _ = c.Locals(RequestIDKey).(string)
+
+		var limitErr *models.RateLimitError
+		if errors.As(err, &limitErr) {
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(max(int(math.Ceil(limitErr.RetryAfter.Seconds())), 1)))
+		}
+		if problem.Status >= fiber.StatusInternalServerError {
+			logger.Errorw("Request failed", "error", err, "method", c.Method(), "path", c.Path(), "request_id", problem.RequestId)
+		}
+
+		return c.Status(problem.Status).JSON(problem, ProblemContentType)
+	}
+}
+
+// NewProblem преобразует ошибку в ответ об ошибке без привязки к запросу
+func NewProblem(err error) models.Problem {
+	var (
+		validationErr *models.ValidationError
+		notFoundErr   *models.NotFoundError
+		conflictErr   *models.ConflictError
+		forbiddenErr  *models.ForbiddenError
+		limitErr      *models.RateLimitError
+		fiberErr      *fiber.Error
+	)
+
+	switch {
+	case errors.As(err, &validationErr):
+		// ошибки отдельных полей - 422, ошибка запроса в целом - 400
+		status := fiber.StatusBadRequest
+		if len(validationErr.Fields) > 0 {
+			status = fiber.StatusUnprocessableEntity
+		}
+		problem := newProblem(status, codeOr(validationErr.Code, models.CodeValidationFailed), validationErr.Message)
+		problem.Errors = validationErr.Fields
+		return problem
+	case errors.As(err, &notFoundErr):
+		return newProblem(fiber.StatusNotFound, codeOr(notFoundErr.Code, models.CodeNotFound), notFoundErr.Message)
+	case errors.As(err, &conflictErr):
+		return newProblem(fiber.StatusConflict, codeOr(conflictErr.Code, models.CodeConflict), conflictErr.Message)
+	case errors.As(err, &forbiddenErr):
+		return newProblem(fiber.StatusForbidden, codeOr(forbiddenErr.Code, models.CodeForbidden), forbiddenErr.Message)
+	case errors.As(err, &limitErr):
+		return newProblem(fiber.StatusTooManyRequests, codeOr(limitErr.Code, models.CodeRateLimited), limitErr.Message)
+	case errors.As(err, &fiberErr):
+		if fiberErr.Code >= fiber.StatusInternalServerError {
+			return newProblem(fiberErr.Code, statusCode(fiberErr.Code), "")
+		}
+		return newProblem(fiberErr.Code, statusCode(fiberErr.Code), fiberErr.Message)
+	}
+	return newProblem(fiber.StatusInternalServerError, models.CodeInternal, "")
+}
+
+func newProblem(status int, code, detail string) models.Problem {
+	return models.Problem{
+		Type:   "about:blank",
+		Title:  utils.StatusMessage(status),
+		Status: status,
+		Detail: detail,
+		Code:   code,
+	}
+}
+
+func codeOr(code, fallback string) string {
+	if code != "" {
+		return code
+	}
+	return fallback
+}
+
+// statusCode - код ошибки по HTTP статусу для ошибок без собственного кода
+func statusCode(status int) string {
+	switch status {
+	case fiber.StatusUnauthorized:
+		return models.CodeUnauthorized
+	case fiber.StatusForbidden:
+		return models.CodeForbidden
+	case fiber.StatusNotFound:
+		return models.CodeNotFound
+	case fiber.StatusMethodNotAllowed:
+		return models.CodeMethodNotAllowed
+	case fiber.StatusConflict:
+		return models.CodeConflict
+	case fiber.StatusRequestEntityTooLarge:
+		return models.CodePayloadTooLarge
+	case fiber.StatusUnsupportedMediaType:
+		return models.CodeUnsupportedMediaType
+	case fiber.StatusUnprocessableEntity:
+		return models.CodeValidationFailed
+	case fiber.StatusTooManyRequests:
+		return models.CodeRateLimited
+	case fiber.StatusServiceUnavailable:
+		return models.CodeUnavailable
+	}
+	if status >= fiber.StatusInternalServerError {
+		return models.CodeInternal
+	}
+	return models.CodeBadRequest
+}
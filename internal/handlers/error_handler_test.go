@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go_news_server/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// newTestApp создает приложение с общим обработчиком ошибок, как в сервере
+func newTestApp() *fiber.App {
+	return fiber.New(fiber.Config{ErrorHandler: ErrorHandler(zap.NewNop().Sugar())})
+}
+
+func TestErrorHandler(t *testing.T) {
+	app := newTestApp()
+	app.Use(requestid.New(requestid.Config{ContextKey: RequestIDKey}))
+
+	errs := map[string]error{
+		"validation": &models.ValidationError{
+			Message: "Invalid category",
+			Fields:  []models.FieldError{{Field: "name", Code: "required", Message: "Category name is required"}},
+		},
+		"not-found":  fmt.Errorf("get category: %w", &models.NotFoundError{Message: "Category not found"}),
+		"conflict":   &models.ConflictError{Message: "Category with this name already exists"},
+		"rate-limit": &models.RateLimitError{Message: "Too many comments", RetryAfter: 1500 * time.Millisecond},
+		"fiber":      fiber.NewError(fiber.StatusRequestEntityTooLarge, "File is too large"),
+		"internal":   errors.New("pq: connection refused"),
+	}
+	app.Get("/:name", func(c *fiber.Ctx) error {
+		return errs[c.Params("name")]
+	})
+
+	tests := []struct {
+		name   string
+		status int
+		code   string
+		detail string
+	}{
+		{"validation", http.StatusUnprocessableEntity, models.CodeValidationFailed, "Invalid category"},
+		{"not-found", http.StatusNotFound, models.CodeNotFound, "Category not found"},
+		{"conflict", http.StatusConflict, models.CodeConflict, "Category with this name already exists"},
+		{"rate-limit", http.StatusTooManyRequests, models.CodeRateLimited, "Too many comments"},
+		{"fiber", http.StatusRequestEntityTooLarge, models.CodePayloadTooLarge, "File is too large"},
+		{"internal", http.StatusInternalServerError, models.CodeInternal, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := app.Test(httptest.NewRequest("GET", "/"+tt.name, nil))
+			assert.NoError(t, err)
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.status, resp.StatusCode)
+			assert.Equal(t, ProblemContentType, resp.Header.Get("Content-Type"))
+
+			var problem models.Problem
+			assert.NoError(t, json.NewDecoder(resp.Body).Decode(&problem))
+			assert.Equal(t, "about:blank", problem.Type)
+			assert.Equal(t, tt.status, problem.Status)
+			assert.Equal(t, tt.code, problem.Code)
+			assert.Equal(t, tt.detail, problem.Detail)
+			assert.Equal(t, "/"+tt.name, problem.Instance)
+			assert.NotEmpty(t, problem.RequestId)
+			assert.Equal(t, resp.Header.Get(fiber.HeaderXRequestID), problem.RequestId)
+
+			switch tt.name {
+			case "validation":
+				assert.Equal(t, []models.FieldError{{Field: "name", Code: "required", Message: "Category name is required"}}, problem.Errors)
+			case "rate-limit":
+				assert.Equal(t, "2", resp.Header.Get(fiber.HeaderRetryAfter))
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"go_news_server/internal/services"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type FeedHandler struct {
+	Service *services.FeedService
+}
+
+// GetRSS возвращает ленту RSS 2.0 последних новостей
+// GET /feeds/rss.xml
+func (h *FeedHandler) GetRSS(c *fiber.Ctx) error {
+	feed, err := h.Service.RSS(c.Context(), 0, c.Path())
+	return h.respond(c, feed, err)
+}
+
+// GetAtom возвращает ленту Atom последних новостей
+// GET /feeds/atom.xml
+func (h *FeedHandler) GetAtom(c *fiber.Ctx) error {
+	feed, err := h.Service.Atom(c.Context(), 0, c.Path())
+	return h.respond(c, feed, err)
+}
+
+// GetCategoryFeed возвращает ленту новостей категории, RSS по умолчанию или Atom при ?format=atom
+// GET /categories/:id/feed.xml
+func (h *FeedHandler) GetCategoryFeed(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid category ID")
+	}
+
+	var feed *services.Feed
+	switch format := c.Query("format", "rss"); format {
+	case "rss":
+		feed, err = h.Service.RSS(c.Context(), id, c.Path())
+	case "atom":
+		feed, err = h.Service.Atom(c.Context(), id, c.Path()+"?format=atom")
+	default:
+		return fiber.NewError(fiber.StatusBadRequest, "Unsupported feed format: "+format)
+	}
+	return h.respond(c, feed, err)
+}
+
+// respond отправляет ленту с ETag и Last-Modified, на условный запрос с актуальной версией отвечает 304
+func (h *FeedHandler) respond(c *fiber.Ctx, feed *services.Feed, err error) error {
+	if err != nil {
+		return err
+	}
+
+	sum := sha256.Sum256(feed.Body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	c.Set(fiber.HeaderETag, etag)
+	c.Set(fiber.HeaderCacheControl, "public, max-age=300")
+	if !feed.LastModified.IsZero() {
+		c.Set(fiber.HeaderLastModified, feed.LastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if notModified(c, etag, feed.LastModified) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	c.Set(fiber.HeaderContentType, feed.ContentType)
+	return c.Send(feed.Body)
+}
+
+// notModified проверяет условные заголовки запроса (RFC 9110): If-None-Match имеет приоритет над If-Modified-Since
+func notModified(c *fiber.Ctx, etag string, lastModified time.Time) bool {
+	if noneMatch := c.Get(fiber.HeaderIfNoneMatch); noneMatch != "" {
+		for _, tag := range strings.Split(noneMatch, ",") {
+			tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+			if tag == "*" || tag == etag {
+				return true
+			}
+		}
+		return false
+	}
+
+	if modifiedSince := c.Get(fiber.HeaderIfModifiedSince); modifiedSince != "" && !lastModified.IsZero() {
+		since, err := http.ParseTime(modifiedSince)
+		if err != nil {
+			return false
+		}
+		// Last-Modified передается с точностью до секунды
+		return !lastModified.Truncate(time.Second).After(since)
+	}
+
+	return false
+}
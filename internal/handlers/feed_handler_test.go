@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go_news_server/internal/services"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeedConditionalResponse(t *testing.T) {
+	app := fiber.New()
+	handler := &FeedHandler{}
+	lastModified := time.Date(2025, 7, 20, 9, 56, 38, 500, time.UTC)
+
+	app.Get("/feed", func(c *fiber.Ctx) error {
+		return handler.respond(c, &services.Feed{
+			Body:         []byte(`<rss version="2.0"></rss>`),
+			ContentType:  services.RSSContentType,
+			LastModified: lastModified,
+		}, nil)
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/feed", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, services.RSSContentType, resp.Header.Get("Content-Type"))
+	assert.Equal(t, "Sun, 20 Jul 2025 09:56:38 GMT", resp.Header.Get("Last-Modified"))
+	etag := resp.Header.Get("ETag")
+	require.NotEmpty(t, etag)
+
+	tests := []struct {
+		name           string
+		header         string
+		value          string
+		expectedStatus int
+	}{
+		{"Matching ETag", "If-None-Match", etag, http.StatusNotModified},
+		{"Weak matching ETag in list", "If-None-Match", `"other", W/` + etag, http.StatusNotModified},
+		{"Stale ETag", "If-None-Match", `"other"`, http.StatusOK},
+		{"Not modified since", "If-Modified-Since", "Sun, 20 Jul 2025 09:56:38 GMT", http.StatusNotModified},
+		{"Modified since", "If-Modified-Since", "Sun, 20 Jul 2025 09:00:00 GMT", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/feed", nil)
+			req.Header.Set(tt.header, tt.value)
+
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+		})
+	}
+}
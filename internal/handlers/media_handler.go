@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"go_news_server/internal/models"
+	"go_news_server/internal/services"
+	"go_news_server/pkg/media"
+	"io"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type MediaHandler struct {
+	Service *services.MediaService
+}
+
+// Upload загружает изображение к новости из поля file формы multipart/form-data.
+// Поля alt - подпись, cover=true - сделать обложкой. Тип определяется по содержимому файла.
+// POST /private/news/:id/media
+func (h *MediaHandler) Upload(c *fiber.Ctx) error {
+	newsID, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid news ID")
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "File is required in multipart field \"file\"")
+	}
+	if header.Size > h.Service.MaxSize() {
+		return h.tooLarge(c)
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Failed to read file")
+	}
+	defer file.Close()
+
+	// размер из заголовка части формы не гарантирован, поэтому чтение тоже ограничено
+	data, err := io.ReadAll(io.LimitReader(file, h.Service.MaxSize()+1))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Failed to read file")
+	}
+
+	cover, _ := strconv.ParseBool(c.FormValue("cover"))
+	item, err := h.Service.Upload(c.Context(), newsID, data, c.FormValue("alt"), cover)
+	if err != nil {
+		if errors.Is(err, services.ErrMediaTooLarge) {
+			return h.tooLarge(c)
+		}
+		if errors.Is(err, media.ErrUnsupportedType) {
+			return fiber.NewError(fiber.StatusUnsupportedMediaType, err.Error())
+		}
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(models.NewsMediaResponse{
+		Success: true,
+		Media:   item,
+	})
+}
+
+// Update меняет подпись, позицию или признак обложки изображения
+// PUT /private/news/:id/media/:mediaId
+func (h *MediaHandler) Update(c *fiber.Ctx) error {
+	newsID, id, ok := mediaParams(c)
+	if !ok {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid news or media ID")
+	}
+
+	var req models.NewsMediaUpdateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	item, err := h.Service.Update(c.Context(), newsID, id, &req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(models.NewsMediaResponse{
+		Success: true,
+		Media:   item,
+	})
+}
+
+// Delete удаляет изображение новости вместе с файлами
+// DELETE /private/news/:id/media/:mediaId
+func (h *MediaHandler) Delete(c *fiber.Ctx) error {
+	newsID, id, ok := mediaParams(c)
+	if !ok {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid news or media ID")
+	}
+
+	if err := h.Service.Delete(c.Context(), newsID, id); err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Media deleted successfully",
+	})
+}
+
+func mediaParams(c *fiber.Ctx) (newsID, id int64, ok bool) {
+	newsID, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	id, err = strconv.ParseInt(c.Params("mediaId"), 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return newsID, id, true
+}
+
+func (h *MediaHandler) tooLarge(c *fiber.Ctx) error {
+	return fiber.NewError(fiber.StatusRequestEntityTooLarge, fmt.Sprintf("File must be at most %d bytes", h.Service.MaxSize()))
+}
@@ -2,8 +2,11 @@ package handlers
 
 import (
 	"context"
+	"fmt"
+	"go_news_server/internal/middleware"
 	"go_news_server/internal/models"
 	"go_news_server/internal/services"
+	"net/url"
 	"strconv"
 
 	"github.com/gofiber/fiber/v2"
@@ -19,40 +22,78 @@ func (h *NewsHandlers) EditNewsHandler(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, "Invalid ID")
 	}
 
-	var payload struct {
-		Id         int64   `json:"Id"`
-		Title      *string `json:"Title"`
-		Content    *string `json:"Content"`
-		Categories []int64 `json:"Categories"`
-	}
-
+	var payload models.NewsEditRequest
 	if err := c.BodyParser(&payload); err != nil {
 		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
 	}
+	if err := ValidateRequest(&payload); err != nil {
+		return err
+	}
 
-	news := &models.News{Id: id}
+	news := &models.News{Id: id, Tags: payload.Tags}
 	if payload.Title != nil {
 		news.Title = *payload.Title
 	}
 	if payload.Content != nil {
 		news.Content = *payload.Content
 	}
+	if payload.ContentFormat != nil {
+		news.ContentFormat = *payload.ContentFormat
+	}
+
+	// Политику очистки HTML задает маршрут: строгая для публичных правок, мягкая для редакторов.
+	// Контекст запроса несет признак primary от PrimaryForWrites: проверка категорий не должна читать реплику.
+	var ctx context.Context = c.Context()
+	if policy, ok := c.Locals(middleware.ContentPolicyKey).(string); ok {
+		ctx = services.WithContentPolicy(ctx, policy)
+	}
 
-	if err := h.Service.UpdateNews(context.Background(), news, payload.Categories); err != nil {
-		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	if err := h.Service.UpdateNews(ctx, news, payload.Categories); err != nil {
+		return err
 	}
 
 	return c.JSON(fiber.Map{"Success": true})
 }
 
-// GetNewsList возвращает список новостей.
+// GetNewsList возвращает список новостей со сформированным HTML.
+// ?format=text возвращает Content без разметки, по умолчанию - HTML.
+// ?category_id= оставляет новости категории и всех ее дочерних категорий, ?tag= - новости с тегом.
 func (h *NewsHandlers) GetNewsList(c *fiber.Ctx) error {
+	return h.newsList(c, false)
+}
+
+// GetPrivateNewsList возвращает список новостей для редакторов: вместе с HTML возвращается
+// исходный Markdown (content_source) и число просмотров за все время (views), доступен также ?format=markdown-source.
+func (h *NewsHandlers) GetPrivateNewsList(c *fiber.Ctx) error {
+	return h.newsList(c, true)
+}
+
+func (h *NewsHandlers) newsList(c *fiber.Ctx, withSource bool) error {
 	limit, _ := strconv.Atoi(c.Query("limit", "10"))
 	offset, _ := strconv.Atoi(c.Query("offset", "0"))
 
-	newsList, err := h.Service.GetNewsList(context.Background(), limit, offset)
+	var filter models.NewsFilter
+	if categoryID := c.Query("category_id"); categoryID != "" {
+		id, err := strconv.ParseInt(categoryID, 10, 64)
+		if err != nil || id <= 0 {
+			return fiber.NewError(fiber.StatusBadRequest, "Invalid category_id")
+		}
+		filter.CategoryID = id
+	}
+	filter.Tag = c.Query("tag")
+
+	newsList, err := h.Service.GetNewsList(withLocale(context.Background(), c), filter, limit, offset)
 	if err != nil {
-		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+		return err
+	}
+
+	if err := services.ApplyContentFormat(newsList, c.Query("format"), withSource); err != nil {
+		return err
+	}
+	if withSource {
+		if err := h.Service.AddViewTotals(c.Context(), newsList); err != nil {
+			return err
+		}
 	}
 
 	return c.JSON(fiber.Map{
@@ -60,3 +101,133 @@ func (h *NewsHandlers) GetNewsList(c *fiber.Ctx) error {
 		"News":    newsList,
 	})
 }
+
+// GetNewsBySlug возвращает новость по slug. Для устаревшего slug отвечает 301 с адресом по текущему slug.
+// Параметр format работает так же, как в списке новостей.
+func (h *NewsHandlers) GetNewsBySlug(c *fiber.Ctx) error {
+	news, current, err := h.Service.GetNewsBySlug(withLocale(context.Background(), c), c.Params("slug"))
+	if err != nil {
+		return err
+	}
+
+	if news == nil {
+		return c.Redirect(slugLocation(c, "/news/by-slug/", current), fiber.StatusMovedPermanently)
+	}
+
+	newsList := []models.News{*news}
+	if err := services.ApplyContentFormat(newsList, c.Query("format"), false); err != nil {
+		return err
+	}
+	setContentLanguage(c, news.Locale)
+
+	return c.JSON(fiber.Map{
+		"Success": true,
+		"News":    newsList[0],
+	})
+}
+
+// GetRelatedNews возвращает новости, похожие на новость :id, по общим категориям и сходству заголовков.
+// ?limit - количество (по умолчанию 5, не больше services.MaxRelatedLimit), format работает так же, как в списке новостей.
+func (h *NewsHandlers) GetRelatedNews(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid ID")
+	}
+
+	limit := 5
+	if limitStr := c.Query("limit"); limitStr != "" {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 || limit > services.MaxRelatedLimit {
+			return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("limit must be between 1 and %d", services.MaxRelatedLimit))
+		}
+	}
+
+	newsList, err := h.Service.GetRelatedNews(withLocale(context.Background(), c), id, limit)
+	if err != nil {
+		return err
+	}
+
+	if err := services.ApplyContentFormat(newsList, c.Query("format"), false); err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"Success": true,
+		"News":    newsList,
+	})
+}
+
+// GetPopularNews возвращает самые просматриваемые новости за период ?window= (1h, 24h, 7d, 30d; по умолчанию 24h).
+// ?category_id= - популярные новости категории и ее дочерних категорий, ?limit - количество (по умолчанию 10).
+func (h *NewsHandlers) GetPopularNews(c *fiber.Ctx) error {
+	return h.popularNews(c, false)
+}
+
+// GetPrivatePopularNews возвращает популярные новости вместе с числом просмотров за период (views)
+func (h *NewsHandlers) GetPrivatePopularNews(c *fiber.Ctx) error {
+	return h.popularNews(c, true)
+}
+
+func (h *NewsHandlers) popularNews(c *fiber.Ctx, withViews bool) error {
+	limit := 10
+	if limitStr := c.Query("limit"); limitStr != "" {
+		var err error
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 || limit > services.MaxPopularLimit {
+			return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("limit must be between 1 and %d", services.MaxPopularLimit))
+		}
+	}
+
+	var categoryID int64
+	if categoryStr := c.Query("category_id"); categoryStr != "" {
+		id, err := strconv.ParseInt(categoryStr, 10, 64)
+		if err != nil || id <= 0 {
+			return fiber.NewError(fiber.StatusBadRequest, "Invalid category_id")
+		}
+		categoryID = id
+	}
+
+	newsList, err := h.Service.GetPopularNews(withLocale(context.Background(), c), c.Query("window", "24h"), categoryID, limit)
+	if err != nil {
+		return err
+	}
+
+	if err := services.ApplyContentFormat(newsList, c.Query("format"), withViews); err != nil {
+		return err
+	}
+	if !withViews {
+		for i := range newsList {
+			newsList[i].Views = 0
+		}
+	}
+
+	return c.JSON(fiber.Map{
+		"Success": true,
+		"News":    newsList,
+	})
+}
+
+// withLocale добавляет в контекст язык ответа, выбранный middleware.Locale
+func withLocale(ctx context.Context, c *fiber.Ctx) context.Context {
+	if tag, ok := c.Locals(middleware.LocaleKey).(string); ok {
+		return services.WithLocale(ctx, tag)
+	}
+	return ctx
+}
+
+// setContentLanguage указывает в Content-Language язык отдельной записи: перевод на выбранный язык
+// может отсутствовать, и запись отдается на языке из цепочки замены
+func setContentLanguage(c *fiber.Ctx, tag string) {
+	if tag != "" {
+		c.Set(fiber.HeaderContentLanguage, tag)
+	}
+}
+
+// slugLocation формирует адрес перенаправления на текущий slug с сохранением параметров запроса
+func slugLocation(c *fiber.Ctx, prefix, slug string) string {
+	location := prefix + url.PathEscape(slug)
+	if query := c.Context().QueryArgs().String(); query != "" {
+		location += "?" + query
+	}
+	return location
+}
@@ -4,14 +4,20 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"go_news_server/internal/middleware"
 	"go_news_server/internal/models"
 	"go_news_server/internal/services"
+	"go_news_server/pkg/database"
+	"go_news_server/pkg/locale"
+	"go_news_server/pkg/sanitize"
 
-	"github.com/gofiber/fiber/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -21,21 +27,51 @@ type MockNewsService struct {
 	mock.Mock
 }
 
+// updateContext - значения контекста UpdateNews, которые проверяют тесты. Сам контекст запроса Fiber
+// переиспользуется после ответа, поэтому мок не должен его запоминать.
+type updateContext struct {
+	Primary bool
+	Policy  string
+}
+
 func (m *MockNewsService) UpdateNews(ctx context.Context, news *models.News, categories []int64) error {
-	args := m.Called(ctx, news, categories)
+	primary, _ := ctx.Value(database.PrimaryKey{}).(bool)
+	args := m.Called(updateContext{Primary: primary, Policy: services.ContentPolicyFromContext(ctx)}, news, categories)
 	return args.Error(0)
 }
 
-func (m *MockNewsService) GetNewsList(ctx context.Context, limit, offset int) ([]models.News, error) {
-	args := m.Called(ctx, limit, offset)
+func (m *MockNewsService) GetNewsList(ctx context.Context, filter models.NewsFilter, limit, offset int) ([]models.News, error) {
+	args := m.Called(ctx, filter, limit, offset)
 	return args.Get(0).([]models.News), args.Error(1)
 }
 
+func (m *MockNewsService) GetNewsBySlug(ctx context.Context, slug string) (*models.News, string, error) {
+	args := m.Called(ctx, slug)
+	news, _ := args.Get(0).(*models.News)
+	return news, args.String(1), args.Error(2)
+}
+
+func (m *MockNewsService) GetRelatedNews(ctx context.Context, id int64, limit int) ([]models.News, error) {
+	args := m.Called(ctx, id, limit)
+	news, _ := args.Get(0).([]models.News)
+	return news, args.Error(1)
+}
+
+func (m *MockNewsService) GetPopularNews(ctx context.Context, window string, categoryID int64, limit int) ([]models.News, error) {
+	args := m.Called(ctx, window, categoryID, limit)
+	news, _ := args.Get(0).([]models.News)
+	return news, args.Error(1)
+}
+
+func (m *MockNewsService) AddViewTotals(ctx context.Context, newsList []models.News) error {
+	return m.Called(ctx, newsList).Error(0)
+}
+
 // Убедимся, что MockNewsService реализует интерфейс
 var _ services.NewsServiceInterface = (*MockNewsService)(nil)
 
 func TestEditNewsHandler(t *testing.T) {
-	app := fiber.New()
+	app := newTestApp()
 	mockService := new(MockNewsService)
 	handler := &NewsHandlers{Service: mockService}
 
@@ -69,6 +105,18 @@ func TestEditNewsHandler(t *testing.T) {
 			expectedStatus: http.StatusBadRequest,
 			setupMock:      func() {},
 		},
+		{
+			name: "Database error is not exposed",
+			id:   "2",
+			requestBody: map[string]interface{}{
+				"Title": "Title",
+			},
+			expectedStatus: http.StatusInternalServerError,
+			setupMock: func() {
+				mockService.On("UpdateNews", mock.Anything, mock.MatchedBy(func(n *models.News) bool { return n.Id == 2 }), []int64(nil)).
+					Return(errors.New(`pq: relation "News" does not exist`))
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -86,14 +134,72 @@ func TestEditNewsHandler(t *testing.T) {
 			}()
 
 			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			if resp.StatusCode != http.StatusOK {
+				assert.Equal(t, ProblemContentType, resp.Header.Get("Content-Type"))
+				data, _ := io.ReadAll(resp.Body)
+				assert.NotContains(t, string(data), "pq:")
+			}
 
 			mockService.AssertExpectations(t)
 		})
 	}
 }
 
+func TestEditNewsHandlerUsesPrimary(t *testing.T) {
+	app := newTestApp()
+	app.Use(middleware.PrimaryForWrites())
+	mockService := new(MockNewsService)
+	handler := &NewsHandlers{Service: mockService}
+	app.Post("/edit/:Id", handler.EditNewsHandler)
+	app.Post("/private/edit/:Id", middleware.ContentPolicy(sanitize.Relaxed), handler.EditNewsHandler)
+
+	// проверка категорий в сервисе должна читать primary: категория могла быть создана только что
+	for path, policy := range map[string]string{"/edit/1": sanitize.Strict, "/private/edit/1": sanitize.Relaxed} {
+		mockService.On("UpdateNews", updateContext{Primary: true, Policy: policy}, mock.Anything, []int64{7}).Return(nil).Once()
+
+		req := httptest.NewRequest("POST", path, strings.NewReader(`{"Title":"Заголовок","Categories":[7]}`))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode, path)
+	}
+	mockService.AssertExpectations(t)
+}
+
+func TestEditNewsHandlerValidation(t *testing.T) {
+	app := newTestApp()
+	handler := &NewsHandlers{Service: new(MockNewsService)}
+	app.Post("/edit/:Id", handler.EditNewsHandler)
+
+	// длина заголовка считается в символах: 256 букв кириллицей превышают VARCHAR(255)
+	title := strings.Repeat("я", 256)
+	body, _ := json.Marshal(map[string]interface{}{
+		"Title":         title,
+		"ContentFormat": "rst",
+		"Categories":    []int64{1, -2},
+	})
+	req := httptest.NewRequest("POST", "/edit/1", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+	var problem models.Problem
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&problem))
+	assert.Equal(t, models.CodeValidationFailed, problem.Code)
+
+	fields := make([]string, len(problem.Errors))
+	for i, fieldErr := range problem.Errors {
+		fields[i] = fieldErr.Field + ":" + fieldErr.Code
+	}
+	assert.Equal(t, []string{"Title:max", "ContentFormat:oneof", "Categories[1]:min"}, fields)
+}
+
 func TestGetNewsList(t *testing.T) {
-	app := fiber.New()
+	app := newTestApp()
 	mockService := new(MockNewsService)
 	handler := &NewsHandlers{Service: mockService}
 
@@ -108,7 +214,7 @@ func TestGetNewsList(t *testing.T) {
 		},
 	}
 
-	mockService.On("GetNewsList", mock.Anything, 10, 0).Return(expectedNews, nil)
+	mockService.On("GetNewsList", mock.Anything, models.NewsFilter{}, 10, 0).Return(expectedNews, nil)
 
 	req := httptest.NewRequest("GET", "/list", nil)
 	resp, _ := app.Test(req)
@@ -128,3 +234,107 @@ func TestGetNewsList(t *testing.T) {
 
 	mockService.AssertExpectations(t)
 }
+
+func TestGetNewsBySlug(t *testing.T) {
+	app := newTestApp()
+	mockService := new(MockNewsService)
+	handler := &NewsHandlers{Service: mockService}
+
+	app.Get("/news/by-slug/:slug", handler.GetNewsBySlug)
+
+	mockService.On("GetNewsBySlug", mock.Anything, "itogi-matcha").
+		Return(&models.News{Id: 1, Slug: "itogi-matcha", ContentSource: "**md**"}, "", nil)
+	mockService.On("GetNewsBySlug", mock.Anything, "staryy-zagolovok").Return(nil, "itogi-matcha", nil)
+	mockService.On("GetNewsBySlug", mock.Anything, "missing").Return(nil, "", &models.NotFoundError{Message: "News not found"})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/news/by-slug/itogi-matcha", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var result struct {
+		News models.News
+	}
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	assert.Equal(t, int64(1), result.News.Id)
+	assert.Empty(t, result.News.ContentSource)
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/news/by-slug/staryy-zagolovok?format=text", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusMovedPermanently, resp.StatusCode)
+	assert.Equal(t, "/news/by-slug/itogi-matcha?format=text", resp.Header.Get("Location"))
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/news/by-slug/missing", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestGetNewsListLocale(t *testing.T) {
+	app := newTestApp()
+	mockService := new(MockNewsService)
+	handler := &NewsHandlers{Service: mockService}
+
+	locales, err := locale.New([]string{"ru", "en"}, "")
+	assert.NoError(t, err)
+	app.Get("/list", middleware.Locale(locales), handler.GetNewsList)
+
+	withLocale := func(tag string) interface{} {
+		return mock.MatchedBy(func(ctx context.Context) bool { return services.LocaleFromContext(ctx) == tag })
+	}
+	mockService.On("GetNewsList", withLocale("en"), models.NewsFilter{}, 10, 0).Return([]models.News{}, nil).Once()
+	mockService.On("GetNewsList", withLocale("ru"), models.NewsFilter{}, 10, 0).Return([]models.News{}, nil).Once()
+
+	req := httptest.NewRequest("GET", "/list", nil)
+	req.Header.Set("Accept-Language", "de-DE, en-US;q=0.8, ru;q=0.5")
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "en", resp.Header.Get("Content-Language"))
+	assert.Equal(t, "Accept-Language", resp.Header.Get("Vary"))
+
+	req = httptest.NewRequest("GET", "/list?lang=ru", nil)
+	req.Header.Set("Accept-Language", "en")
+	resp, err = app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, "ru", resp.Header.Get("Content-Language"))
+
+	mockService.AssertExpectations(t)
+}
+
+func TestGetPopularNews(t *testing.T) {
+	app := newTestApp()
+	mockService := new(MockNewsService)
+	handler := &NewsHandlers{Service: mockService}
+
+	app.Get("/news/popular", handler.GetPopularNews)
+	app.Get("/private/news/popular", handler.GetPrivatePopularNews)
+
+	popular := func() []models.News { return []models.News{{Id: 7, Title: "Popular", Views: 42}} }
+	mockService.On("GetPopularNews", mock.Anything, "24h", int64(0), 10).Return(popular(), nil).Once()
+	mockService.On("GetPopularNews", mock.Anything, "7d", int64(3), 5).Return(popular(), nil).Once()
+	mockService.On("GetPopularNews", mock.Anything, "1y", int64(0), 10).
+		Return(nil, &models.ValidationError{Message: "Unsupported window: 1y"}).Once()
+
+	// число просмотров отдается только на закрытом эндпоинте
+	resp, err := app.Test(httptest.NewRequest("GET", "/news/popular", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	body, _ := io.ReadAll(resp.Body)
+	assert.NotContains(t, string(body), `"views"`)
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/private/news/popular?window=7d&category_id=3&limit=5", nil))
+	assert.NoError(t, err)
+	body, _ = io.ReadAll(resp.Body)
+	assert.Contains(t, string(body), `"views":42`)
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/news/popular?window=1y", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/news/popular?limit=100", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	mockService.AssertExpectations(t)
+}
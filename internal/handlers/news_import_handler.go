@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"go_news_server/internal/models"
+	"go_news_server/internal/services"
+	"io"
+	"mime"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type NewsImportHandler struct {
+	Service *services.NewsImportService
+	Logger  *zap.SugaredLogger
+}
+
+// Import импортирует новости из тела запроса в формате NDJSON или CSV.
+// Формат задается ?format или Content-Type, параметры: dry_run, create_missing_categories, batch_size.
+// POST /private/news/import
+func (h *NewsImportHandler) Import(c *fiber.Ctx) error {
+	format := c.Query("format")
+	if format == "" {
+		format = formatFromContentType(c.Get(fiber.HeaderContentType))
+	}
+	if !services.IsNewsFormat(format) {
+		return fiber.NewError(fiber.StatusBadRequest, "Unsupported import format, use ?format=ndjson or ?format=csv")
+	}
+
+	opts := models.NewsImportOptions{
+		DryRun:                  c.QueryBool("dry_run"),
+		CreateMissingCategories: c.QueryBool("create_missing_categories"),
+	}
+	opts.RequestId, _ = c.Locals(RequestIDKey).(string)
+	if batchStr := c.Query("batch_size"); batchStr != "" {
+		batch, err := strconv.Atoi(batchStr)
+		if err != nil || batch <= 0 {
+			return fiber.NewError(fiber.StatusBadRequest, "Invalid batch_size")
+		}
+		opts.BatchSize = batch
+	}
+
+	result, err := h.Service.Import(c.Context(), format, bytes.NewReader(c.Body()), opts)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"success": result.Failed == 0,
+		"result":  result,
+	})
+}
+
+// Export выгружает все новости с категориями в формате NDJSON (по умолчанию) или CSV
+// GET /private/news/export?format=ndjson|csv
+func (h *NewsImportHandler) Export(c *fiber.Ctx) error {
+	format := c.Query("format", models.FormatNDJSON)
+
+	var contentType string
+	switch format {
+	case models.FormatNDJSON:
+		contentType = "application/x-ndjson"
+	case models.FormatCSV:
+		contentType = "text/csv; charset=utf-8"
+	default:
+		return fiber.NewError(fiber.StatusBadRequest, "Unsupported export format: "+format)
+	}
+
+	filename := "news-" + time.Now().UTC().Format("20060102-150405") + "." + format
+	c.Set(fiber.HeaderContentType, contentType)
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="`+filename+`"`)
+
+	// Заголовки уже отправлены, поэтому ошибка посреди выгрузки только логируется, а ответ обрывается.
+	// Выгрузка идет после возврата из обработчика, поэтому у нее свой контекст: отключение клиента
+	// обнаруживается по ошибке записи и отменяет его вместе с запросом к базе.
+	ctx, cancel := context.WithCancel(context.Background())
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer cancel()
+
+		err := h.Service.Export(ctx, format, cancelOnErrorWriter{w: w, cancel: cancel})
+		if err == nil {
+			if err = w.Flush(); err != nil {
+				cancel()
+			}
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				h.Logger.Debugw("Export client disconnected", "error", err)
+				return
+			}
+			h.Logger.Errorw("News export failed", "format", format, "error", err)
+		}
+	})
+	return nil
+}
+
+// cancelOnErrorWriter отменяет контекст выгрузки при первой ошибке записи клиенту
+type cancelOnErrorWriter struct {
+	w      io.Writer
+	cancel context.CancelFunc
+}
+
+func (w cancelOnErrorWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	if err != nil {
+		w.cancel()
+	}
+	return n, err
+}
+
+// formatFromContentType определяет формат импорта по Content-Type
+func formatFromContentType(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+
+	switch mediaType {
+	case "application/x-ndjson", "application/ndjson", "application/jsonl", "application/x-jsonlines":
+		return models.FormatNDJSON
+	case "text/csv", "application/csv":
+		return models.FormatCSV
+	}
+	return ""
+}
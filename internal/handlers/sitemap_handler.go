@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"go_news_server/internal/services"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// sitemapTimeout ограничивает время формирования одного файла sitemap
+const sitemapTimeout = time.Minute
+
+type SitemapHandler struct {
+	Service *services.SitemapService
+	Logger  *zap.SugaredLogger
+}
+
+// GetSitemap возвращает sitemap всех страниц или индекс sitemap, если страниц больше 50 000
+// GET /sitemap.xml
+func (h *SitemapHandler) GetSitemap(c *fiber.Ctx) error {
+	useIndex, err := h.Service.UseIndex(c.Context())
+	if err != nil {
+		return err
+	}
+
+	if useIndex {
+		return h.stream(c, h.Service.WriteIndex)
+	}
+	return h.stream(c, h.Service.WriteURLSet)
+}
+
+// GetCategoriesSitemap возвращает sitemap категорий из диапазона ID
+// GET /sitemaps/categories-:chunk.xml
+func (h *SitemapHandler) GetCategoriesSitemap(c *fiber.Ctx) error {
+	chunk, err := strconv.ParseInt(c.Params("chunk"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid sitemap number")
+	}
+
+	exists, err := h.Service.HasCategoryChunk(c.Context(), chunk)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fiber.NewError(fiber.StatusNotFound, "Sitemap not found")
+	}
+
+	return h.stream(c, func(ctx context.Context, w io.Writer) error {
+		return h.Service.WriteCategoryChunk(ctx, chunk, w)
+	})
+}
+
+// GetNewsSitemap возвращает sitemap новостей из диапазона ID
+// GET /sitemaps/news-:chunk.xml
+func (h *SitemapHandler) GetNewsSitemap(c *fiber.Ctx) error {
+	chunk, err := strconv.ParseInt(c.Params("chunk"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid sitemap number")
+	}
+
+	exists, err := h.Service.HasNewsChunk(c.Context(), chunk)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fiber.NewError(fiber.StatusNotFound, "Sitemap not found")
+	}
+
+	return h.stream(c, func(ctx context.Context, w io.Writer) error {
+		return h.Service.WriteNewsChunk(ctx, chunk, w)
+	})
+}
+
+// stream отправляет sitemap по мере чтения из базы.
+// Заголовки уже отправлены, поэтому ошибка посреди файла только логируется, а ответ обрывается.
+func (h *SitemapHandler) stream(c *fiber.Ctx, write func(ctx context.Context, w io.Writer) error) error {
+	c.Set(fiber.HeaderContentType, "application/xml; charset=utf-8")
+	c.Set(fiber.HeaderCacheControl, "public, max-age=3600")
+
+	path := c.Path()
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		ctx, cancel := context.WithTimeout(context.Background(), sitemapTimeout)
+		defer cancel()
+
+		if err := write(ctx, w); err != nil {
+			h.Logger.Errorw("Failed to write sitemap", "path", path, "error", err)
+			return
+		}
+		if err := w.Flush(); err != nil {
+			h.Logger.Debugw("Sitemap client disconnected", "path", path, "error", err)
+		}
+	})
+	return nil
+}
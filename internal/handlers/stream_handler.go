@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"go_news_server/internal/services"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type StreamHandler struct {
+	Service *services.StreamService
+	// HeartbeatInterval - период отправки комментариев, которые не дают прокси закрыть соединение
+	HeartbeatInterval time.Duration
+}
+
+// StreamNews отправляет изменения новостей и категорий в формате Server-Sent Events.
+// Поддерживает возобновление по заголовку Last-Event-ID и фильтр ?category=1,2
+// GET /news/stream
+func (h *StreamHandler) StreamNews(c *fiber.Ctx) error {
+	categories := make(map[int64]struct{})
+	if categoryStr := c.Query("category"); categoryStr != "" {
+		for _, part := range strings.Split(categoryStr, ",") {
+			id, err := strconv.ParseInt(strings.TrimSpace(part), 10, 64)
+			if err != nil || id <= 0 {
+				return fiber.NewError(fiber.StatusBadRequest, "Invalid category filter")
+			}
+			categories[id] = struct{}{}
+		}
+	}
+
+	lastEventIDStr := c.Get("Last-Event-ID", c.Query("last_event_id"))
+	lastEventID, err := strconv.ParseUint(lastEventIDStr, 10, 64)
+	resume := lastEventIDStr != "" && err == nil
+
+	heartbeat := h.HeartbeatInterval
+	if heartbeat <= 0 {
+		heartbeat = 15 * time.Second
+	}
+
+	client, backlog, resync := h.Service.Subscribe(lastEventID, resume)
+	done := h.Service.Done()
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer h.Service.Unsubscribe(client)
+
+		fmt.Fprintf(w, "retry: %d\n\n", 3000)
+		if resync {
+			writeStreamEvent(w, services.StreamEvent{Type: services.StreamResync, OccurredAt: time.Now()})
+		}
+		for _, e := range backlog {
+			if e.Matches(categories) {
+				writeStreamEvent(w, e)
+			}
+		}
+		if err := w.Flush(); err != nil {
+			return
+		}
+
+		ticker := time.NewTicker(heartbeat)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case e, ok := <-client.C():
+				if !ok {
+					return
+				}
+				if !e.Matches(categories) {
+					continue
+				}
+				writeStreamEvent(w, e)
+			case <-ticker.C:
+				fmt.Fprint(w, ": heartbeat\n\n")
+			case <-done:
+				return
+			}
+
+			// Ошибка записи означает, что клиент отключился
+			if err := w.Flush(); err != nil {
+				return
+			}
+		}
+	})
+
+	return nil
+}
+
+// writeStreamEvent записывает событие в формате text/event-stream
+func writeStreamEvent(w *bufio.Writer, e services.StreamEvent) {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+
+	if e.ID > 0 {
+		fmt.Fprintf(w, "id: %d\n", e.ID)
+	}
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.Type, data)
+}
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"strconv"
+
+	"go_news_server/internal/models"
+	"go_news_server/internal/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type TagHandler struct {
+	tagService *services.TagService
+}
+
+func NewTagHandler(tagService *services.TagService) *TagHandler {
+	return &TagHandler{
+		tagService: tagService,
+	}
+}
+
+// GetTags получает используемые теги с числом новостей, ?prefix= - подсказки по началу названия
+// GET /tags
+func (h *TagHandler) GetTags(c *fiber.Ctx) error {
+	limit := 50
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 {
+			limit = l
+		}
+	}
+
+	tags, err := h.tagService.GetTags(c.Context(), c.Query("prefix"), limit)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(models.TagsResponse{
+		Success: true,
+		Tags:    tags,
+	})
+}
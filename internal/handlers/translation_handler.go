@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"context"
+	"go_news_server/internal/middleware"
+	"go_news_server/internal/models"
+	"go_news_server/internal/services"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type TranslationHandler struct {
+	Service *services.TranslationService
+}
+
+// GetNewsTranslations получает все переводы новости
+// GET /private/news/:id/translations
+func (h *TranslationHandler) GetNewsTranslations(c *fiber.Ctx) error {
+	newsID, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid news ID")
+	}
+
+	translations, err := h.Service.GetNewsTranslations(c.Context(), newsID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(models.NewsTranslationsResponse{
+		Success:      true,
+		Translations: translations,
+	})
+}
+
+// PutNewsTranslation создает или заменяет перевод новости на язык :locale
+// PUT /private/news/:id/translations/:locale
+func (h *TranslationHandler) PutNewsTranslation(c *fiber.Ctx) error {
+	newsID, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid news ID")
+	}
+
+	var req models.NewsTranslationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+	if err := ValidateRequest(&req); err != nil {
+		return err
+	}
+
+	// Политику очистки HTML задает маршрут, как при изменении новости
+	var ctx context.Context = c.Context()
+	if policy, ok := c.Locals(middleware.ContentPolicyKey).(string); ok {
+		ctx = services.WithContentPolicy(ctx, policy)
+	}
+
+	translation, err := h.Service.PutNewsTranslation(ctx, newsID, c.Params("locale"), &req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(models.NewsTranslationResponse{
+		Success:     true,
+		Translation: translation,
+	})
+}
+
+// DeleteNewsTranslation удаляет перевод новости
+// DELETE /private/news/:id/translations/:locale
+func (h *TranslationHandler) DeleteNewsTranslation(c *fiber.Ctx) error {
+	newsID, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid news ID")
+	}
+
+	if err := h.Service.DeleteNewsTranslation(c.Context(), newsID, c.Params("locale")); err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Translation deleted successfully",
+	})
+}
+
+// PutCategoryTranslation создает или заменяет перевод категории на язык :locale
+// PUT /categories/:id/translations/:locale
+func (h *TranslationHandler) PutCategoryTranslation(c *fiber.Ctx) error {
+	categoryID, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid category ID")
+	}
+
+	var req models.CategoryTranslationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+	if err := ValidateRequest(&req); err != nil {
+		return err
+	}
+
+	translation, err := h.Service.PutCategoryTranslation(c.Context(), categoryID, c.Params("locale"), &req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(models.CategoryTranslationResponse{
+		Success:     true,
+		Translation: translation,
+	})
+}
+
+// DeleteCategoryTranslation удаляет перевод категории
+// DELETE /categories/:id/translations/:locale
+func (h *TranslationHandler) DeleteCategoryTranslation(c *fiber.Ctx) error {
+	categoryID, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid category ID")
+	}
+
+	if err := h.Service.DeleteCategoryTranslation(c.Context(), categoryID, c.Params("locale")); err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Translation deleted successfully",
+	})
+}
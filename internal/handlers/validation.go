@@ -0,0 +1,24 @@
+package handlers
+
+import (
+	"go_news_server/internal/models"
+	"go_news_server/pkg/validate"
+)
+
+// ValidateRequest проверяет тело запроса по тегам validate и возвращает все ошибки полей одной models.ValidationError.
+// Некорректный тег возвращается как внутренняя ошибка.
+func ValidateRequest(req any) error {
+	errs, err := validate.Struct(req)
+	if err != nil {
+		return err
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+
+	fields := make([]models.FieldError, len(errs))
+	for i, err := range errs {
+		fields[i] = models.FieldError{Field: err.Field, Code: err.Code, Message: err.Message}
+	}
+	return &models.ValidationError{Message: "Request validation failed", Fields: fields}
+}
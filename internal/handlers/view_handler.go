@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"go_news_server/internal/models"
+	"go_news_server/internal/services"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ViewHandler struct {
+	Service *services.ViewService
+}
+
+// RecordView учитывает просмотр новости. Клиент определяется по адресу и User-Agent,
+// его повторные просмотры в пределах окна не учитываются. Просмотры записываются в базу пачками.
+// POST /news/:id/view
+func (h *ViewHandler) RecordView(c *fiber.Ctx) error {
+	newsID, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil || newsID <= 0 {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid news ID")
+	}
+
+	counted, err := h.Service.Record(c.Context(), newsID, c.IP()+"\x00"+c.Get(fiber.HeaderUserAgent))
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+		"success": true,
+		"counted": counted,
+	})
+}
+
+// GetNewsViews получает счетчики просмотров новости
+// GET /private/news/:id/views
+func (h *ViewHandler) GetNewsViews(c *fiber.Ctx) error {
+	newsID, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid news ID")
+	}
+
+	views, err := h.Service.GetNewsViews(c.Context(), newsID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(models.NewsViewsResponse{
+		Success: true,
+		Views:   views,
+	})
+}
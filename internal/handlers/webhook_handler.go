@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"strconv"
+
+	"go_news_server/internal/models"
+	"go_news_server/internal/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type WebhookHandler struct {
+	webhookService *services.WebhookService
+}
+
+func NewWebhookHandler(webhookService *services.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+	}
+}
+
+// CreateWebhook создает подписку. Секрет возвращается только в этом ответе.
+// POST /private/webhooks
+func (h *WebhookHandler) CreateWebhook(c *fiber.Ctx) error {
+	var req models.WebhookRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	webhook, err := h.webhookService.CreateWebhook(c.Context(), &req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"success": true,
+		"webhook": webhook,
+	})
+}
+
+// GetAllWebhooks возвращает все подписки
+// GET /private/webhooks
+func (h *WebhookHandler) GetAllWebhooks(c *fiber.Ctx) error {
+	webhooks, err := h.webhookService.GetAllWebhooks(c.Context())
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"success":  true,
+		"webhooks": webhooks,
+	})
+}
+
+// GetWebhookByID возвращает подписку по ID
+// GET /private/webhooks/:id
+func (h *WebhookHandler) GetWebhookByID(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid webhook ID")
+	}
+
+	webhook, err := h.webhookService.GetWebhookByID(c.Context(), id)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"webhook": webhook,
+	})
+}
+
+// UpdateWebhook обновляет подписку
+// PUT /private/webhooks/:id
+func (h *WebhookHandler) UpdateWebhook(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid webhook ID")
+	}
+
+	var req models.WebhookRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	webhook, err := h.webhookService.UpdateWebhook(c.Context(), id, &req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"webhook": webhook,
+	})
+}
+
+// DeleteWebhook удаляет подписку
+// DELETE /private/webhooks/:id
+func (h *WebhookHandler) DeleteWebhook(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid webhook ID")
+	}
+
+	if err := h.webhookService.DeleteWebhook(c.Context(), id); err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Webhook deleted successfully",
+	})
+}
+
+// GetDeliveries возвращает журнал доставок подписки
+// GET /private/webhooks/:id/deliveries
+func (h *WebhookHandler) GetDeliveries(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid webhook ID")
+	}
+
+	limit := 20
+	offset := 0
+
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 {
+			limit = l
+		}
+	}
+
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		if o, err := strconv.Atoi(offsetStr); err == nil && o >= 0 {
+			offset = o
+		}
+	}
+
+	deliveries, total, err := h.webhookService.GetDeliveries(c.Context(), id, c.Query("status"), limit, offset)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"success":    true,
+		"deliveries": deliveries,
+		"total":      total,
+	})
+}
+
+// RetryDelivery возвращает доставку из dead letter в очередь
+// POST /private/webhooks/deliveries/:id/retry
+func (h *WebhookHandler) RetryDelivery(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid delivery ID")
+	}
+
+	delivery, err := h.webhookService.RetryDelivery(c.Context(), id)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"success":  true,
+		"delivery": delivery,
+	})
+}
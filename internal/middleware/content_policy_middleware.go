@@ -0,0 +1,16 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// ContentPolicyKey ключ c.Locals с именем политики очистки HTML для маршрута
+const ContentPolicyKey = "contentPolicy"
+
+// ContentPolicy задает политику очистки HTML (sanitize.Strict, sanitize.Relaxed) для записи новостей в маршрутах группы
+func ContentPolicy(policy string) func(*fiber.Ctx) error {
+	return func(c *fiber.Ctx) error {
+		c.Locals(ContentPolicyKey, policy)
+		return c.Next()
+	}
+}
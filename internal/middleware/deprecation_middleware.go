@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Deprecated помечает устаревший маршрут заголовком Deprecation (RFC 9745) с датой since
+// и ссылкой Link rel="successor-version" на маршрут замены. Параметры successor вида :id
+// подставляются из параметров запроса устаревшего маршрута с тем же именем.
+func Deprecated(since time.Time, successor string) func(*fiber.Ctx) error {
+	deprecation := "@" + strconv.FormatInt(since.Unix(), 10)
+	segments := strings.Split(successor, "/")
+
+	return func(c *fiber.Ctx) error {
+		path := make([]string, len(segments))
+		for i, segment := range segments {
+			if name, ok := strings.CutPrefix(segment, ":"); ok {
+				segment = c.Params(name)
+			}
+			path[i] = segment
+		}
+
+		c.Set("Deprecation", deprecation)
+		c.Append(fiber.HeaderLink, "<"+strings.Join(path, "/")+`>; rel="successor-version"`)
+		return c.Next()
+	}
+}
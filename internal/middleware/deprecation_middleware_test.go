@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeprecated(t *testing.T) {
+	app := fiber.New()
+	since := time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+	app.Post("/edit/:Id", Deprecated(since, "/api/v1/news/:Id"), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	resp, err := app.Test(httptest.NewRequest("POST", "/edit/42", nil))
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.Equal(t, "@1792368000", resp.Header.Get("Deprecation"))
+	assert.Equal(t, `</api/v1/news/42>; rel="successor-version"`, resp.Header.Get(fiber.HeaderLink))
+}
@@ -1,31 +1,23 @@
 package middleware
 
 import (
+	"errors"
 	"fmt"
+	"go_news_server/internal/models"
+
 	jwtMiddleware "github.com/gofiber/contrib/jwt"
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang-jwt/jwt/v5"
 )
 
+// ReaderKey - ключ c.Locals с читателем (models.Reader), определенным по JWT
+const ReaderKey = "reader"
+
 // JWTProtected func for specify routes group with JWT authentication.
 // See: https://github.com/gofiber/contrib/jwt
 func JWTProtected(secretKey string) func(*fiber.Ctx) error {
-
-	token := jwt.New(jwt.SigningMethodHS256)
-	claims := make(jwt.MapClaims)
-
-	claims["sub"] = "5"
-	claims["name"] = "akozadaev"
-
-	token.Claims = claims
-	signature := []byte(secretKey)
-	fmt.Println("signature : ", signature)
-	tokenString, err := token.SignedString(signature)
-	fmt.Println(tokenString)
-	fmt.Println(err)
-
 	config := jwtMiddleware.Config{
-		SigningKey:   jwtMiddleware.SigningKey{Key: signature},
+		SigningKey:   jwtMiddleware.SigningKey{Key: []byte(secretKey)},
 		ContextKey:   "jwt", // used in private routes
 		ErrorHandler: jwtError,
 	}
@@ -33,18 +25,45 @@ func JWTProtected(secretKey string) func(*fiber.Ctx) error {
 	return jwtMiddleware.New(config)
 }
 
-func jwtError(c *fiber.Ctx, err error) error {
-	// Return status 401 and failed authentication error.
-	if err.Error() == "Missing or malformed JWT" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": true,
-			"msg":   err.Error(),
-		})
+// Reader проверяет JWT читателя (HS256) и сохраняет в c.Locals(ReaderKey) читателя из claims:
+// sub - ID читателя (обязателен), name - отображаемое имя
+func Reader(secretKey string) func(*fiber.Ctx) error {
+	return jwtMiddleware.New(jwtMiddleware.Config{
+		SigningKey:     jwtMiddleware.SigningKey{JWTAlg: jwtMiddleware.HS256, Key: []byte(secretKey)},
+		ContextKey:     "jwt",
+		ErrorHandler:   jwtError,
+		SuccessHandler: readerFromToken,
+	})
+}
+
+func readerFromToken(c *fiber.Ctx) error {
+	token, _ := c.Locals("jwt").(*jwt.Token)
+	var claims jwt.MapClaims
+	if token != nil {
+		claims, _ = token.Claims.(jwt.MapClaims)
 	}
 
-	// Return status 401 and failed authentication error.
-	return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-		"error": true,
-		"msg":   err.Error(),
-	})
+	var reader models.Reader
+	switch sub := claims["sub"].(type) {
+	case string:
+		reader.Id = sub
+	case float64:
+		// числовой sub допустим, хотя RFC 7519 требует строку
+		reader.Id = fmt.Sprintf("%.0f", sub)
+	}
+	if reader.Id == "" {
+		return jwtError(c, fmt.Errorf("token has no subject"))
+	}
+	reader.Name, _ = claims["name"].(string)
+
+	c.Locals(ReaderKey, reader)
+	return c.Next()
+}
+
+// jwtError возвращает ошибку для общего обработчика ошибок: 400 без токена, 401 для недействительного токена
+func jwtError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, jwtMiddleware.ErrJWTMissingOrMalformed) {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	return fiber.NewError(fiber.StatusUnauthorized, err.Error())
 }
@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"go_news_server/internal/models"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReader(t *testing.T) {
+	app := fiber.New()
+	app.Post("/comments", Reader("reader-secret"), func(c *fiber.Ctx) error {
+		reader := c.Locals(ReaderKey).(models.Reader)
+		return c.SendString(reader.Id + "/" + reader.Name)
+	})
+
+	sign := func(method jwt.SigningMethod, key interface{}, claims jwt.MapClaims) string {
+		token, err := jwt.NewWithClaims(method, claims).SignedString(key)
+		assert.NoError(t, err)
+		return token
+	}
+	secret := []byte("reader-secret")
+
+	tests := []struct {
+		name   string
+		token  string
+		status int
+		body   string
+	}{
+		{"без токена", "", fiber.StatusBadRequest, ""},
+		{"строковый sub", sign(jwt.SigningMethodHS256, secret, jwt.MapClaims{"sub": "r1", "name": "Анна"}), fiber.StatusOK, "r1/Анна"},
+		{"числовой sub", sign(jwt.SigningMethodHS256, secret, jwt.MapClaims{"sub": 42}), fiber.StatusOK, "42/"},
+		{"без sub", sign(jwt.SigningMethodHS256, secret, jwt.MapClaims{"name": "Анна"}), fiber.StatusUnauthorized, ""},
+		{"чужой ключ", sign(jwt.SigningMethodHS256, []byte("other"), jwt.MapClaims{"sub": "r1"}), fiber.StatusUnauthorized, ""},
+		{"другой алгоритм", sign(jwt.SigningMethodHS512, secret, jwt.MapClaims{"sub": "r1"}), fiber.StatusUnauthorized, ""},
+		{"истекший токен", sign(jwt.SigningMethodHS256, secret, jwt.MapClaims{"sub": "r1", "exp": 1}), fiber.StatusUnauthorized, ""},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest("POST", "/comments", nil)
+		if tt.token != "" {
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+		}
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		assert.Equal(t, tt.status, resp.StatusCode, tt.name)
+		if tt.status == fiber.StatusOK {
+			assert.Equal(t, tt.body, string(body), tt.name)
+		}
+	}
+}
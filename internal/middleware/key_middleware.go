@@ -18,5 +18,9 @@ func KeyProtected(secretKey string) func(*fiber.Ctx) error {
 			}
 			return false, keyauth.ErrMissingOrMalformedAPIKey
 		},
+		// ответ формирует общий обработчик ошибок
+		ErrorHandler: func(c *fiber.Ctx, err error) error {
+			return fiber.NewError(fiber.StatusUnauthorized, err.Error())
+		},
 	})
 }
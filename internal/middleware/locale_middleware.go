@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"go_news_server/pkg/locale"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// LocaleKey ключ c.Locals с языком ответа
+const LocaleKey = "locale"
+
+// Locale выбирает язык ответа по ?lang и Accept-Language, сохраняет его в c.Locals(LocaleKey)
+// и указывает в Content-Language. Ответ зависит от Accept-Language, что сообщается кэшам через Vary.
+func Locale(locales *locale.Locales) func(*fiber.Ctx) error {
+	return func(c *fiber.Ctx) error {
+		tag := locales.Negotiate(c.Query("lang"), c.Get(fiber.HeaderAcceptLanguage))
+		c.Locals(LocaleKey, tag)
+		c.Set(fiber.HeaderContentLanguage, tag)
+		c.Vary(fiber.HeaderAcceptLanguage)
+		return c.Next()
+	}
+}
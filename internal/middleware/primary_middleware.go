@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"go_news_server/pkg/database"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// PrimaryForWrites направляет все запросы к базе из изменяющих HTTP запросов (кроме GET, HEAD и OPTIONS)
+// на primary: проверки перед записью и чтение после нее не должны попасть на отстающую реплику.
+// Обработчики передают в сервисы c.Context(), значение Locals доступно через его Value.
+func PrimaryForWrites() func(*fiber.Ctx) error {
+	return func(c *fiber.Ctx) error {
+		switch c.Method() {
+		case fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions:
+		default:
+			c.Locals(database.PrimaryKey{}, true)
+		}
+		return c.Next()
+	}
+}
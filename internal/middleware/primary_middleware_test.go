@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"context"
+	"go_news_server/pkg/database"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrimaryForWrites(t *testing.T) {
+	app := fiber.New()
+	app.Use(PrimaryForWrites())
+	app.All("/news", func(c *fiber.Ctx) error {
+		// сервисы получают контекст с переводом или политикой поверх c.Context()
+		ctx := context.WithValue(c.Context(), struct{}{}, "locale")
+		forced, _ := ctx.Value(database.PrimaryKey{}).(bool)
+		return c.JSON(forced)
+	})
+
+	for method, want := range map[string]string{"GET": "false", "HEAD": "", "POST": "true", "PATCH": "true", "DELETE": "true"} {
+		resp, err := app.Test(httptest.NewRequest(method, "/news", nil))
+		assert.NoError(t, err)
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		assert.Equal(t, want, string(body), method)
+	}
+}
@@ -6,23 +6,54 @@ import (
 
 // Category представляет категорию новостей
 type Category struct {
-	Id          int64     `json:"id" db:"Id"`
-	Name        string    `json:"name" db:"Name"`
-	Description string    `json:"description" db:"Description"`
-	CreatedAt   time.Time `json:"created_at" db:"CreatedAt"`
-	UpdatedAt   time.Time `json:"updated_at" db:"UpdatedAt"`
+	Id          int64  `json:"id" db:"Id"`
+	Name        string `json:"name" db:"Name"`
+	Slug        string `json:"slug" db:"Slug"`
+	Description string `json:"description" db:"Description"`
+	// ParentId - родительская категория, nil у корневых
+	ParentId *int64 `json:"parent_id" db:"ParentId"`
+	// Position - порядок среди категорий с тем же родителем, начиная с 0
+	Position  int       `json:"position" db:"Position"`
+	CreatedAt time.Time `json:"created_at" db:"CreatedAt"`
+	UpdatedAt time.Time `json:"updated_at" db:"UpdatedAt"`
+	// Locale - язык Name и Description в ответе с выбором языка
+	Locale string `json:"locale,omitempty"`
+}
+
+// CategoryNode - категория с дочерними категориями в дереве
+type CategoryNode struct {
+	Category
+	Children []*CategoryNode `json:"children"`
 }
 
 // CategoryCreateRequest представляет запрос на создание категории
 type CategoryCreateRequest struct {
 	Name        string `json:"name" validate:"required,min=1,max=100"`
 	Description string `json:"description"`
+	// ParentId - родительская категория, новая категория добавляется последней среди ее дочерних
+	ParentId *int64 `json:"parent_id"`
 }
 
 // CategoryUpdateRequest представляет запрос на обновление категории
 type CategoryUpdateRequest struct {
 	Name        string `json:"name" validate:"required,min=1,max=100"`
 	Description string `json:"description"`
+	// ParentId - новый родитель: не задан - без изменений, 0 - перенос в корень
+	ParentId *int64 `json:"parent_id"`
+}
+
+// CategoryMoveRequest представляет запрос на перенос категории в дереве
+type CategoryMoveRequest struct {
+	// ParentId - новый родитель, null или 0 - корень
+	ParentId *int64 `json:"parent_id"`
+	// Position - позиция среди дочерних категорий нового родителя, не задана - последней
+	Position *int `json:"position"`
+}
+
+// CategoryTreeResponse представляет ответ с деревом категорий
+type CategoryTreeResponse struct {
+	Success    bool            `json:"success"`
+	Categories []*CategoryNode `json:"categories"`
 }
 
 // CategoryResponse представляет ответ с категорией
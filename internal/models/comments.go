@@ -0,0 +1,67 @@
+package models
+
+import "time"
+
+// Статусы комментариев
+const (
+	CommentStatusPending  = "pending"
+	CommentStatusApproved = "approved"
+	CommentStatusRejected = "rejected"
+)
+
+// Comment представляет комментарий читателя к новости. Content - текст без разметки, при выводе его нужно экранировать.
+type Comment struct {
+	Id     int64 `json:"id"`
+	NewsId int64 `json:"news_id"`
+	// ParentId - комментарий, на который дан ответ; nil - комментарий верхнего уровня
+	ParentId *int64 `json:"parent_id"`
+	UserId   string `json:"user_id"`
+	UserName string `json:"user_name"`
+	Content  string `json:"content"`
+	Status   string `json:"status"`
+	// FilterReason - причина, по которой фильтр отправил комментарий на модерацию: blocked_word или link
+	FilterReason string     `json:"filter_reason,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	ModeratedAt  *time.Time `json:"moderated_at,omitempty"`
+	// Replies - одобренные ответы по времени создания, заполняются в ветках комментариев новости
+	Replies []*Comment `json:"replies,omitempty"`
+}
+
+// Reader - читатель, определенный по JWT
+type Reader struct {
+	Id   string
+	Name string
+}
+
+// CommentRequest представляет запрос на создание комментария
+type CommentRequest struct {
+	Content  string `json:"content"`
+	ParentId *int64 `json:"parent_id"`
+}
+
+// CommentBanRequest представляет запрос на блокировку автора комментария
+type CommentBanRequest struct {
+	Reason string `json:"reason"`
+}
+
+// CommentResponse представляет ответ с комментарием
+type CommentResponse struct {
+	Success bool     `json:"success"`
+	Comment *Comment `json:"comment"`
+}
+
+// CommentsResponse представляет страницу комментариев. NextCursor передается в ?cursor= для следующей страницы,
+// пустой - страниц больше нет.
+type CommentsResponse struct {
+	Success    bool       `json:"success"`
+	Comments   []*Comment `json:"comments"`
+	NextCursor string     `json:"next_cursor"`
+}
+
+// CommentBanResponse представляет ответ на блокировку автора комментария
+type CommentBanResponse struct {
+	Success bool   `json:"success"`
+	UserId  string `json:"user_id"`
+	// Rejected - число отклоненных комментариев автора, ожидавших модерации, включая заблокированный
+	Rejected int64 `json:"rejected"`
+}
@@ -1,19 +1,104 @@
 package models
 
-// ValidationError представляет ошибку валидации
+import "time"
+
+// Коды ошибок в поле code ответа об ошибке (application/problem+json)
+const (
+	CodeBadRequest           = "bad_request"
+	CodeValidationFailed     = "validation_failed"
+	CodeUnauthorized         = "unauthorized"
+	CodeForbidden            = "forbidden"
+	CodeNotFound             = "not_found"
+	CodeMethodNotAllowed     = "method_not_allowed"
+	CodeConflict             = "conflict"
+	CodePayloadTooLarge      = "payload_too_large"
+	CodeUnsupportedMediaType = "unsupported_media_type"
+	CodeRateLimited          = "rate_limited"
+	CodeInternal             = "internal_error"
+	CodeUnavailable          = "service_unavailable"
+)
+
+// Problem - ответ об ошибке по RFC 7807
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	// Code - машиночитаемый код ошибки, см. Code*
+	Code string `json:"code"`
+	// Errors - ошибки отдельных полей запроса
+	Errors    []FieldError `json:"errors,omitempty"`
+	RequestId string       `json:"request_id,omitempty"`
+}
+
+// Коды ошибок полей, дополняют коды правил pkg/validate (required, min, max, oneof)
+const (
+	FieldCodeNotFound = "not_found"
+	FieldCodeInvalid  = "invalid"
+)
+
+// FieldError - ошибка в поле запроса
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// ValidationError представляет ошибку валидации. Code по умолчанию - CodeValidationFailed,
+// Fields - ошибки отдельных полей.
 type ValidationError struct {
 	Message string
+	Code    string
+	Fields  []FieldError
 }
 
 func (e *ValidationError) Error() string {
 	return e.Message
 }
 
+// NewFieldError возвращает ошибку валидации одного поля запроса
+func NewFieldError(field, code, message string) *ValidationError {
+	return &ValidationError{Message: message, Fields: []FieldError{{Field: field, Code: code, Message: message}}}
+}
+
 // NotFoundError представляет ошибку "не найдено"
 type NotFoundError struct {
 	Message string
+	Code    string
 }
 
 func (e *NotFoundError) Error() string {
 	return e.Message
 }
+
+// ConflictError представляет конфликт с существующими данными, например занятое имя
+type ConflictError struct {
+	Message string
+	Code    string
+}
+
+func (e *ConflictError) Error() string {
+	return e.Message
+}
+
+// ForbiddenError представляет ошибку "действие запрещено"
+type ForbiddenError struct {
+	Message string
+	Code    string
+}
+
+func (e *ForbiddenError) Error() string {
+	return e.Message
+}
+
+// RateLimitError представляет превышение ограничения частоты запросов, RetryAfter - через сколько можно повторить
+type RateLimitError struct {
+	Message    string
+	Code       string
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return e.Message
+}
@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// NewsMedia представляет изображение новости. URL формируются хранилищем при выдаче.
+type NewsMedia struct {
+	Id              int64     `json:"id"`
+	NewsId          int64     `json:"news_id"`
+	URL             string    `json:"url"`
+	ThumbnailURL    string    `json:"thumbnail_url"`
+	ContentType     string    `json:"content_type"`
+	Size            int64     `json:"size"`
+	Width           int       `json:"width"`
+	Height          int       `json:"height"`
+	ThumbnailWidth  int       `json:"thumbnail_width"`
+	ThumbnailHeight int       `json:"thumbnail_height"`
+	Alt             string    `json:"alt"`
+	Position        int       `json:"position"`
+	IsCover         bool      `json:"is_cover"`
+	CreatedAt       time.Time `json:"created_at"`
+	StorageKey      string    `json:"-"`
+	ThumbnailKey    string    `json:"-"`
+}
+
+// NewsMediaUpdateRequest представляет запрос на изменение изображения, незаданные поля не меняются
+type NewsMediaUpdateRequest struct {
+	Alt *string `json:"alt"`
+	// Position - новая позиция среди изображений новости с 0
+	Position *int `json:"position"`
+	// IsCover - true делает изображение обложкой вместо прежней, false снимает обложку
+	IsCover *bool `json:"is_cover"`
+}
+
+// NewsMediaResponse представляет ответ с изображением новости
+type NewsMediaResponse struct {
+	Success bool       `json:"success"`
+	Media   *NewsMedia `json:"media,omitempty"`
+	Message string     `json:"message,omitempty"`
+}
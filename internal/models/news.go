@@ -1,8 +1,65 @@
 package models
 
+import "time"
+
+// Форматы содержимого новости в ответах (?format=)
+const (
+	ContentFormatHTML           = "html"
+	ContentFormatText           = "text"
+	ContentFormatMarkdownSource = "markdown-source"
+)
+
+// Форматы, в которых автор передает содержимое новости (ContentFormat)
+const (
+	SourceFormatHTML     = "html"
+	SourceFormatMarkdown = "markdown"
+)
+
 type News struct {
-	Id         int64   `json:"id"`
-	Title      string  `json:"title"`
+	Id    int64  `json:"id"`
+	Title string `json:"title"`
+	// Slug - адрес новости в URL, формируется из Title
+	Slug       string  `json:"slug"`
 	Content    string  `json:"content"`
 	Categories []int64 `json:"categories"`
+	// Tags - нормализованные теги в алфавитном порядке; nil при записи - теги не меняются
+	Tags      []string  `json:"tags"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	// ContentFormat - формат содержимого, переданного автором: html или markdown
+	ContentFormat string `json:"content_format"`
+	// ContentSource - исходный Markdown, Content при этом хранит сформированный из него HTML
+	ContentSource string `json:"content_source,omitempty"`
+	// ContentText - текст Content без разметки для поиска и лент
+	ContentText string `json:"-"`
+	// Media - изображения новости по порядку, заполняются только в ответах API
+	Media []NewsMedia `json:"media,omitempty"`
+	// Locale - язык Title и Content в ответе с выбором языка, с учетом замены отсутствующего перевода
+	Locale string `json:"locale,omitempty"`
+	// Views - просмотры за период в списке популярных новостей или за все время в закрытом списке,
+	// отдаются только на закрытых эндпоинтах
+	Views int64 `json:"views,omitempty"`
+}
+
+// NewsEditRequest - тело POST /edit/:Id, незаданные поля не меняются
+type NewsEditRequest struct {
+	// Id не используется: ID новости берется из пути
+	Id      int64   `json:"Id"`
+	Title   *string `json:"Title" validate:"omitempty,max=255"`
+	Content *string `json:"Content"`
+	// ContentFormat - html или markdown, по умолчанию формат, в котором новость хранится сейчас
+	ContentFormat *string `json:"ContentFormat" validate:"omitempty,oneof=html markdown"`
+	Categories    []int64 `json:"Categories" validate:"dive,min=1"`
+	// Tags - теги новости, отсутствующие создаются; не задано - теги не меняются, [] - удаляются
+	Tags []string `json:"Tags"`
+}
+
+// NewsFilter - условия выборки списка новостей, нулевые значения не ограничивают выборку
+type NewsFilter struct {
+	// CategoryID - новости категории и всех ее дочерних категорий
+	CategoryID int64
+	// Tag - новости с тегом, название нормализуется
+	Tag string
+	// AfterID - новости с ID больше AfterID: курсор страницы списка в порядке ID
+	AfterID int64
 }
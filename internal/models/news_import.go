@@ -0,0 +1,64 @@
+package models
+
+import "time"
+
+// Форматы импорта и экспорта новостей
+const (
+	FormatNDJSON = "ndjson"
+	FormatCSV    = "csv"
+)
+
+// NewsImportRow строка импорта новостей. Категории задаются ID или названиями.
+// Если Id задан, новость с этим ID создается или заменяется.
+type NewsImportRow struct {
+	Line          int
+	Id            int64
+	Title         string
+	Content       string
+	ContentFormat string
+	CreatedAt     time.Time
+	CategoryIds   []int64
+	CategoryNames []string
+}
+
+// NewsImportOptions параметры импорта
+type NewsImportOptions struct {
+	// DryRun - проверить и выполнить импорт, но откатить все изменения
+	DryRun bool
+	// CreateMissingCategories - создавать категории, заданные названием и отсутствующие в базе
+	CreateMissingCategories bool
+	// BatchSize - количество строк в одной транзакции
+	BatchSize int
+	// RequestId - ID запроса для журнала ошибок, пустой при импорте командой import
+	RequestId string
+}
+
+// NewsImportError ошибка в строке импорта
+type NewsImportError struct {
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+// NewsImportResult результат импорта
+type NewsImportResult struct {
+	DryRun            bool              `json:"dry_run"`
+	Total             int               `json:"total"`
+	Created           int               `json:"created"`
+	Updated           int               `json:"updated"`
+	Failed            int               `json:"failed"`
+	CreatedCategories []string          `json:"created_categories"`
+	Errors            []NewsImportError `json:"errors"`
+}
+
+// NewsExportRecord новость в формате экспорта; категории передаются названиями,
+// чтобы файл можно было импортировать в базу с другими ID категорий.
+// Для Markdown новостей Content содержит исходный Markdown.
+type NewsExportRecord struct {
+	Id            int64     `json:"id"`
+	Title         string    `json:"title"`
+	Content       string    `json:"content"`
+	ContentFormat string    `json:"content_format"`
+	Categories    []string  `json:"categories"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
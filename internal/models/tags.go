@@ -0,0 +1,43 @@
+package models
+
+import "strings"
+
+// Tag представляет тег новостей с числом новостей, которым он назначен
+type Tag struct {
+	Id    int64  `json:"id"`
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+}
+
+// TagsResponse представляет ответ со списком тегов
+type TagsResponse struct {
+	Success bool  `json:"success"`
+	Tags    []Tag `json:"tags"`
+}
+
+// NormalizeTag приводит название тега к виду, в котором он хранится:
+// нижний регистр, без "#" в начале, пробелы внутри схлопываются в один
+func NormalizeTag(name string) string {
+	name = strings.TrimLeft(strings.TrimSpace(name), "#")
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// NormalizeTags нормализует теги, удаляя пустые и повторы с сохранением порядка.
+// Для nil возвращает nil: теги не переданы и не меняются.
+func NormalizeTags(tags []string) []string {
+	if tags == nil {
+		return nil
+	}
+
+	seen := make(map[string]bool, len(tags))
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = NormalizeTag(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	return normalized
+}
@@ -0,0 +1,16 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	assert.Equal(t, "new york", NormalizeTag("  #New   York "))
+	assert.Equal(t, "выборы", NormalizeTag("##Выборы"))
+
+	assert.Equal(t, []string{"elections", "moscow"}, NormalizeTags([]string{"Elections", " moscow", "#elections", "", "  "}))
+	assert.Equal(t, []string{}, NormalizeTags([]string{}))
+	assert.Nil(t, NormalizeTags(nil))
+}
@@ -0,0 +1,61 @@
+package models
+
+import "time"
+
+// NewsTranslation представляет перевод новости на язык Locale
+type NewsTranslation struct {
+	NewsId int64  `json:"news_id"`
+	Locale string `json:"locale"`
+	Title  string `json:"title"`
+	// Content - очищенный HTML, для Markdown сформированный из ContentSource
+	Content       string    `json:"content"`
+	ContentFormat string    `json:"content_format"`
+	ContentSource string    `json:"content_source,omitempty"`
+	ContentText   string    `json:"-"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// CategoryTranslation представляет перевод категории на язык Locale
+type CategoryTranslation struct {
+	CategoryId  int64     `json:"category_id"`
+	Locale      string    `json:"locale"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// NewsTranslationRequest представляет запрос на создание или замену перевода новости
+type NewsTranslationRequest struct {
+	Title   string `json:"title" validate:"required,max=255"`
+	Content string `json:"content" validate:"required"`
+	// ContentFormat - html (по умолчанию) или markdown
+	ContentFormat string `json:"content_format" validate:"omitempty,oneof=html markdown"`
+}
+
+// CategoryTranslationRequest представляет запрос на создание или замену перевода категории
+type CategoryTranslationRequest struct {
+	Name        string `json:"name" validate:"required,max=100"`
+	Description string `json:"description"`
+}
+
+// NewsTranslationResponse представляет ответ с переводом новости
+type NewsTranslationResponse struct {
+	Success     bool             `json:"success"`
+	Translation *NewsTranslation `json:"translation,omitempty"`
+	Message     string           `json:"message,omitempty"`
+}
+
+// NewsTranslationsResponse представляет ответ со всеми переводами новости
+type NewsTranslationsResponse struct {
+	Success      bool              `json:"success"`
+	Translations []NewsTranslation `json:"translations"`
+}
+
+// CategoryTranslationResponse представляет ответ с переводом категории
+type CategoryTranslationResponse struct {
+	Success     bool                 `json:"success"`
+	Translation *CategoryTranslation `json:"translation,omitempty"`
+	Message     string               `json:"message,omitempty"`
+}
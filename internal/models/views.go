@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// NewsViewCount - число просмотров новости за час, начинающийся в Hour
+type NewsViewCount struct {
+	NewsId int64
+	Hour   time.Time
+	Count  int64
+}
+
+// NewsViews представляет счетчики просмотров новости
+type NewsViews struct {
+	NewsId int64 `json:"news_id"`
+	// Total - просмотры за все время
+	Total int64 `json:"total"`
+	// Last24h и Last7d - просмотры за последние сутки и неделю с точностью до часа
+	Last24h int64 `json:"last_24h"`
+	Last7d  int64 `json:"last_7d"`
+	// Pending - просмотры, еще не записанные в базу; они уже учтены в остальных счетчиках
+	Pending int64 `json:"pending"`
+}
+
+// NewsViewsResponse представляет ответ со счетчиками просмотров новости
+type NewsViewsResponse struct {
+	Success bool       `json:"success"`
+	Views   *NewsViews `json:"views"`
+}
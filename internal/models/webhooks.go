@@ -0,0 +1,82 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Типы событий, на которые можно подписаться вебхуком
+const (
+	EventNewsCreated     = "news.created"
+	EventNewsUpdated     = "news.updated"
+	EventCategoryCreated = "category.created"
+	EventCategoryUpdated = "category.updated"
+	EventCategoryDeleted = "category.deleted"
+)
+
+// WebhookEventTypes список всех типов событий вебхуков
+var WebhookEventTypes = []string{EventNewsCreated, EventNewsUpdated, EventCategoryCreated, EventCategoryUpdated, EventCategoryDeleted}
+
+// Статусы доставки вебхука
+const (
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	// DeliveryDead - исчерпаны попытки доставки, событие перенесено в dead letter
+	DeliveryDead = "dead"
+)
+
+// Webhook подписка партнера на события
+type Webhook struct {
+	Id          int64     `json:"id"`
+	Url         string    `json:"url"`
+	Secret      string    `json:"secret,omitempty"`
+	EventTypes  []string  `json:"event_types"`
+	CategoryIds []int64   `json:"category_ids"`
+	Active      bool      `json:"active"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// WebhookRequest представляет запрос на создание или обновление подписки.
+// Пустой EventTypes означает все события, пустой CategoryIds - все категории.
+type WebhookRequest struct {
+	Url         string   `json:"url"`
+	Secret      string   `json:"secret"`
+	EventTypes  []string `json:"event_types"`
+	CategoryIds []int64  `json:"category_ids"`
+	Active      *bool    `json:"active"`
+}
+
+// OutboxEvent событие, записанное в outbox вместе с изменением данных
+type OutboxEvent struct {
+	Id          int64
+	EventType   string
+	CategoryIds []int64
+	Payload     json.RawMessage
+	CreatedAt   time.Time
+}
+
+// WebhookDelivery запись журнала доставки
+type WebhookDelivery struct {
+	Id             int64     `json:"id"`
+	WebhookId      int64     `json:"webhook_id"`
+	OutboxId       int64     `json:"event_id"`
+	EventType      string    `json:"event_type"`
+	Status         string    `json:"status"`
+	Attempts       int       `json:"attempts"`
+	NextAttemptAt  time.Time `json:"next_attempt_at"`
+	LastStatusCode *int      `json:"last_status_code,omitempty"`
+	LastError      *string   `json:"last_error,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// WebhookTask доставка, взятая в работу обработчиком, вместе с подпиской и телом события
+type WebhookTask struct {
+	Delivery WebhookDelivery
+	Url      string
+	Secret   string
+	Payload  json.RawMessage
+	// CreatedAt - время записи события в outbox
+	CreatedAt time.Time
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"database/sql"
 	"go_news_server/internal/models"
+	"time"
 
+	"github.com/lib/pq"
 	"gopkg.in/reform.v1"
 )
 
@@ -12,25 +14,43 @@ type CategoryRepository struct {
 	DB *reform.DB
 }
 
-// CreateCategory создает новую категорию
+// CreateCategory создает новую категорию последней среди дочерних категорий category.ParentId
+// и записывает событие для вебхуков в outbox
 func (r *CategoryRepository) CreateCategory(ctx context.Context, category *models.Category) error {
 	query := `
-		INSERT INTO "Categories" ("Name", "Description") 
-		VALUES ($1, $2) 
-		RETURNING "Id", "CreatedAt", "UpdatedAt"
+		INSERT INTO "Categories" ("Name", "Description", "Slug", "ParentId", "Position") 
+		VALUES ($1, $2, $3, $4, (SELECT COALESCE(MAX("Position") + 1, 0) FROM "Categories" WHERE "ParentId" IS NOT DISTINCT FROM $4)) 
+		RETURNING "Id", "Position", "CreatedAt", "UpdatedAt"
 	`
 
-	return r.DB.QueryRowContext(ctx, query, category.Name, category.Description).
-		Scan(&category.Id, &category.CreatedAt, &category.UpdatedAt)
+	return r.DB.InTransactionContext(ctx, nil, func(tx *reform.TX) error {
+		if err := lockCategoryTree(tx.Querier); err != nil {
+			return err
+		}
+
+		slug, err := categorySlugs.free(tx.Querier, 0, categorySlugs.base(category.Name))
+		if err != nil {
+			return err
+		}
+
+		err = tx.QueryRow(query, category.Name, category.Description, slug, category.ParentId).
+			Scan(&category.Id, &category.Position, &category.CreatedAt, &category.UpdatedAt)
+		if err != nil {
+			return err
+		}
+		category.Slug = slug
+
+		return insertOutboxEvent(tx.Querier, models.EventCategoryCreated, []int64{category.Id}, category)
+	})
 }
 
 // GetCategoryByID получает категорию по ID
 func (r *CategoryRepository) GetCategoryByID(ctx context.Context, id int64) (*models.Category, error) {
-	query := `SELECT "Id", "Name", "Description", "CreatedAt", "UpdatedAt" FROM "Categories" WHERE "Id" = $1`
+	query := `SELECT "Id", "Name", "Slug", "Description", "ParentId", "Position", "CreatedAt", "UpdatedAt" FROM "Categories" WHERE "Id" = $1`
 
 	var category models.Category
-	err := r.DB.QueryRowContext(ctx, query, id).
-		Scan(&category.Id, &category.Name, &category.Description, &category.CreatedAt, &category.UpdatedAt)
+	err := r.DB.QuerierFromContext(ctx).QueryRow(query, id).
+		Scan(&category.Id, &category.Name, &category.Slug, &category.Description, &category.ParentId, &category.Position, &category.CreatedAt, &category.UpdatedAt)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -44,11 +64,29 @@ func (r *CategoryRepository) GetCategoryByID(ctx context.Context, id int64) (*mo
 
 // GetCategoryByName получает категорию по имени
 func (r *CategoryRepository) GetCategoryByName(ctx context.Context, name string) (*models.Category, error) {
-	query := `SELECT "Id", "Name", "Description", "CreatedAt", "UpdatedAt" FROM "Categories" WHERE "Name" = $1`
+	query := `SELECT "Id", "Name", "Slug", "Description", "ParentId", "Position", "CreatedAt", "UpdatedAt" FROM "Categories" WHERE "Name" = $1`
+
+	var category models.Category
+	err := r.DB.QuerierFromContext(ctx).QueryRow(query, name).
+		Scan(&category.Id, &category.Name, &category.Slug, &category.Description, &category.ParentId, &category.Position, &category.CreatedAt, &category.UpdatedAt)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &category, nil
+}
+
+// GetCategoryBySlug получает категорию по текущему slug
+func (r *CategoryRepository) GetCategoryBySlug(ctx context.Context, slug string) (*models.Category, error) {
+	query := `SELECT "Id", "Name", "Slug", "Description", "ParentId", "Position", "CreatedAt", "UpdatedAt" FROM "Categories" WHERE "Slug" = $1`
 
 	var category models.Category
-	err := r.DB.QueryRowContext(ctx, query, name).
-		Scan(&category.Id, &category.Name, &category.Description, &category.CreatedAt, &category.UpdatedAt)
+	err := r.DB.QuerierFromContext(ctx).QueryRow(query, slug).
+		Scan(&category.Id, &category.Name, &category.Slug, &category.Description, &category.ParentId, &category.Position, &category.CreatedAt, &category.UpdatedAt)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -60,24 +98,29 @@ func (r *CategoryRepository) GetCategoryByName(ctx context.Context, name string)
 	return &category, nil
 }
 
+// GetCategorySlugRedirect возвращает текущий slug категории, которой раньше принадлежал slug, или "", если такой нет
+func (r *CategoryRepository) GetCategorySlugRedirect(ctx context.Context, slug string) (string, error) {
+	return categorySlugs.redirect(r.DB.QuerierFromContext(ctx), slug)
+}
+
 // GetAllCategories получает все категории с пагинацией
 func (r *CategoryRepository) GetAllCategories(ctx context.Context, limit, offset int) ([]models.Category, int64, error) {
 	// Получаем общее количество категорий
 	var total int64
-	err := r.DB.QueryRowContext(ctx, `SELECT COUNT(*) FROM "Categories"`).Scan(&total)
+	err := r.DB.QuerierFromContext(ctx).QueryRow(`SELECT COUNT(*) FROM "Categories"`).Scan(&total)
 	if err != nil {
 		return nil, 0, err
 	}
 
 	// Получаем категории с пагинацией
 	query := `
-		SELECT "Id", "Name", "Description", "CreatedAt", "UpdatedAt" 
+		SELECT "Id", "Name", "Slug", "Description", "ParentId", "Position", "CreatedAt", "UpdatedAt" 
 		FROM "Categories" 
 		ORDER BY "Name" 
 		LIMIT $1 OFFSET $2
 	`
 
-	rows, err := r.DB.QueryContext(ctx, query, limit, offset)
+	rows, err := r.DB.QuerierFromContext(ctx).Query(query, limit, offset)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -89,7 +132,7 @@ func (r *CategoryRepository) GetAllCategories(ctx context.Context, limit, offset
 	var categories []models.Category
 	for rows.Next() {
 		var category models.Category
-		err := rows.Scan(&category.Id, &category.Name, &category.Description, &category.CreatedAt, &category.UpdatedAt)
+		err := rows.Scan(&category.Id, &category.Name, &category.Slug, &category.Description, &category.ParentId, &category.Position, &category.CreatedAt, &category.UpdatedAt)
 		if err != nil {
 			return nil, 0, err
 		}
@@ -99,65 +142,101 @@ func (r *CategoryRepository) GetAllCategories(ctx context.Context, limit, offset
 	return categories, total, nil
 }
 
-// UpdateCategory обновляет категорию
+// UpdateCategory обновляет категорию и записывает событие для вебхуков в outbox.
+// При смене category.ParentId категория становится последней среди дочерних категорий нового родителя.
 func (r *CategoryRepository) UpdateCategory(ctx context.Context, category *models.Category) error {
 	query := `
 		UPDATE "Categories" 
 		SET "Name" = $1, "Description" = $2, "UpdatedAt" = CURRENT_TIMESTAMP 
 		WHERE "Id" = $3
+		RETURNING "UpdatedAt"
 	`
 
-	result, err := r.DB.ExecContext(ctx, query, category.Name, category.Description, category.Id)
-	if err != nil {
-		return err
-	}
+	return r.DB.InTransactionContext(ctx, nil, func(tx *reform.TX) error {
+		// Блокировка дерева берется до блокировки строки, в том же порядке, что и при переносе
+		if err := lockCategoryTree(tx.Querier); err != nil {
+			return err
+		}
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return err
-	}
+		// Обновляем UpdatedAt в модели; sql.ErrNoRows, если категории нет
+		err := tx.QueryRow(query, category.Name, category.Description, category.Id).Scan(&category.UpdatedAt)
+		if err != nil {
+			return err
+		}
 
-	if rowsAffected == 0 {
-		return sql.ErrNoRows
-	}
+		var parentID sql.NullInt64
+		if err := tx.QueryRow(`SELECT "ParentId" FROM "Categories" WHERE "Id" = $1`, category.Id).Scan(&parentID); err != nil {
+			return err
+		}
+		if parentID.Valid != (category.ParentId != nil) || parentID.Valid && parentID.Int64 != *category.ParentId {
+			if err := placeCategory(tx.Querier, category.Id, category.ParentId, -1); err != nil {
+				return err
+			}
+			if err := tx.QueryRow(`SELECT "Position" FROM "Categories" WHERE "Id" = $1`, category.Id).Scan(&category.Position); err != nil {
+				return err
+			}
+		}
+
+		// Прежний slug остается в истории для перенаправлений
+		if category.Slug, err = categorySlugs.assign(tx.Querier, category.Id, category.Name); err != nil {
+			return err
+		}
 
-	// Обновляем UpdatedAt в модели
-	query = `SELECT "UpdatedAt" FROM "Categories" WHERE "Id" = $1`
-	return r.DB.QueryRowContext(ctx, query, category.Id).Scan(&category.UpdatedAt)
+		return insertOutboxEvent(tx.Querier, models.EventCategoryUpdated, []int64{category.Id}, category)
+	})
 }
 
-// DeleteCategory удаляет категорию по ID
+// DeleteCategory удаляет категорию по ID и записывает событие для вебхуков в outbox.
+// Дочерние категории переносятся к родителю удаленной и становятся последними среди его дочерних.
 func (r *CategoryRepository) DeleteCategory(ctx context.Context, id int64) error {
-	query := `DELETE FROM "Categories" WHERE "Id" = $1`
+	query := `DELETE FROM "Categories" WHERE "Id" = $1 RETURNING "ParentId"`
 
-	result, err := r.DB.ExecContext(ctx, query, id)
-	if err != nil {
-		return err
-	}
+	return r.DB.InTransactionContext(ctx, nil, func(tx *reform.TX) error {
+		if err := lockCategoryTree(tx.Querier); err != nil {
+			return err
+		}
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return err
-	}
+		// Дочерние категории получают позиции после категорий нового родителя, затем позиции пересчитываются
+		var children []int64
+		err := tx.QueryRow(`
+			WITH moved AS (
+				UPDATE "Categories" c
+				SET "ParentId" = p."ParentId",
+					"Position" = c."Position" + (SELECT COUNT(*) FROM "Categories" s WHERE s."ParentId" IS NOT DISTINCT FROM p."ParentId"),
+					"UpdatedAt" = CURRENT_TIMESTAMP
+				FROM "Categories" p
+				WHERE p."Id" = $1 AND c."ParentId" = p."Id"
+				RETURNING c."Id"
+			)
+			SELECT COALESCE(array_agg("Id"), '{}') FROM moved`, id).Scan(pq.Array(&children))
+		if err != nil {
+			return err
+		}
 
-	if rowsAffected == 0 {
-		return sql.ErrNoRows
-	}
+		// sql.ErrNoRows, если категории нет
+		var parentID *int64
+		if err := tx.QueryRow(query, id).Scan(&parentID); err != nil {
+			return err
+		}
+		if _, err := renumberCategories(tx.Querier, parentID, 0); err != nil {
+			return err
+		}
 
-	return nil
+		return insertOutboxEvent(tx.Querier, models.EventCategoryDeleted, append([]int64{id}, children...), map[string]int64{"id": id})
+	})
 }
 
 // GetCategoriesByNewsID получает категории для конкретной новости
 func (r *CategoryRepository) GetCategoriesByNewsID(ctx context.Context, newsID int64) ([]models.Category, error) {
 	query := `
-		SELECT c."Id", c."Name", c."Description", c."CreatedAt", c."UpdatedAt"
+		SELECT c."Id", c."Name", c."Slug", c."Description", c."ParentId", c."Position", c."CreatedAt", c."UpdatedAt"
 		FROM "Categories" c
 		INNER JOIN "NewsCategories" nc ON c."Id" = nc."CategoryId"
 		WHERE nc."NewsId" = $1
 		ORDER BY c."Name"
 	`
 
-	rows, err := r.DB.QueryContext(ctx, query, newsID)
+	rows, err := r.DB.QuerierFromContext(ctx).Query(query, newsID)
 	if err != nil {
 		return nil, err
 	}
@@ -169,7 +248,7 @@ func (r *CategoryRepository) GetCategoriesByNewsID(ctx context.Context, newsID i
 	var categories []models.Category
 	for rows.Next() {
 		var category models.Category
-		err := rows.Scan(&category.Id, &category.Name, &category.Description, &category.CreatedAt, &category.UpdatedAt)
+		err := rows.Scan(&category.Id, &category.Name, &category.Slug, &category.Description, &category.ParentId, &category.Position, &category.CreatedAt, &category.UpdatedAt)
 		if err != nil {
 			return nil, err
 		}
@@ -178,3 +257,71 @@ func (r *CategoryRepository) GetCategoriesByNewsID(ctx context.Context, newsID i
 
 	return categories, nil
 }
+
+// CountCategories возвращает количество категорий
+func (r *CategoryRepository) CountCategories(ctx context.Context) (int64, error) {
+	var total int64
+	err := r.DB.QuerierFromContext(ctx).QueryRow(`SELECT COUNT(*) FROM "Categories"`).Scan(&total)
+	return total, err
+}
+
+// GetSitemapChunks группирует категории по диапазонам ID размера chunkSize ([chunk*chunkSize, (chunk+1)*chunkSize))
+// и возвращает непустые диапазоны с временем последнего изменения
+func (r *CategoryRepository) GetSitemapChunks(ctx context.Context, chunkSize int64) ([]SitemapChunk, error) {
+	rows, err := r.DB.QuerierFromContext(ctx).Query(`
+		SELECT "Id" / $1 AS chunk, MAX("UpdatedAt")
+		FROM "Categories"
+		GROUP BY chunk
+		ORDER BY chunk`, chunkSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var chunks []SitemapChunk
+	for rows.Next() {
+		var chunk SitemapChunk
+		if err := rows.Scan(&chunk.Chunk, &chunk.LastMod); err != nil {
+			return nil, err
+		}
+		chunks = append(chunks, chunk)
+	}
+
+	return chunks, rows.Err()
+}
+
+// HasCategoriesInRange проверяет, есть ли категории с ID в диапазоне [fromID, toID)
+func (r *CategoryRepository) HasCategoriesInRange(ctx context.Context, fromID, toID int64) (bool, error) {
+	var exists bool
+	err := r.DB.QuerierFromContext(ctx).
+		QueryRow(`SELECT EXISTS (SELECT 1 FROM "Categories" WHERE "Id" >= $1 AND "Id" < $2)`, fromID, toID).
+		Scan(&exists)
+	return exists, err
+}
+
+// EachCategoryModified вызывает fn для каждой категории с ID в диапазоне [fromID, toID) в порядке ID,
+// не загружая список в память; toID <= 0 - без верхней границы
+func (r *CategoryRepository) EachCategoryModified(ctx context.Context, fromID, toID int64, fn func(id int64, updatedAt time.Time) error) error {
+	rows, err := r.DB.QuerierFromContext(ctx).Query(`
+		SELECT "Id", "UpdatedAt"
+		FROM "Categories"
+		WHERE "Id" >= $1 AND ($2 <= 0 OR "Id" < $2)
+		ORDER BY "Id"`, fromID, toID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int64
+		var updatedAt time.Time
+		if err := rows.Scan(&id, &updatedAt); err != nil {
+			return err
+		}
+		if err := fn(id, updatedAt); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
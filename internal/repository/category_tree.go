@@ -0,0 +1,234 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"go_news_server/internal/models"
+
+	"gopkg.in/reform.v1"
+)
+
+// categoryTreeLockID - ключ advisory lock для изменений дерева категорий: проверка циклов
+// и пересчет позиций не должны пересекаться с параллельными переносами
+const categoryTreeLockID = 7243002
+
+// categorySubtree возвращает рекурсивный CTE category_tree с категорией root (плейсхолдер "$1" или "?")
+// и всеми ее потомками. UNION вместо UNION ALL не дает зациклиться, если цикл все же оказался в данных.
+func categorySubtree(root string) string {
+	return `
+	WITH RECURSIVE category_tree AS (
+		SELECT "Id" FROM "Categories" WHERE "Id" = ` + root + `
+		UNION
+		SELECT c."Id" FROM "Categories" c INNER JOIN category_tree t ON c."ParentId" = t."Id"
+	)`
+}
+
+// categorySubtreeCTE - categorySubtree для запросов с категорией в $1
+var categorySubtreeCTE = categorySubtree("$1")
+
+// newsInCategorySubtree - условие reform.Tail: новость из столбца newsColumn относится к категории categoryID
+// или к одной из ее дочерних категорий
+func newsInCategorySubtree(newsColumn string, categoryID int64) reform.Cond {
+	return reform.Expr(`EXISTS (
+		SELECT 1 FROM "NewsCategories" f WHERE f."NewsId" = `+newsColumn+` AND f."CategoryId" IN (`+
+		categorySubtree("?")+`
+			SELECT "Id" FROM category_tree
+		)
+	)`, categoryID)
+}
+
+// InTransaction выполняет fn в транзакции, методы репозитория с контекстом fn выполняются в ней
+func (r *CategoryRepository) InTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return r.DB.InTransactionContext(ctx, nil, func(tx *reform.TX) error {
+		return fn(reform.ContextWithTX(ctx, tx))
+	})
+}
+
+// LockCategoryTree блокирует изменения дерева категорий до конца транзакции из контекста.
+// Вне транзакции блокировка снимается сразу, поэтому вызывается внутри InTransaction.
+func (r *CategoryRepository) LockCategoryTree(ctx context.Context) error {
+	return lockCategoryTree(r.DB.QuerierFromContext(ctx))
+}
+
+func lockCategoryTree(q *reform.Querier) error {
+	_, err := q.Exec(`SELECT pg_advisory_xact_lock($1)`, categoryTreeLockID)
+	return err
+}
+
+// IsCategoryInSubtree проверяет, является ли id категорией rootID или ее потомком
+func (r *CategoryRepository) IsCategoryInSubtree(ctx context.Context, id, rootID int64) (bool, error) {
+	var exists bool
+	err := r.DB.QuerierFromContext(ctx).
+		QueryRow(categorySubtreeCTE+` SELECT EXISTS (SELECT 1 FROM category_tree WHERE "Id" = $2)`, rootID, id).
+		Scan(&exists)
+	return exists, err
+}
+
+// GetCategoryTree получает все категории в виде дерева, дочерние категории упорядочены по позиции
+func (r *CategoryRepository) GetCategoryTree(ctx context.Context) ([]*models.CategoryNode, error) {
+	rows, err := r.DB.QuerierFromContext(ctx).Query(`
+		SELECT "Id", "Name", "Slug", "Description", "ParentId", "Position", "CreatedAt", "UpdatedAt"
+		FROM "Categories"
+		ORDER BY "Position", "Name", "Id"`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var categories []models.Category
+	for rows.Next() {
+		var category models.Category
+		err := rows.Scan(&category.Id, &category.Name, &category.Slug, &category.Description, &category.ParentId, &category.Position, &category.CreatedAt, &category.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		categories = append(categories, category)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return buildCategoryTree(categories), nil
+}
+
+// buildCategoryTree собирает дерево из категорий, отсортированных по позиции.
+// Категория, родитель которой не найден, считается корневой.
+func buildCategoryTree(categories []models.Category) []*models.CategoryNode {
+	nodes := make(map[int64]*models.CategoryNode, len(categories))
+	for _, category := range categories {
+		nodes[category.Id] = &models.CategoryNode{Category: category, Children: []*models.CategoryNode{}}
+	}
+
+	roots := []*models.CategoryNode{}
+	for _, category := range categories {
+		node := nodes[category.Id]
+		if category.ParentId != nil {
+			if parent, ok := nodes[*category.ParentId]; ok {
+				parent.Children = append(parent.Children, node)
+				continue
+			}
+		}
+		roots = append(roots, node)
+	}
+	return roots
+}
+
+// GetCategoryDescendants получает всех потомков категории в порядке обхода дерева в глубину
+func (r *CategoryRepository) GetCategoryDescendants(ctx context.Context, id int64) ([]models.Category, error) {
+	// path - позиции и ID предков, сравнение массивов дает порядок обхода в глубину;
+	// ids защищает от зацикливания, если цикл все же оказался в данных
+	rows, err := r.DB.QuerierFromContext(ctx).Query(`
+		WITH RECURSIVE descendants AS (
+			SELECT c.*, ARRAY[c."Position"::BIGINT, c."Id"] AS path, ARRAY[$1::BIGINT, c."Id"] AS ids
+			FROM "Categories" c
+			WHERE c."ParentId" = $1
+			UNION ALL
+			SELECT c.*, d.path || ARRAY[c."Position"::BIGINT, c."Id"], d.ids || c."Id"
+			FROM "Categories" c
+			INNER JOIN descendants d ON c."ParentId" = d."Id"
+			WHERE NOT c."Id" = ANY(d.ids)
+		)
+		SELECT "Id", "Name", "Slug", "Description", "ParentId", "Position", "CreatedAt", "UpdatedAt"
+		FROM descendants
+		ORDER BY path`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	categories := []models.Category{}
+	for rows.Next() {
+		var category models.Category
+		err := rows.Scan(&category.Id, &category.Name, &category.Slug, &category.Description, &category.ParentId, &category.Position, &category.CreatedAt, &category.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		categories = append(categories, category)
+	}
+
+	return categories, rows.Err()
+}
+
+// MoveCategory переносит категорию к родителю parentID (nil - в корень) на позицию position
+// среди его дочерних категорий; position < 0 или больше их числа - последней
+func (r *CategoryRepository) MoveCategory(ctx context.Context, id int64, parentID *int64, position int) (*models.Category, error) {
+	var category *models.Category
+	err := r.DB.InTransactionContext(ctx, nil, func(tx *reform.TX) error {
+		if err := lockCategoryTree(tx.Querier); err != nil {
+			return err
+		}
+		if err := placeCategory(tx.Querier, id, parentID, position); err != nil {
+			return err
+		}
+
+		category = &models.Category{}
+		err := tx.QueryRow(`
+			UPDATE "Categories" SET "UpdatedAt" = CURRENT_TIMESTAMP
+			WHERE "Id" = $1
+			RETURNING "Id", "Name", "Slug", "Description", "ParentId", "Position", "CreatedAt", "UpdatedAt"`, id).
+			Scan(&category.Id, &category.Name, &category.Slug, &category.Description, &category.ParentId, &category.Position, &category.CreatedAt, &category.UpdatedAt)
+		if err != nil {
+			return err
+		}
+
+		return insertOutboxEvent(tx.Querier, models.EventCategoryUpdated, []int64{id}, category)
+	})
+	return category, err
+}
+
+// placeCategory ставит категорию id к родителю parentID на позицию position (< 0 - последней)
+// и пересчитывает позиции в прежней и новой группах, чтобы они шли с 0 без пропусков.
+// Возвращает sql.ErrNoRows, если категории нет.
+func placeCategory(q *reform.Querier, id int64, parentID *int64, position int) error {
+	var oldParentID sql.NullInt64
+	if err := q.QueryRow(`SELECT "ParentId" FROM "Categories" WHERE "Id" = $1 FOR UPDATE`, id).Scan(&oldParentID); err != nil {
+		return err
+	}
+
+	count, err := renumberCategories(q, parentID, id)
+	if err != nil {
+		return err
+	}
+	if position < 0 || int64(position) > count {
+		position = int(count)
+	}
+
+	_, err = q.Exec(`
+		UPDATE "Categories" SET "Position" = "Position" + 1
+		WHERE "ParentId" IS NOT DISTINCT FROM $1 AND "Id" <> $2 AND "Position" >= $3`, parentID, id, position)
+	if err != nil {
+		return err
+	}
+	if _, err := q.Exec(`UPDATE "Categories" SET "ParentId" = $1, "Position" = $2 WHERE "Id" = $3`, parentID, position, id); err != nil {
+		return err
+	}
+
+	if oldParentID.Valid != (parentID != nil) || oldParentID.Valid && oldParentID.Int64 != *parentID {
+		var oldParent *int64
+		if oldParentID.Valid {
+			oldParent = &oldParentID.Int64
+		}
+		if _, err := renumberCategories(q, oldParent, 0); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renumberCategories выставляет позиции 0, 1, 2... дочерним категориям parentID (nil - корневым),
+// кроме exceptID, сохраняя их порядок. Возвращает число пронумерованных категорий.
+func renumberCategories(q *reform.Querier, parentID *int64, exceptID int64) (int64, error) {
+	result, err := q.Exec(`
+		UPDATE "Categories" c
+		SET "Position" = o.position
+		FROM (
+			SELECT "Id", row_number() OVER (ORDER BY "Position", "Name", "Id") - 1 AS position
+			FROM "Categories"
+			WHERE "ParentId" IS NOT DISTINCT FROM $1 AND "Id" <> $2
+		) o
+		WHERE c."Id" = o."Id"`, parentID, exceptID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"go_news_server/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildCategoryTree(t *testing.T) {
+	parent := func(id int64) *int64 { return &id }
+
+	// категории отсортированы по позиции, как их возвращает GetCategoryTree
+	tree := buildCategoryTree([]models.Category{
+		{Id: 1, Name: "Спорт", Position: 0},
+		{Id: 3, Name: "Футбол", ParentId: parent(1), Position: 0},
+		{Id: 5, Name: "РПЛ", ParentId: parent(3), Position: 0},
+		{Id: 2, Name: "Политика", Position: 1},
+		{Id: 4, Name: "Хоккей", ParentId: parent(1), Position: 1},
+		{Id: 6, Name: "Без родителя", ParentId: parent(100), Position: 2},
+	})
+
+	require.Len(t, tree, 3)
+	assert.Equal(t, []int64{1, 2, 6}, []int64{tree[0].Id, tree[1].Id, tree[2].Id})
+
+	sport := tree[0]
+	require.Len(t, sport.Children, 2)
+	assert.Equal(t, "Футбол", sport.Children[0].Name)
+	assert.Equal(t, "Хоккей", sport.Children[1].Name)
+	require.Len(t, sport.Children[0].Children, 1)
+	assert.Equal(t, int64(5), sport.Children[0].Children[0].Id)
+	assert.NotNil(t, tree[1].Children)
+}
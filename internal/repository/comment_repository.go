@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"go_news_server/internal/models"
+	"time"
+
+	"gopkg.in/reform.v1"
+)
+
+type CommentRepository struct {
+	DB *reform.DB
+}
+
+// commentAuthorLockID - первый ключ advisory lock автора комментариев, второй - hashtext от ID автора.
+// Проверка частоты и запись комментария одного автора не должны выполняться параллельно.
+const commentAuthorLockID = 7243003
+
+const commentColumns = `"Id", "NewsId", "ParentId", "UserId", "UserName", "Content", "Status", "FilterReason", "CreatedAt", "ModeratedAt"`
+
+func scanComment(row rowScanner) (models.Comment, error) {
+	var c models.Comment
+	err := row.Scan(&c.Id, &c.NewsId, &c.ParentId, &c.UserId, &c.UserName, &c.Content, &c.Status, &c.FilterReason, &c.CreatedAt, &c.ModeratedAt)
+	return c, err
+}
+
+// InTransaction выполняет fn в транзакции, методы репозитория с контекстом fn выполняются в ней
+func (r *CommentRepository) InTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return r.DB.InTransactionContext(ctx, nil, func(tx *reform.TX) error {
+		return fn(reform.ContextWithTX(ctx, tx))
+	})
+}
+
+// LockCommentAuthor блокирует автора до конца транзакции
+func (r *CommentRepository) LockCommentAuthor(ctx context.Context, userID string) error {
+	_, err := r.DB.QuerierFromContext(ctx).Exec(`SELECT pg_advisory_xact_lock($1::int, hashtext($2))`, commentAuthorLockID, userID)
+	return err
+}
+
+// IsUserBanned проверяет, запрещено ли читателю комментировать
+func (r *CommentRepository) IsUserBanned(ctx context.Context, userID string) (bool, error) {
+	var banned bool
+	err := r.DB.QuerierFromContext(ctx).QueryRow(`SELECT EXISTS (SELECT 1 FROM "CommentBans" WHERE "UserId" = $1)`, userID).Scan(&banned)
+	return banned, err
+}
+
+// CountRecentComments получает число комментариев читателя за последние window и время,
+// через которое самый ранний из них выйдет за окно
+func (r *CommentRepository) CountRecentComments(ctx context.Context, userID string, window time.Duration) (int, time.Duration, error) {
+	var count int
+	var retryAfter float64
+	err := r.DB.QuerierFromContext(ctx).QueryRow(`
+		SELECT COUNT(*),
+		       COALESCE(EXTRACT(EPOCH FROM MIN("CreatedAt") + make_interval(secs => $2::float8) - CURRENT_TIMESTAMP), 0)
+		FROM "Comments"
+		WHERE "UserId" = $1 AND "CreatedAt" > CURRENT_TIMESTAMP - make_interval(secs => $2::float8)`,
+		userID, window.Seconds()).Scan(&count, &retryAfter)
+	return count, time.Duration(retryAfter * float64(time.Second)), err
+}
+
+// CreateComment создает комментарий. sql.ErrNoRows, если новости нет.
+func (r *CommentRepository) CreateComment(ctx context.Context, comment *models.Comment) error {
+	return r.DB.QuerierFromContext(ctx).QueryRow(`
+		INSERT INTO "Comments" ("NewsId", "ParentId", "UserId", "UserName", "Content", "Status", "FilterReason")
+		SELECT "Id", $2, $3, $4, $5, $6, $7 FROM "News" WHERE "Id" = $1
+		RETURNING "Id", "CreatedAt"`,
+		comment.NewsId, comment.ParentId, comment.UserId, comment.UserName, comment.Content, comment.Status, comment.FilterReason).
+		Scan(&comment.Id, &comment.CreatedAt)
+}
+
+// GetComment получает комментарий по ID, nil - если его нет
+func (r *CommentRepository) GetComment(ctx context.Context, id int64) (*models.Comment, error) {
+	comment, err := scanComment(r.DB.QuerierFromContext(ctx).QueryRow(`SELECT `+commentColumns+` FROM "Comments" WHERE "Id" = $1`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// GetCommentThreads получает до limit одобренных комментариев верхнего уровня новости с ID меньше before
+// (0 - с самого нового) вместе со всеми одобренными ответами на них. Комментарии отсортированы по ID,
+// ответы на отклоненные комментарии не попадают в выборку.
+func (r *CommentRepository) GetCommentThreads(ctx context.Context, newsID, before int64, limit int) ([]models.Comment, error) {
+	rows, err := r.DB.QuerierFromContext(ctx).Query(`
+		WITH RECURSIVE roots AS (
+			SELECT "Id" FROM "Comments"
+			WHERE "NewsId" = $1 AND "ParentId" IS NULL AND "Status" = 'approved' AND ($2 = 0 OR "Id" < $2)
+			ORDER BY "Id" DESC
+			LIMIT $3
+		), thread AS (
+			SELECT c.* FROM "Comments" c WHERE c."Id" IN (SELECT "Id" FROM roots)
+			UNION ALL
+			SELECT c.* FROM "Comments" c INNER JOIN thread t ON c."ParentId" = t."Id" WHERE c."Status" = 'approved'
+		)
+		SELECT `+commentColumns+` FROM thread ORDER BY "Id"`, newsID, before, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanComments(rows)
+}
+
+// GetCommentsByStatus получает до limit комментариев со статусом status и ID больше after, от старых к новым
+func (r *CommentRepository) GetCommentsByStatus(ctx context.Context, status string, after int64, limit int) ([]models.Comment, error) {
+	rows, err := r.DB.QuerierFromContext(ctx).Query(`
+		SELECT `+commentColumns+`
+		FROM "Comments"
+		WHERE "Status" = $1 AND "Id" > $2
+		ORDER BY "Id"
+		LIMIT $3`, status, after, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanComments(rows)
+}
+
+func scanComments(rows *sql.Rows) ([]models.Comment, error) {
+	comments := []models.Comment{}
+	for rows.Next() {
+		comment, err := scanComment(rows)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, comment)
+	}
+	return comments, rows.Err()
+}
+
+// SetCommentStatus меняет статус комментария. nil, если комментария нет.
+func (r *CommentRepository) SetCommentStatus(ctx context.Context, id int64, status string) (*models.Comment, error) {
+	comment, err := scanComment(r.DB.QuerierFromContext(ctx).QueryRow(`
+		UPDATE "Comments" SET "Status" = $2, "ModeratedAt" = CURRENT_TIMESTAMP
+		WHERE "Id" = $1
+		RETURNING `+commentColumns, id, status))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// BanCommentAuthor запрещает комментировать автору комментария commentID, отклоняет этот комментарий
+// и все комментарии автора, ожидающие модерации. Возвращает ID автора и число отклоненных комментариев.
+// sql.ErrNoRows, если комментария нет.
+func (r *CommentRepository) BanCommentAuthor(ctx context.Context, commentID int64, reason string) (string, int64, error) {
+	var userID string
+	var rejected int64
+	err := r.DB.InTransactionContext(ctx, nil, func(tx *reform.TX) error {
+		if err := tx.QueryRow(`SELECT "UserId" FROM "Comments" WHERE "Id" = $1`, commentID).Scan(&userID); err != nil {
+			return err
+		}
+
+		_, err := tx.Exec(`
+			INSERT INTO "CommentBans" ("UserId", "Reason", "CommentId") VALUES ($1, $2, $3)
+			ON CONFLICT ("UserId") DO UPDATE SET "Reason" = EXCLUDED."Reason", "CommentId" = EXCLUDED."CommentId"`,
+			userID, reason, commentID)
+		if err != nil {
+			return err
+		}
+
+		result, err := tx.Exec(`
+			UPDATE "Comments" SET "Status" = 'rejected', "ModeratedAt" = CURRENT_TIMESTAMP
+			WHERE "UserId" = $1 AND ("Status" = 'pending' OR "Id" = $2)`, userID, commentID)
+		if err != nil {
+			return err
+		}
+		rejected, err = result.RowsAffected()
+		return err
+	})
+	return userID, rejected, err
+}
+
+// DeleteCommentBan снимает запрет комментировать. sql.ErrNoRows, если запрета не было.
+func (r *CommentRepository) DeleteCommentBan(ctx context.Context, userID string) error {
+	return execOne(r.DB.QuerierFromContext(ctx), `DELETE FROM "CommentBans" WHERE "UserId" = $1`, userID)
+}
@@ -0,0 +1,213 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"go_news_server/internal/models"
+
+	"github.com/lib/pq"
+	"gopkg.in/reform.v1"
+)
+
+type MediaRepository struct {
+	DB *reform.DB
+}
+
+const mediaColumns = `"Id", "NewsId", "StorageKey", "ThumbnailKey", "ContentType", "Size", "Width", "Height",
+	"ThumbnailWidth", "ThumbnailHeight", "Alt", "Position", "IsCover", "CreatedAt"`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanMedia(row rowScanner) (models.NewsMedia, error) {
+	var m models.NewsMedia
+	err := row.Scan(&m.Id, &m.NewsId, &m.StorageKey, &m.ThumbnailKey, &m.ContentType, &m.Size, &m.Width, &m.Height,
+		&m.ThumbnailWidth, &m.ThumbnailHeight, &m.Alt, &m.Position, &m.IsCover, &m.CreatedAt)
+	return m, err
+}
+
+// touchNews обновляет "UpdatedAt" новости: изображения входят в ответ с новостью, поэтому ленты
+// и кэши должны увидеть изменение. Блокирует строку новости до конца транзакции, чтобы позиции
+// и обложка изображений одной новости не менялись параллельно. sql.ErrNoRows, если новости нет.
+func touchNews(q *reform.Querier, newsID int64) error {
+	return execOne(q, `UPDATE "News" SET "UpdatedAt" = CURRENT_TIMESTAMP WHERE "Id" = $1`, newsID)
+}
+
+// CreateMedia добавляет изображение последним среди изображений новости. Первое изображение новости
+// становится обложкой; media.IsCover делает обложкой новое изображение вместо прежней.
+// sql.ErrNoRows, если новости нет.
+func (r *MediaRepository) CreateMedia(ctx context.Context, media *models.NewsMedia) error {
+	return r.DB.InTransactionContext(ctx, nil, func(tx *reform.TX) error {
+		if err := touchNews(tx.Querier, media.NewsId); err != nil {
+			return err
+		}
+
+		if media.IsCover {
+			if _, err := tx.Exec(`UPDATE "NewsMedia" SET "IsCover" = FALSE WHERE "NewsId" = $1 AND "IsCover"`, media.NewsId); err != nil {
+				return err
+			}
+		}
+
+		return tx.QueryRow(`
+			INSERT INTO "NewsMedia" ("NewsId", "StorageKey", "ThumbnailKey", "ContentType", "Size", "Width", "Height",
+				"ThumbnailWidth", "ThumbnailHeight", "Alt", "Position", "IsCover")
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10,
+				(SELECT COALESCE(MAX("Position") + 1, 0) FROM "NewsMedia" WHERE "NewsId" = $1),
+				$11 OR NOT EXISTS (SELECT 1 FROM "NewsMedia" WHERE "NewsId" = $1 AND "IsCover"))
+			RETURNING "Id", "Position", "IsCover", "CreatedAt"`,
+			media.NewsId, media.StorageKey, media.ThumbnailKey, media.ContentType, media.Size, media.Width, media.Height,
+			media.ThumbnailWidth, media.ThumbnailHeight, media.Alt, media.IsCover).
+			Scan(&media.Id, &media.Position, &media.IsCover, &media.CreatedAt)
+	})
+}
+
+// GetMediaByNewsIDs получает изображения новостей по порядку, сгруппированные по ID новости
+func (r *MediaRepository) GetMediaByNewsIDs(ctx context.Context, newsIDs []int64) (map[int64][]models.NewsMedia, error) {
+	result := make(map[int64][]models.NewsMedia)
+	if len(newsIDs) == 0 {
+		return result, nil
+	}
+
+	rows, err := r.DB.QuerierFromContext(ctx).Query(`
+		SELECT `+mediaColumns+`
+		FROM "NewsMedia"
+		WHERE "NewsId" = ANY($1)
+		ORDER BY "NewsId", "Position", "Id"`, pq.Array(newsIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		media, err := scanMedia(rows)
+		if err != nil {
+			return nil, err
+		}
+		result[media.NewsId] = append(result[media.NewsId], media)
+	}
+
+	return result, rows.Err()
+}
+
+// UpdateMedia меняет подпись, позицию и признак обложки изображения новости; nil - без изменений.
+// sql.ErrNoRows, если у новости нет такого изображения.
+func (r *MediaRepository) UpdateMedia(ctx context.Context, newsID, id int64, req *models.NewsMediaUpdateRequest) (*models.NewsMedia, error) {
+	var media models.NewsMedia
+	err := r.DB.InTransactionContext(ctx, nil, func(tx *reform.TX) error {
+		if err := touchNews(tx.Querier, newsID); err != nil {
+			return err
+		}
+
+		var exists bool
+		err := tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM "NewsMedia" WHERE "Id" = $1 AND "NewsId" = $2)`, id, newsID).Scan(&exists)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return sql.ErrNoRows
+		}
+
+		if req.Alt != nil {
+			if _, err := tx.Exec(`UPDATE "NewsMedia" SET "Alt" = $1 WHERE "Id" = $2`, *req.Alt, id); err != nil {
+				return err
+			}
+		}
+
+		if req.Position != nil {
+			if err := placeMedia(tx.Querier, newsID, id, *req.Position); err != nil {
+				return err
+			}
+		}
+
+		if req.IsCover != nil {
+			if *req.IsCover {
+				_, err = tx.Exec(`UPDATE "NewsMedia" SET "IsCover" = FALSE WHERE "NewsId" = $1 AND "IsCover" AND "Id" <> $2`, newsID, id)
+				if err != nil {
+					return err
+				}
+			}
+			if _, err := tx.Exec(`UPDATE "NewsMedia" SET "IsCover" = $1 WHERE "Id" = $2`, *req.IsCover, id); err != nil {
+				return err
+			}
+		}
+
+		media, err = scanMedia(tx.QueryRow(`SELECT `+mediaColumns+` FROM "NewsMedia" WHERE "Id" = $1`, id))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &media, nil
+}
+
+// placeMedia ставит изображение id на позицию position среди изображений новости (больше их числа - последним)
+// и сдвигает остальные, чтобы позиции шли с 0 без пропусков
+func placeMedia(q *reform.Querier, newsID, id int64, position int) error {
+	count, err := renumberMedia(q, newsID, id)
+	if err != nil {
+		return err
+	}
+	if position < 0 || int64(position) > count {
+		position = int(count)
+	}
+
+	_, err = q.Exec(`
+		UPDATE "NewsMedia" SET "Position" = "Position" + 1
+		WHERE "NewsId" = $1 AND "Id" <> $2 AND "Position" >= $3`, newsID, id, position)
+	if err != nil {
+		return err
+	}
+	_, err = q.Exec(`UPDATE "NewsMedia" SET "Position" = $1 WHERE "Id" = $2`, position, id)
+	return err
+}
+
+// renumberMedia выставляет изображениям новости, кроме exceptID, позиции 0, 1, 2... с сохранением порядка.
+// Возвращает число пронумерованных изображений.
+func renumberMedia(q *reform.Querier, newsID, exceptID int64) (int64, error) {
+	result, err := q.Exec(`
+		UPDATE "NewsMedia" m
+		SET "Position" = o.position
+		FROM (
+			SELECT "Id", row_number() OVER (ORDER BY "Position", "Id") - 1 AS position
+			FROM "NewsMedia"
+			WHERE "NewsId" = $1 AND "Id" <> $2
+		) o
+		WHERE m."Id" = o."Id"`, newsID, exceptID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// DeleteMedia удаляет изображение новости и возвращает его, чтобы удалить файлы из хранилища.
+// Если удалена обложка, обложкой становится первое из оставшихся изображений.
+// sql.ErrNoRows, если у новости нет такого изображения.
+func (r *MediaRepository) DeleteMedia(ctx context.Context, newsID, id int64) (*models.NewsMedia, error) {
+	var media models.NewsMedia
+	err := r.DB.InTransactionContext(ctx, nil, func(tx *reform.TX) error {
+		if err := touchNews(tx.Querier, newsID); err != nil {
+			return err
+		}
+
+		var err error
+		media, err = scanMedia(tx.QueryRow(`DELETE FROM "NewsMedia" WHERE "Id" = $1 AND "NewsId" = $2 RETURNING `+mediaColumns, id, newsID))
+		if err != nil {
+			return err
+		}
+
+		if _, err := renumberMedia(tx.Querier, newsID, 0); err != nil {
+			return err
+		}
+		if media.IsCover {
+			_, err = tx.Exec(`
+				UPDATE "NewsMedia" SET "IsCover" = TRUE
+				WHERE "Id" = (SELECT "Id" FROM "NewsMedia" WHERE "NewsId" = $1 ORDER BY "Position", "Id" LIMIT 1)`, newsID)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &media, nil
+}
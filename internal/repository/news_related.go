@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"go_news_server/internal/models"
+
+	"github.com/lib/pq"
+)
+
+// Веса оценки похожих новостей: каждая общая категория дает relatedCategoryWeight,
+// сходство заголовков по триграммам (от 0 до 1) умножается на relatedTitleWeight
+const (
+	relatedCategoryWeight = 1.0
+	relatedTitleWeight    = 2.0
+)
+
+// RelatedNews похожая новость и ее оценка
+type RelatedNews struct {
+	NewsId int64
+	Score  float64
+}
+
+// GetRelatedNewsIDs получает до limit новостей, похожих на новость id, по убыванию оценки.
+// Кандидаты - новости с общими категориями и новости с похожим заголовком (оператор % pg_trgm).
+// Неопубликованных новостей в схеме нет, поэтому в выдачу попадают все новости, кроме самой id.
+func (r *NewsRepository) GetRelatedNewsIDs(ctx context.Context, id int64, limit int) ([]RelatedNews, error) {
+	rows, err := r.DB.QuerierFromContext(ctx).Query(`
+		WITH source AS (
+            SELECT "Title" FROM "News" WHERE "Id" = $1
+        ), shared AS (
+            SELECT nc."NewsId", COUNT(*) AS shared
+            FROM "NewsCategories" nc
+            WHERE nc."CategoryId" IN (SELECT "CategoryId" FROM "NewsCategories" WHERE "NewsId" = $1)
+              AND nc."NewsId" <> $1
+            GROUP BY nc."NewsId"
+        ), candidates AS (
+            SELECT "NewsId" FROM shared
+            UNION
+            SELECT n."Id" FROM "News" n, source s WHERE n."Title" % s."Title" AND n."Id" <> $1
+        )
+        SELECT n."Id", COALESCE(sh.shared, 0) * $2::float8 + similarity(n."Title", s."Title") * $3::float8 AS score
+        FROM candidates c
+        INNER JOIN "News" n ON n."Id" = c."NewsId"
+        CROSS JOIN source s
+        LEFT JOIN shared sh ON sh."NewsId" = c."NewsId"
+        ORDER BY score DESC, n."CreatedAt" DESC, n."Id" DESC
+        LIMIT $4`, id, relatedCategoryWeight, relatedTitleWeight, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	related := []RelatedNews{}
+	for rows.Next() {
+		var item RelatedNews
+		if err := rows.Scan(&item.NewsId, &item.Score); err != nil {
+			return nil, err
+		}
+		related = append(related, item)
+	}
+	return related, rows.Err()
+}
+
+// GetNewsByIDs получает новости с категориями в порядке ids, отсутствующие пропускаются
+func (r *NewsRepository) GetNewsByIDs(ctx context.Context, ids []int64) ([]models.News, error) {
+	if len(ids) == 0 {
+		return []models.News{}, nil
+	}
+
+	rows, err := r.DB.QuerierFromContext(ctx).Query(`
+		SELECT n."Id", n."Title", n."Slug", n."Content", n."ContentText", n."ContentFormat", n."ContentSource", n."CreatedAt", n."UpdatedAt",
+               COALESCE(array_agg(nc."CategoryId") FILTER (WHERE nc."CategoryId" IS NOT NULL), '{}') as Categories,
+               `+newsTagsColumn+`
+        FROM "News" n
+        LEFT JOIN "NewsCategories" nc ON n."Id" = nc."NewsId"
+        WHERE n."Id" = ANY($1)
+        GROUP BY n."Id"
+        ORDER BY array_position($1, n."Id")`, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	newsList := []models.News{}
+	for rows.Next() {
+		var news models.News
+		var categoriesStr string
+		if err := rows.Scan(&news.Id, &news.Title, &news.Slug, &news.Content, &news.ContentText, &news.ContentFormat, &news.ContentSource, &news.CreatedAt, &news.UpdatedAt, &categoriesStr, pq.Array(&news.Tags)); err != nil {
+			return nil, err
+		}
+		news.Categories = parseCategoryIDs(categoriesStr)
+		newsList = append(newsList, news)
+	}
+	return newsList, rows.Err()
+}
@@ -2,10 +2,13 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"go_news_server/internal/models"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/lib/pq"
 	"gopkg.in/reform.v1"
 )
 
@@ -13,96 +16,432 @@ type NewsRepository struct {
 	DB *reform.DB
 }
 
+// UpdateNews обновляет новость и ее категории в транзакции.
+// Если в контексте уже есть транзакция, изменения выполняются в ней через savepoint.
+// В той же транзакции в outbox записывается событие для вебхуков. Если новости нет, возвращает sql.ErrNoRows.
 func (r *NewsRepository) UpdateNews(ctx context.Context, news *models.News, categories []int64) error {
-	tx, err := r.DB.BeginTx(ctx, nil)
+	return r.DB.InTransactionContext(ctx, nil, func(tx *reform.TX) error {
+		// Update news fields if they are not empty; UpdatedAt меняется и при изменении только категорий
+		result, err := tx.Exec(`
+			UPDATE "News"
+			SET "Title" = COALESCE(NULLIF($1, ''), "Title"),
+				"Content" = COALESCE(NULLIF($2, ''), "Content"),
+				"ContentText" = CASE WHEN $2 = '' THEN "ContentText" ELSE $4 END,
+				"ContentFormat" = CASE WHEN $2 = '' THEN "ContentFormat" ELSE $5 END,
+				"ContentSource" = CASE WHEN $2 = '' THEN "ContentSource" ELSE $6 END,
+				"UpdatedAt" = CURRENT_TIMESTAMP
+			WHERE "Id" = $3`, news.Title, news.Content, news.Id, news.ContentText, news.ContentFormat, news.ContentSource)
+		if err != nil {
+			return err
+		}
+		if affected, err := result.RowsAffected(); err != nil {
+			return err
+		} else if affected == 0 {
+			return sql.ErrNoRows
+		}
+
+		// При изменении заголовка slug формируется заново, прежний остается в истории для перенаправлений
+		if news.Title != "" {
+			if _, err := newsSlugs.assign(tx.Querier, news.Id, news.Title); err != nil {
+				return err
+			}
+		}
+
+		// nil - категории не переданы и не меняются, пустой срез удаляет их
+		var oldCategories []int64
+		if categories != nil {
+			if oldCategories, err = replaceNewsCategories(tx.Querier, news.Id, categories); err != nil {
+				return err
+			}
+		}
+
+		if news.Tags != nil {
+			if err := replaceNewsTags(tx.Querier, news.Id, models.NormalizeTags(news.Tags)); err != nil {
+				return err
+			}
+		}
+
+		updated, err := getNewsByID(tx.Querier, news.Id)
+		if err != nil {
+			return err
+		}
+		if updated == nil {
+			return sql.ErrNoRows
+		}
+		return insertOutboxEvent(tx.Querier, models.EventNewsUpdated, unionIDs(oldCategories, updated.Categories), updated)
+	})
+}
+
+// GetMissingCategoryIDs возвращает ID из ids, для которых нет категории
+func (r *NewsRepository) GetMissingCategoryIDs(ctx context.Context, ids []int64) ([]int64, error) {
+	missing := []int64{}
+	if len(ids) == 0 {
+		return missing, nil
+	}
+	err := r.DB.QuerierFromContext(ctx).QueryRow(`
+		SELECT COALESCE(array_agg(v.id), '{}')
+		FROM unnest($1::bigint[]) AS v(id)
+		WHERE NOT EXISTS (SELECT 1 FROM "Categories" c WHERE c."Id" = v.id)`, pq.Array(ids)).Scan(pq.Array(&missing))
+	return missing, err
+}
+
+// replaceNewsCategories заменяет категории новости и возвращает прежние:
+// подписчики на них тоже должны узнать об изменении
+func replaceNewsCategories(q *reform.Querier, newsID int64, categories []int64) ([]int64, error) {
+	var oldCategories []int64
+	err := q.QueryRow("SELECT COALESCE(array_agg(\"CategoryId\"), '{}') FROM \"NewsCategories\" WHERE \"NewsId\" = $1", newsID).
+		Scan(pq.Array(&oldCategories))
+	if err != nil {
+		return nil, err
+	}
+
+	// Delete existing categories
+	if _, err := q.Exec("DELETE FROM \"NewsCategories\" WHERE \"NewsId\" = $1", newsID); err != nil {
+		return nil, err
+	}
+
+	// Insert new categories
+	for _, category := range categories {
+		if _, err := q.Exec("INSERT INTO \"NewsCategories\" (\"NewsId\", \"CategoryId\") VALUES ($1, $2)", newsID, category); err != nil {
+			return nil, err
+		}
+	}
+
+	return oldCategories, nil
+}
+
+// replaceNewsTags заменяет теги новости нормализованными tags, создавая отсутствующие теги
+func replaceNewsTags(q *reform.Querier, newsID int64, tags []string) error {
+	_, err := q.Exec(`INSERT INTO "Tags" ("Name") SELECT unnest($1::TEXT[]) ON CONFLICT ("Name") DO NOTHING`, pq.Array(tags))
 	if err != nil {
 		return err
 	}
 
-	defer func() {
-		if err := tx.Rollback(); err != nil {
+	if _, err := q.Exec(`DELETE FROM "NewsTags" WHERE "NewsId" = $1`, newsID); err != nil {
+		return err
+	}
+
+	_, err = q.Exec(`
+		INSERT INTO "NewsTags" ("NewsId", "TagId")
+		SELECT $1, "Id" FROM "Tags" WHERE "Name" = ANY($2::TEXT[])`, newsID, pq.Array(tags))
+	return err
+}
+
+// InTransaction выполняет fn в транзакции. Контекст fn содержит транзакцию, поэтому методы репозиториев,
+// вызванные с ним, выполняются в ней, а вложенный InTransaction - через savepoint.
+func (r *NewsRepository) InTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return r.DB.InTransactionContext(ctx, nil, func(tx *reform.TX) error {
+		return fn(reform.ContextWithTX(ctx, tx))
+	})
+}
+
+// ImportNews создает новость с категориями. Если задан news.Id, новость с этим ID создается или заменяется.
+// Категории заменяются на categories, пустой срез или nil удаляет их.
+// Нулевой news.CreatedAt означает текущее время. Возвращает true, если новость создана.
+func (r *NewsRepository) ImportNews(ctx context.Context, news *models.News, categories []int64) (bool, error) {
+	var created bool
+	err := r.DB.InTransactionContext(ctx, nil, func(tx *reform.TX) error {
+		var createdAt interface{}
+		if !news.CreatedAt.IsZero() {
+			createdAt = news.CreatedAt
 		}
-	}()
 
-	// Update news fields if they are not empty
-	if news.Title != "" {
-		_, err = tx.ExecContext(ctx, "UPDATE \"News\" SET \"Title\" = $1 WHERE \"Id\" = $2", news.Title, news.Id)
+		slug, err := newsSlugs.free(tx.Querier, news.Id, newsSlugs.base(news.Title))
 		if err != nil {
 			return err
 		}
-	}
 
-	if news.Content != "" {
-		_, err = tx.ExecContext(ctx, "UPDATE \"News\" SET \"Content\" = $1 WHERE \"Id\" = $2", news.Content, news.Id)
+		if news.Id == 0 {
+			created = true
+			err = tx.QueryRow(`
+				INSERT INTO "News" ("Title", "Content", "ContentText", "ContentFormat", "ContentSource", "CreatedAt", "UpdatedAt", "Slug")
+				VALUES ($1, $2, $3, $4, $5, COALESCE($6, CURRENT_TIMESTAMP), CURRENT_TIMESTAMP, $7)
+				RETURNING "Id", "CreatedAt", "UpdatedAt"`, news.Title, news.Content, news.ContentText, news.ContentFormat, news.ContentSource, createdAt, slug).
+				Scan(&news.Id, &news.CreatedAt, &news.UpdatedAt)
+		} else {
+			// xmax = 0 только у вставленной, а не обновленной строки; slug замененной новости формирует assign
+			err = tx.QueryRow(`
+				INSERT INTO "News" ("Id", "Title", "Content", "ContentText", "ContentFormat", "ContentSource", "CreatedAt", "UpdatedAt", "Slug")
+				VALUES ($1, $2, $3, $4, $5, $6, COALESCE($7, CURRENT_TIMESTAMP), CURRENT_TIMESTAMP, $9)
+				ON CONFLICT ("Id") DO UPDATE
+				SET "Title" = EXCLUDED."Title",
+					"Content" = EXCLUDED."Content",
+					"ContentText" = EXCLUDED."ContentText",
+					"ContentFormat" = EXCLUDED."ContentFormat",
+					"ContentSource" = EXCLUDED."ContentSource",
+					"CreatedAt" = CASE WHEN $8 THEN EXCLUDED."CreatedAt" ELSE "News"."CreatedAt" END,
+					"UpdatedAt" = CURRENT_TIMESTAMP
+				RETURNING "CreatedAt", "UpdatedAt", (xmax = 0)`, news.Id, news.Title, news.Content, news.ContentText,
+				news.ContentFormat, news.ContentSource, createdAt, createdAt != nil, slug).
+				Scan(&news.CreatedAt, &news.UpdatedAt, &created)
+			if err == nil && !created {
+				slug, err = newsSlugs.assign(tx.Querier, news.Id, news.Title)
+			}
+		}
 		if err != nil {
 			return err
 		}
-	}
+		news.Slug = slug
 
-	// Delete existing categories
-	_, err = tx.ExecContext(ctx, "DELETE FROM \"NewsCategories\" WHERE \"NewsId\" = $1", news.Id)
+		oldCategories, err := replaceNewsCategories(tx.Querier, news.Id, categories)
+		if err != nil {
+			return err
+		}
+		news.Categories = nonNilIDs(categories)
+
+		eventType := models.EventNewsUpdated
+		if created {
+			eventType = models.EventNewsCreated
+		}
+		return insertOutboxEvent(tx.Querier, eventType, unionIDs(oldCategories, categories), news)
+	})
+	return created, err
+}
+
+// SyncNewsSequence выставляет последовательность "News"."Id" по максимальному ID после вставки новостей с явными ID
+func (r *NewsRepository) SyncNewsSequence(ctx context.Context) error {
+	_, err := r.DB.QuerierFromContext(ctx).Exec(`SELECT setval(pg_get_serial_sequence('"News"', 'Id'), GREATEST((SELECT MAX("Id") FROM "News"), 1))`)
+	return err
+}
+
+// EachNewsForExport вызывает fn для каждой новости в порядке ID вместе с названиями ее категорий.
+// Строки читаются по одной, без загрузки всех новостей в память.
+func (r *NewsRepository) EachNewsForExport(ctx context.Context, fn func(news models.News, categoryNames []string) error) error {
+	rows, err := r.DB.QuerierFromContext(ctx).Query(`
+		SELECT n."Id", n."Title", n."Content", n."ContentFormat", n."ContentSource", n."CreatedAt", n."UpdatedAt",
+               COALESCE(array_agg(c."Name" ORDER BY c."Name") FILTER (WHERE c."Id" IS NOT NULL), '{}')
+        FROM "News" n
+        LEFT JOIN "NewsCategories" nc ON n."Id" = nc."NewsId"
+        LEFT JOIN "Categories" c ON c."Id" = nc."CategoryId"
+        GROUP BY n."Id"
+        ORDER BY n."Id"`)
 	if err != nil {
 		return err
 	}
+	defer rows.Close()
 
-	// Insert new categories
-	for _, category := range categories {
-		_, err = tx.ExecContext(ctx, "INSERT INTO \"NewsCategories\" (\"NewsId\", \"CategoryId\") VALUES ($1, $2)", news.Id, category)
-		if err != nil {
+	for rows.Next() {
+		var news models.News
+		var categoryNames []string
+		if err := rows.Scan(&news.Id, &news.Title, &news.Content, &news.ContentFormat, &news.ContentSource, &news.CreatedAt, &news.UpdatedAt, pq.Array(&categoryNames)); err != nil {
+			return err
+		}
+		if err := fn(news, categoryNames); err != nil {
 			return err
 		}
 	}
 
-	return tx.Commit()
+	return rows.Err()
+}
+
+// newsTagsColumn - теги новости n в алфавитном порядке
+const newsTagsColumn = `COALESCE((
+                   SELECT array_agg(t."Name" ORDER BY t."Name")
+                   FROM "NewsTags" nt
+                   INNER JOIN "Tags" t ON t."Id" = nt."TagId"
+                   WHERE nt."NewsId" = n."Id"
+               ), '{}') as Tags`
+
+// GetNewsByID получает новость с категориями по ID, возвращает nil, если новость не найдена
+func (r *NewsRepository) GetNewsByID(ctx context.Context, id int64) (*models.News, error) {
+	return getNewsByID(r.DB.QuerierFromContext(ctx), id)
+}
+
+// GetNewsBySlug получает новость с категориями по текущему slug, возвращает nil, если новость не найдена
+func (r *NewsRepository) GetNewsBySlug(ctx context.Context, slug string) (*models.News, error) {
+	return getNewsWhere(r.DB.QuerierFromContext(ctx), `n."Slug" = $1`, slug)
 }
 
-func (r *NewsRepository) GetNewsList(ctx context.Context, limit, offset int) ([]models.News, error) {
-	rows, err := r.DB.QueryContext(ctx, `
-		SELECT n."Id", n."Title", n."Content", 
-               COALESCE(array_agg(nc."CategoryId") FILTER (WHERE nc."CategoryId" IS NOT NULL), '{}') as Categories
+// GetNewsSlugRedirect возвращает текущий slug новости, которой раньше принадлежал slug, или "", если такой нет
+func (r *NewsRepository) GetNewsSlugRedirect(ctx context.Context, slug string) (string, error) {
+	return newsSlugs.redirect(r.DB.QuerierFromContext(ctx), slug)
+}
+
+func getNewsByID(q *reform.Querier, id int64) (*models.News, error) {
+	return getNewsWhere(q, `n."Id" = $1`, id)
+}
+
+// getNewsWhere получает одну новость с категориями по условию condition с параметром $1
+func getNewsWhere(q *reform.Querier, condition string, arg interface{}) (*models.News, error) {
+	var news models.News
+	var categoriesStr string
+	err := q.QueryRow(`
+		SELECT n."Id", n."Title", n."Slug", n."Content", n."ContentText", n."ContentFormat", n."ContentSource", n."CreatedAt", n."UpdatedAt",
+               COALESCE(array_agg(nc."CategoryId") FILTER (WHERE nc."CategoryId" IS NOT NULL), '{}') as Categories,
+               `+newsTagsColumn+`
         FROM "News" n
         LEFT JOIN "NewsCategories" nc ON n."Id" = nc."NewsId"
-        GROUP BY n."Id", n."Title", n."Content"
-        ORDER BY n."Id"
-        LIMIT $1 OFFSET $2`, limit, offset)
+        WHERE `+condition+`
+        GROUP BY n."Id"`, arg).
+		Scan(&news.Id, &news.Title, &news.Slug, &news.Content, &news.ContentText, &news.ContentFormat, &news.ContentSource, &news.CreatedAt, &news.UpdatedAt, &categoriesStr, pq.Array(&news.Tags))
 
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
 		return nil, err
 	}
-	defer func() {
-		if err := rows.Close(); err != nil {
-		}
-	}()
+
+	news.Categories = parseCategoryIDs(categoriesStr)
+	return &news, nil
+}
+
+// GetNewsList получает страницу новостей по условиям filter в порядке ID.
+// Фильтр по категории включает новости ее дочерних категорий на любой глубине, фильтр по тегу - точное совпадение.
+func (r *NewsRepository) GetNewsList(ctx context.Context, filter models.NewsFilter, limit, offset int) ([]models.News, error) {
+	order := reform.NewTail().OrderBy("n.Id").Limit(limit).Offset(offset)
+	return queryNewsList(r.DB.QuerierFromContext(ctx), newsFilterTail(filter), order)
+}
+
+// GetLatestNews получает последние созданные новости категории и ее дочерних категорий, categoryID = 0 - из всех категорий
+func (r *NewsRepository) GetLatestNews(ctx context.Context, categoryID int64, limit int) ([]models.News, error) {
+	order := reform.NewTail().OrderByDesc("n.CreatedAt", "n.Id").Limit(limit)
+	return queryNewsList(r.DB.QuerierFromContext(ctx), newsFilterTail(models.NewsFilter{CategoryID: categoryID}), order)
+}
+
+// newsFilterTail возвращает условия выборки новостей n по filter, нулевые значения не добавляют условий
+func newsFilterTail(filter models.NewsFilter) *reform.Tail {
+	tail := reform.NewTail()
+	if filter.CategoryID != 0 {
+		tail.Where(newsInCategorySubtree(`n."Id"`, filter.CategoryID))
+	}
+	if tag := models.NormalizeTag(filter.Tag); tag != "" {
+		tail.Where(reform.Expr(`EXISTS (
+            SELECT 1 FROM "NewsTags" ft INNER JOIN "Tags" t ON t."Id" = ft."TagId" WHERE ft."NewsId" = n."Id" AND t."Name" = ?
+        )`, tag))
+	}
+	if filter.AfterID != 0 {
+		tail.Where(reform.Expr(`n."Id" > ?`, filter.AfterID))
+	}
+	return tail
+}
+
+// queryNewsList получает новости n с категориями и тегами. Условия берутся из where, а сортировка
+// и ограничения - из order, потому что между WHERE и ORDER BY в запросе стоит GROUP BY.
+func queryNewsList(q *reform.Querier, where, order *reform.Tail) ([]models.News, error) {
+	whereSQL, args := where.BuildWhere(q.Dialect, 1)
+	orderSQL, orderArgs := order.Build(q.Dialect, len(args)+1)
+	args = append(args, orderArgs...)
+
+	rows, err := q.Query(`
+		SELECT n."Id", n."Title", n."Slug", n."Content", n."ContentText", n."ContentFormat", n."ContentSource", n."CreatedAt", n."UpdatedAt",
+               COALESCE(array_agg(nc."CategoryId") FILTER (WHERE nc."CategoryId" IS NOT NULL), '{}') as Categories,
+               `+newsTagsColumn+`
+        FROM "News" n
+        LEFT JOIN "NewsCategories" nc ON n."Id" = nc."NewsId"
+        `+whereSQL+`
+        GROUP BY n."Id"
+        `+orderSQL, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 
 	var newsList []models.News
 	for rows.Next() {
 		var news models.News
 		var categoriesStr string
-		if err := rows.Scan(&news.Id, &news.Title, &news.Content, &categoriesStr); err != nil {
+		if err := rows.Scan(&news.Id, &news.Title, &news.Slug, &news.Content, &news.ContentText, &news.ContentFormat, &news.ContentSource, &news.CreatedAt, &news.UpdatedAt, &categoriesStr, pq.Array(&news.Tags)); err != nil {
 			return nil, err
 		}
+		news.Categories = parseCategoryIDs(categoriesStr)
+		newsList = append(newsList, news)
+	}
 
-		// Забираем из PostgreSQL категории как массив {1,2,3}
-		var categories []int64
-		if categoriesStr != "{}" && categoriesStr != "" {
-			// Remove { and } and split by comma
-			categoriesStr = strings.Trim(categoriesStr, "{}")
-			if categoriesStr != "" {
-				parts := strings.Split(categoriesStr, ",")
-				for _, part := range parts {
-					part = strings.TrimSpace(part)
-					if part != "" {
-						if categoryID, err := strconv.ParseInt(part, 10, 64); err == nil {
-							categories = append(categories, categoryID)
-						}
+	return newsList, rows.Err()
+}
+
+// parseCategoryIDs разбирает массив PostgreSQL вида {1,2,3} с ID категорий
+func parseCategoryIDs(categoriesStr string) []int64 {
+	var categories []int64
+	if categoriesStr != "{}" && categoriesStr != "" {
+		// Remove { and } and split by comma
+		categoriesStr = strings.Trim(categoriesStr, "{}")
+		if categoriesStr != "" {
+			parts := strings.Split(categoriesStr, ",")
+			for _, part := range parts {
+				part = strings.TrimSpace(part)
+				if part != "" {
+					if categoryID, err := strconv.ParseInt(part, 10, 64); err == nil {
+						categories = append(categories, categoryID)
 					}
 				}
 			}
 		}
-		news.Categories = categories
+	}
+	return categories
+}
 
-		newsList = append(newsList, news)
+// SitemapChunk диапазон ID новостей или категорий для одного файла sitemap
+type SitemapChunk struct {
+	Chunk   int64
+	LastMod time.Time
+}
+
+// CountNews возвращает количество новостей
+func (r *NewsRepository) CountNews(ctx context.Context) (int64, error) {
+	var total int64
+	err := r.DB.QuerierFromContext(ctx).QueryRow(`SELECT COUNT(*) FROM "News"`).Scan(&total)
+	return total, err
+}
+
+// GetSitemapChunks группирует новости по диапазонам ID размера chunkSize ([chunk*chunkSize, (chunk+1)*chunkSize))
+// и возвращает непустые диапазоны с временем последнего изменения
+func (r *NewsRepository) GetSitemapChunks(ctx context.Context, chunkSize int64) ([]SitemapChunk, error) {
+	rows, err := r.DB.QuerierFromContext(ctx).Query(`
+		SELECT "Id" / $1 AS chunk, MAX("UpdatedAt")
+		FROM "News"
+		GROUP BY chunk
+		ORDER BY chunk`, chunkSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var chunks []SitemapChunk
+	for rows.Next() {
+		var chunk SitemapChunk
+		if err := rows.Scan(&chunk.Chunk, &chunk.LastMod); err != nil {
+			return nil, err
+		}
+		chunks = append(chunks, chunk)
+	}
+
+	return chunks, rows.Err()
+}
+
+// HasNewsInRange проверяет, есть ли новости с ID в диапазоне [fromID, toID)
+func (r *NewsRepository) HasNewsInRange(ctx context.Context, fromID, toID int64) (bool, error) {
+	var exists bool
+	err := r.DB.QuerierFromContext(ctx).
+		QueryRow(`SELECT EXISTS (SELECT 1 FROM "News" WHERE "Id" >= $1 AND "Id" < $2)`, fromID, toID).
+		Scan(&exists)
+	return exists, err
+}
+
+// EachNewsModified вызывает fn для каждой новости с ID в диапазоне [fromID, toID) в порядке ID.
+// Строки читаются по одной, без загрузки всего диапазона в память; toID <= 0 - без верхней границы.
+func (r *NewsRepository) EachNewsModified(ctx context.Context, fromID, toID int64, fn func(id int64, updatedAt time.Time) error) error {
+	rows, err := r.DB.QuerierFromContext(ctx).Query(`
+		SELECT "Id", "UpdatedAt"
+		FROM "News"
+		WHERE "Id" >= $1 AND ($2 <= 0 OR "Id" < $2)
+		ORDER BY "Id"`, fromID, toID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int64
+		var updatedAt time.Time
+		if err := rows.Scan(&id, &updatedAt); err != nil {
+			return err
+		}
+		if err := fn(id, updatedAt); err != nil {
+			return err
+		}
 	}
 
-	return newsList, nil
+	return rows.Err()
 }
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"go_news_server/internal/models"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/reform.v1/dialects/postgresql"
+)
+
+func TestNewsFilterTail(t *testing.T) {
+	where, args := newsFilterTail(models.NewsFilter{}).BuildWhere(postgresql.Dialect, 1)
+	assert.Empty(t, where)
+	assert.Empty(t, args)
+
+	where, args = newsFilterTail(models.NewsFilter{CategoryID: 7, Tag: " Go "}).BuildWhere(postgresql.Dialect, 1)
+	assert.True(t, strings.HasPrefix(where, "WHERE EXISTS"))
+	assert.Contains(t, where, `WHERE "Id" = $1`)
+	assert.Contains(t, where, `t."Name" = $2`)
+	assert.Equal(t, []interface{}{int64(7), "go"}, args)
+
+	// только тег: категория не занимает $1
+	where, args = newsFilterTail(models.NewsFilter{Tag: "go"}).BuildWhere(postgresql.Dialect, 1)
+	assert.Contains(t, where, `t."Name" = $1`)
+	assert.Equal(t, []interface{}{"go"}, args)
+
+	// курсор добавляется последним условием
+	where, args = newsFilterTail(models.NewsFilter{Tag: "go", AfterID: 64}).BuildWhere(postgresql.Dialect, 1)
+	assert.Contains(t, where, `AND n."Id" > $2`)
+	assert.Equal(t, []interface{}{"go", int64(64)}, args)
+}
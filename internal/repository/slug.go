@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"go_news_server/pkg/slug"
+	"strconv"
+	"strings"
+
+	"gopkg.in/reform.v1"
+)
+
+// slugTable описывает таблицу со slug и таблицу истории прежних slug ее строк
+type slugTable struct {
+	table    string
+	history  string
+	ownerID  string
+	fallback string
+}
+
+var (
+	newsSlugs     = slugTable{table: `"News"`, history: `"NewsSlugHistory"`, ownerID: `"NewsId"`, fallback: "news"}
+	categorySlugs = slugTable{table: `"Categories"`, history: `"CategorySlugHistory"`, ownerID: `"CategoryId"`, fallback: "category"}
+)
+
+// base формирует slug из названия без суффикса коллизии
+func (t slugTable) base(title string) string {
+	if s := slug.Make(title); s != "" {
+		return s
+	}
+	return t.fallback
+}
+
+// free возвращает свободный slug вида base, base-2, base-3...: не занятый другими строками
+// ни сейчас, ни в истории. Прежние slug самой строки id считаются свободными.
+func (t slugTable) free(q *reform.Querier, id int64, base string) (string, error) {
+	rows, err := q.Query(fmt.Sprintf(`
+		SELECT "Slug" FROM %[1]s WHERE ("Slug" = $1 OR "Slug" LIKE $2) AND "Id" <> $3
+		UNION
+		SELECT "Slug" FROM %[2]s WHERE ("Slug" = $1 OR "Slug" LIKE $2) AND %[3]s <> $3`, t.table, t.history, t.ownerID),
+		base, base+"-%", id)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	taken := make(map[string]bool)
+	for rows.Next() {
+		var s string
+		if err := rows.Scan(&s); err != nil {
+			return "", err
+		}
+		taken[s] = true
+	}
+	if err := rows.Err(); err != nil {
+		return "", err
+	}
+
+	candidate := base
+	for n := 2; taken[candidate]; n++ {
+		candidate = base + "-" + strconv.Itoa(n)
+	}
+	return candidate, nil
+}
+
+// assign формирует slug существующей строки id по ее новому названию, прежний slug переносится в историю.
+// Текущий slug не меняется, если он уже получен из этого названия: совпадает с base или, пока base занят,
+// имеет вид base-N. Когда base освобождается, суффикс коллизии снимается.
+func (t slugTable) assign(q *reform.Querier, id int64, title string) (string, error) {
+	var current string
+	err := q.QueryRow(fmt.Sprintf(`SELECT "Slug" FROM %s WHERE "Id" = $1`, t.table), id).Scan(&current)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", nil
+		}
+		return "", err
+	}
+
+	base := t.base(title)
+	next, err := t.free(q, id, base)
+	if err != nil {
+		return "", err
+	}
+	if next == current || next != base && hasSlugSuffix(current, base) {
+		return current, nil
+	}
+
+	_, err = q.Exec(fmt.Sprintf(`
+		INSERT INTO %[1]s ("Slug", %[2]s) VALUES ($1, $2)
+		ON CONFLICT ("Slug") DO UPDATE SET %[2]s = EXCLUDED.%[2]s, "CreatedAt" = CURRENT_TIMESTAMP`, t.history, t.ownerID),
+		current, id)
+	if err != nil {
+		return "", err
+	}
+	if _, err := q.Exec(fmt.Sprintf(`DELETE FROM %s WHERE "Slug" = $1`, t.history), next); err != nil {
+		return "", err
+	}
+	if _, err := q.Exec(fmt.Sprintf(`UPDATE %s SET "Slug" = $1 WHERE "Id" = $2`, t.table), next, id); err != nil {
+		return "", err
+	}
+	return next, nil
+}
+
+// hasSlugSuffix проверяет, что s имеет вид base-N
+func hasSlugSuffix(s, base string) bool {
+	n, ok := strings.CutPrefix(s, base+"-")
+	if !ok || n == "" {
+		return false
+	}
+	for _, r := range n {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// redirect возвращает текущий slug строки, которой раньше принадлежал slug, или "", если такой нет
+func (t slugTable) redirect(q *reform.Querier, old string) (string, error) {
+	var current string
+	err := q.QueryRow(fmt.Sprintf(`
+		SELECT t."Slug" FROM %[1]s h
+		INNER JOIN %[2]s t ON t."Id" = h.%[3]s
+		WHERE h."Slug" = $1`, t.history, t.table, t.ownerID), old).Scan(&current)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return current, err
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHasSlugSuffix(t *testing.T) {
+	assert.True(t, hasSlugSuffix("foo-2", "foo"))
+	assert.True(t, hasSlugSuffix("foo-bar-15", "foo-bar"))
+	assert.False(t, hasSlugSuffix("foo", "foo"))
+	assert.False(t, hasSlugSuffix("foo-", "foo"))
+	assert.False(t, hasSlugSuffix("foo-bar", "foo"))
+	assert.False(t, hasSlugSuffix("foobar-2", "foo"))
+}
@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"go_news_server/internal/models"
+	"strings"
+
+	"gopkg.in/reform.v1"
+)
+
+type TagRepository struct {
+	DB *reform.DB
+}
+
+// likeEscaper экранирует спецсимволы LIKE в префиксе
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// GetTags получает используемые теги с числом новостей, начиная с самых популярных.
+// Пустой prefix - все теги, иначе только начинающиеся с prefix (уже нормализованного).
+func (r *TagRepository) GetTags(ctx context.Context, prefix string, limit int) ([]models.Tag, error) {
+	rows, err := r.DB.QuerierFromContext(ctx).Query(`
+		SELECT t."Id", t."Name", COUNT(*) AS count
+		FROM "Tags" t
+		INNER JOIN "NewsTags" nt ON nt."TagId" = t."Id"
+		WHERE t."Name" LIKE $1
+		GROUP BY t."Id"
+		ORDER BY count DESC, t."Name"
+		LIMIT $2`, likeEscaper.Replace(prefix)+"%", limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := []models.Tag{}
+	for rows.Next() {
+		var tag models.Tag
+		if err := rows.Scan(&tag.Id, &tag.Name, &tag.Count); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+
+	return tags, rows.Err()
+}
@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"go_news_server/internal/models"
+
+	"github.com/lib/pq"
+	"gopkg.in/reform.v1"
+)
+
+type TranslationRepository struct {
+	DB *reform.DB
+}
+
+const newsTranslationColumns = `"NewsId", "Locale", "Title", "Content", "ContentFormat", "ContentSource", "ContentText", "CreatedAt", "UpdatedAt"`
+
+func scanNewsTranslation(row rowScanner) (models.NewsTranslation, error) {
+	var t models.NewsTranslation
+	err := row.Scan(&t.NewsId, &t.Locale, &t.Title, &t.Content, &t.ContentFormat, &t.ContentSource, &t.ContentText, &t.CreatedAt, &t.UpdatedAt)
+	return t, err
+}
+
+// PutNewsTranslation создает или заменяет перевод новости. Время изменения новости обновляется,
+// чтобы ленты и кэши увидели новый перевод. sql.ErrNoRows, если новости нет.
+func (r *TranslationRepository) PutNewsTranslation(ctx context.Context, t *models.NewsTranslation) error {
+	return r.DB.InTransactionContext(ctx, nil, func(tx *reform.TX) error {
+		if err := touchNews(tx.Querier, t.NewsId); err != nil {
+			return err
+		}
+
+		return tx.QueryRow(`
+			INSERT INTO "NewsTranslations" ("NewsId", "Locale", "Title", "Content", "ContentFormat", "ContentSource", "ContentText")
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			ON CONFLICT ("NewsId", "Locale") DO UPDATE SET
+				"Title" = EXCLUDED."Title",
+				"Content" = EXCLUDED."Content",
+				"ContentFormat" = EXCLUDED."ContentFormat",
+				"ContentSource" = EXCLUDED."ContentSource",
+				"ContentText" = EXCLUDED."ContentText",
+				"UpdatedAt" = CURRENT_TIMESTAMP
+			RETURNING "CreatedAt", "UpdatedAt"`,
+			t.NewsId, t.Locale, t.Title, t.Content, t.ContentFormat, t.ContentSource, t.ContentText).
+			Scan(&t.CreatedAt, &t.UpdatedAt)
+	})
+}
+
+// DeleteNewsTranslation удаляет перевод новости. sql.ErrNoRows, если перевода нет.
+func (r *TranslationRepository) DeleteNewsTranslation(ctx context.Context, newsID int64, locale string) error {
+	return r.DB.InTransactionContext(ctx, nil, func(tx *reform.TX) error {
+		if err := touchNews(tx.Querier, newsID); err != nil {
+			return err
+		}
+		return execOne(tx.Querier, `DELETE FROM "NewsTranslations" WHERE "NewsId" = $1 AND "Locale" = $2`, newsID, locale)
+	})
+}
+
+// GetNewsTranslations получает все переводы новости по языкам
+func (r *TranslationRepository) GetNewsTranslations(ctx context.Context, newsID int64) ([]models.NewsTranslation, error) {
+	return r.queryNewsTranslations(ctx, `
+		SELECT `+newsTranslationColumns+`
+		FROM "NewsTranslations"
+		WHERE "NewsId" = $1
+		ORDER BY "Locale"`, newsID)
+}
+
+// GetNewsTranslationsIn получает переводы новостей newsIDs на языки locales
+func (r *TranslationRepository) GetNewsTranslationsIn(ctx context.Context, newsIDs []int64, locales []string) ([]models.NewsTranslation, error) {
+	if len(newsIDs) == 0 || len(locales) == 0 {
+		return nil, nil
+	}
+	return r.queryNewsTranslations(ctx, `
+		SELECT `+newsTranslationColumns+`
+		FROM "NewsTranslations"
+		WHERE "NewsId" = ANY($1) AND "Locale" = ANY($2)`, pq.Array(newsIDs), pq.Array(locales))
+}
+
+func (r *TranslationRepository) queryNewsTranslations(ctx context.Context, query string, args ...interface{}) ([]models.NewsTranslation, error) {
+	rows, err := r.DB.QuerierFromContext(ctx).Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	translations := []models.NewsTranslation{}
+	for rows.Next() {
+		t, err := scanNewsTranslation(rows)
+		if err != nil {
+			return nil, err
+		}
+		translations = append(translations, t)
+	}
+	return translations, rows.Err()
+}
+
+// PutCategoryTranslation создает или заменяет перевод категории. sql.ErrNoRows, если категории нет.
+func (r *TranslationRepository) PutCategoryTranslation(ctx context.Context, t *models.CategoryTranslation) error {
+	return r.DB.InTransactionContext(ctx, nil, func(tx *reform.TX) error {
+		if err := touchCategory(tx.Querier, t.CategoryId); err != nil {
+			return err
+		}
+
+		return tx.QueryRow(`
+			INSERT INTO "CategoryTranslations" ("CategoryId", "Locale", "Name", "Description")
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT ("CategoryId", "Locale") DO UPDATE SET
+				"Name" = EXCLUDED."Name",
+				"Description" = EXCLUDED."Description",
+				"UpdatedAt" = CURRENT_TIMESTAMP
+			RETURNING "CreatedAt", "UpdatedAt"`,
+			t.CategoryId, t.Locale, t.Name, t.Description).
+			Scan(&t.CreatedAt, &t.UpdatedAt)
+	})
+}
+
+// DeleteCategoryTranslation удаляет перевод категории. sql.ErrNoRows, если перевода нет.
+func (r *TranslationRepository) DeleteCategoryTranslation(ctx context.Context, categoryID int64, locale string) error {
+	return r.DB.InTransactionContext(ctx, nil, func(tx *reform.TX) error {
+		if err := touchCategory(tx.Querier, categoryID); err != nil {
+			return err
+		}
+		return execOne(tx.Querier, `DELETE FROM "CategoryTranslations" WHERE "CategoryId" = $1 AND "Locale" = $2`, categoryID, locale)
+	})
+}
+
+// GetCategoryTranslationsIn получает переводы категорий categoryIDs на языки locales
+func (r *TranslationRepository) GetCategoryTranslationsIn(ctx context.Context, categoryIDs []int64, locales []string) ([]models.CategoryTranslation, error) {
+	if len(categoryIDs) == 0 || len(locales) == 0 {
+		return nil, nil
+	}
+
+	rows, err := r.DB.QuerierFromContext(ctx).Query(`
+		SELECT "CategoryId", "Locale", "Name", "Description", "CreatedAt", "UpdatedAt"
+		FROM "CategoryTranslations"
+		WHERE "CategoryId" = ANY($1) AND "Locale" = ANY($2)`, pq.Array(categoryIDs), pq.Array(locales))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var translations []models.CategoryTranslation
+	for rows.Next() {
+		var t models.CategoryTranslation
+		if err := rows.Scan(&t.CategoryId, &t.Locale, &t.Name, &t.Description, &t.CreatedAt, &t.UpdatedAt); err != nil {
+			return nil, err
+		}
+		translations = append(translations, t)
+	}
+	return translations, rows.Err()
+}
+
+// touchCategory обновляет "UpdatedAt" категории. sql.ErrNoRows, если категории нет.
+func touchCategory(q *reform.Querier, categoryID int64) error {
+	return execOne(q, `UPDATE "Categories" SET "UpdatedAt" = CURRENT_TIMESTAMP WHERE "Id" = $1`, categoryID)
+}
+
+// execOne выполняет изменение и возвращает sql.ErrNoRows, если оно не затронуло ни одной строки
+func execOne(q *reform.Querier, query string, args ...interface{}) error {
+	result, err := q.Exec(query, args...)
+	if err != nil {
+		return err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"go_news_server/internal/models"
+	"sort"
+	"time"
+
+	"github.com/lib/pq"
+	"gopkg.in/reform.v1"
+)
+
+type ViewRepository struct {
+	DB *reform.DB
+}
+
+// PopularNews популярная новость и число ее просмотров за период
+type PopularNews struct {
+	NewsId int64
+	Views  int64
+}
+
+// AddViews прибавляет просмотры к часовым и общим счетчикам одной транзакцией.
+// Просмотры удаленных новостей пропускаются. Строки блокируются в порядке ID новости и часа,
+// поэтому записи нескольких экземпляров сервера не взаимоблокируются.
+func (r *ViewRepository) AddViews(ctx context.Context, counts []models.NewsViewCount) error {
+	if len(counts) == 0 {
+		return nil
+	}
+
+	sorted := append([]models.NewsViewCount(nil), counts...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].NewsId != sorted[j].NewsId {
+			return sorted[i].NewsId < sorted[j].NewsId
+		}
+		return sorted[i].Hour.Before(sorted[j].Hour)
+	})
+
+	ids := make([]int64, len(sorted))
+	hours := make([]int64, len(sorted))
+	views := make([]int64, len(sorted))
+	for i, item := range sorted {
+		ids[i] = item.NewsId
+		hours[i] = item.Hour.Unix()
+		views[i] = item.Count
+	}
+
+	return r.DB.InTransactionContext(ctx, nil, func(tx *reform.TX) error {
+		_, err := tx.Exec(`
+			INSERT INTO "NewsViews" ("NewsId", "Hour", "Count")
+			SELECT v.news_id, to_timestamp(v.hour), v.count
+			FROM unnest($1::bigint[], $2::bigint[], $3::bigint[]) WITH ORDINALITY AS v(news_id, hour, count, ord)
+			WHERE EXISTS (SELECT 1 FROM "News" n WHERE n."Id" = v.news_id)
+			ORDER BY v.ord
+			ON CONFLICT ("NewsId", "Hour") DO UPDATE SET "Count" = "NewsViews"."Count" + EXCLUDED."Count"`,
+			pq.Array(ids), pq.Array(hours), pq.Array(views))
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(`
+			INSERT INTO "NewsViewTotals" ("NewsId", "Views")
+			SELECT v.news_id, SUM(v.count)
+			FROM unnest($1::bigint[], $2::bigint[]) AS v(news_id, count)
+			WHERE EXISTS (SELECT 1 FROM "News" n WHERE n."Id" = v.news_id)
+			GROUP BY v.news_id
+			ORDER BY v.news_id
+			ON CONFLICT ("NewsId") DO UPDATE SET "Views" = "NewsViewTotals"."Views" + EXCLUDED."Views"`,
+			pq.Array(ids), pq.Array(views))
+		return err
+	})
+}
+
+// DeleteViewsBefore удаляет часовые счетчики старше before, общие счетчики не меняются
+func (r *ViewRepository) DeleteViewsBefore(ctx context.Context, before time.Time) error {
+	_, err := r.DB.QuerierFromContext(ctx).Exec(`DELETE FROM "NewsViews" WHERE "Hour" < $1`, before)
+	return err
+}
+
+// GetPopularNewsIDs получает до limit новостей с наибольшим числом просмотров начиная с часа since.
+// categoryID ограничивает выборку новостями категории и ее дочерних категорий, 0 - все новости.
+func (r *ViewRepository) GetPopularNewsIDs(ctx context.Context, since time.Time, categoryID int64, limit int) ([]PopularNews, error) {
+	rows, err := r.DB.QuerierFromContext(ctx).Query(categorySubtreeCTE+`
+		SELECT v."NewsId", SUM(v."Count") AS views
+		FROM "NewsViews" v
+		WHERE v."Hour" >= $2
+		  AND ($1 = 0 OR EXISTS (
+			SELECT 1 FROM "NewsCategories" f WHERE f."NewsId" = v."NewsId" AND f."CategoryId" IN (SELECT "Id" FROM category_tree)
+		  ))
+		GROUP BY v."NewsId"
+		ORDER BY views DESC, v."NewsId" DESC
+		LIMIT $3`, categoryID, since, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	popular := []PopularNews{}
+	for rows.Next() {
+		var item PopularNews
+		if err := rows.Scan(&item.NewsId, &item.Views); err != nil {
+			return nil, err
+		}
+		popular = append(popular, item)
+	}
+	return popular, rows.Err()
+}
+
+// GetNewsViews получает счетчики просмотров новости, периоды отсчитываются от now.
+// Для несуществующей новости возвращает nil.
+func (r *ViewRepository) GetNewsViews(ctx context.Context, newsID int64, now time.Time) (*models.NewsViews, error) {
+	views := models.NewsViews{NewsId: newsID}
+	err := r.DB.QuerierFromContext(ctx).QueryRow(`
+		SELECT COALESCE(t."Views", 0),
+		       COALESCE((SELECT SUM("Count") FROM "NewsViews" WHERE "NewsId" = n."Id" AND "Hour" >= $2), 0),
+		       COALESCE((SELECT SUM("Count") FROM "NewsViews" WHERE "NewsId" = n."Id" AND "Hour" >= $3), 0)
+		FROM "News" n
+		LEFT JOIN "NewsViewTotals" t ON t."NewsId" = n."Id"
+		WHERE n."Id" = $1`, newsID, now.Add(-24*time.Hour), now.Add(-7*24*time.Hour)).Scan(&views.Total, &views.Last24h, &views.Last7d)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &views, nil
+}
+
+// GetViewTotals получает просмотры за все время новостей ids. Новостей без просмотров нет в результате.
+func (r *ViewRepository) GetViewTotals(ctx context.Context, ids []int64) (map[int64]int64, error) {
+	totals := make(map[int64]int64, len(ids))
+	if len(ids) == 0 {
+		return totals, nil
+	}
+
+	rows, err := r.DB.QuerierFromContext(ctx).Query(`
+		SELECT "NewsId", "Views" FROM "NewsViewTotals" WHERE "NewsId" = ANY($1)`, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var newsID, views int64
+		if err := rows.Scan(&newsID, &views); err != nil {
+			return nil, err
+		}
+		totals[newsID] = views
+	}
+	return totals, rows.Err()
+}
+
+// NewsExists проверяет, что новость существует
+func (r *ViewRepository) NewsExists(ctx context.Context, newsID int64) (bool, error) {
+	var exists bool
+	err := r.DB.QuerierFromContext(ctx).
+		QueryRow(`SELECT EXISTS (SELECT 1 FROM "News" WHERE "Id" = $1)`, newsID).
+		Scan(&exists)
+	return exists, err
+}
@@ -0,0 +1,307 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"go_news_server/internal/models"
+	"time"
+
+	"github.com/lib/pq"
+	"gopkg.in/reform.v1"
+)
+
+type WebhookRepository struct {
+	DB *reform.DB
+}
+
+const webhookColumns = `"Id", "Url", "Secret", "EventTypes", "CategoryIds", "Active", "CreatedAt", "UpdatedAt"`
+
+const deliveryColumns = `"Id", "WebhookId", "OutboxId", "EventType", "Status", "Attempts", "NextAttemptAt", "LastStatusCode", "LastError", "CreatedAt", "UpdatedAt"`
+
+// insertOutboxEvent записывает событие для вебхуков в outbox.
+// Вызывается в транзакции, изменяющей данные, чтобы событие не потерялось и не появилось без изменения.
+func insertOutboxEvent(q *reform.Querier, eventType string, categoryIDs []int64, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	_, err = q.Exec(`INSERT INTO "WebhookOutbox" ("EventType", "CategoryIds", "Payload") VALUES ($1, $2, $3)`,
+		eventType, pq.Array(nonNilIDs(categoryIDs)), string(payload))
+	return err
+}
+
+// unionIDs объединяет списки ID без повторов
+func unionIDs(a, b []int64) []int64 {
+	seen := make(map[int64]struct{}, len(a)+len(b))
+	res := make([]int64, 0, len(a)+len(b))
+	for _, list := range [][]int64{a, b} {
+		for _, id := range list {
+			if _, ok := seen[id]; !ok {
+				seen[id] = struct{}{}
+				res = append(res, id)
+			}
+		}
+	}
+	return res
+}
+
+// nonNilIDs заменяет nil на пустой список: pq.Array(nil) записывается как NULL
+func nonNilIDs(ids []int64) []int64 {
+	if ids == nil {
+		return []int64{}
+	}
+	return ids
+}
+
+func nonNilStrings(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
+
+func scanWebhook(row interface{ Scan(...interface{}) error }, webhook *models.Webhook) error {
+	return row.Scan(&webhook.Id, &webhook.Url, &webhook.Secret, pq.Array(&webhook.EventTypes), pq.Array(&webhook.CategoryIds),
+		&webhook.Active, &webhook.CreatedAt, &webhook.UpdatedAt)
+}
+
+func scanDelivery(row interface{ Scan(...interface{}) error }, delivery *models.WebhookDelivery, extra ...interface{}) error {
+	dest := []interface{}{&delivery.Id, &delivery.WebhookId, &delivery.OutboxId, &delivery.EventType, &delivery.Status,
+		&delivery.Attempts, &delivery.NextAttemptAt, &delivery.LastStatusCode, &delivery.LastError, &delivery.CreatedAt, &delivery.UpdatedAt}
+	return row.Scan(append(dest, extra...)...)
+}
+
+// CreateWebhook создает подписку
+func (r *WebhookRepository) CreateWebhook(ctx context.Context, webhook *models.Webhook) error {
+	query := `
+		INSERT INTO "Webhooks" ("Url", "Secret", "EventTypes", "CategoryIds", "Active")
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING "Id", "CreatedAt", "UpdatedAt"
+	`
+
+	return r.DB.QuerierFromContext(ctx).QueryRow(query, webhook.Url, webhook.Secret,
+		pq.Array(nonNilStrings(webhook.EventTypes)), pq.Array(nonNilIDs(webhook.CategoryIds)), webhook.Active).
+		Scan(&webhook.Id, &webhook.CreatedAt, &webhook.UpdatedAt)
+}
+
+// GetWebhookByID получает подписку по ID, возвращает nil, если подписка не найдена
+func (r *WebhookRepository) GetWebhookByID(ctx context.Context, id int64) (*models.Webhook, error) {
+	query := `SELECT ` + webhookColumns + ` FROM "Webhooks" WHERE "Id" = $1`
+
+	var webhook models.Webhook
+	if err := scanWebhook(r.DB.QuerierFromContext(ctx).QueryRow(query, id), &webhook); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &webhook, nil
+}
+
+// GetAllWebhooks получает все подписки
+func (r *WebhookRepository) GetAllWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	query := `SELECT ` + webhookColumns + ` FROM "Webhooks" ORDER BY "Id"`
+
+	rows, err := r.DB.QuerierFromContext(ctx).Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	webhooks := []models.Webhook{}
+	for rows.Next() {
+		var webhook models.Webhook
+		if err := scanWebhook(rows, &webhook); err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, webhook)
+	}
+
+	return webhooks, rows.Err()
+}
+
+// UpdateWebhook обновляет подписку, возвращает sql.ErrNoRows, если подписки нет
+func (r *WebhookRepository) UpdateWebhook(ctx context.Context, webhook *models.Webhook) error {
+	query := `
+		UPDATE "Webhooks"
+		SET "Url" = $1, "Secret" = $2, "EventTypes" = $3, "CategoryIds" = $4, "Active" = $5, "UpdatedAt" = CURRENT_TIMESTAMP
+		WHERE "Id" = $6
+		RETURNING "UpdatedAt"
+	`
+
+	return r.DB.QuerierFromContext(ctx).QueryRow(query, webhook.Url, webhook.Secret,
+		pq.Array(nonNilStrings(webhook.EventTypes)), pq.Array(nonNilIDs(webhook.CategoryIds)), webhook.Active, webhook.Id).
+		Scan(&webhook.UpdatedAt)
+}
+
+// DeleteWebhook удаляет подписку вместе с журналом доставок
+func (r *WebhookRepository) DeleteWebhook(ctx context.Context, id int64) error {
+	result, err := r.DB.QuerierFromContext(ctx).Exec(`DELETE FROM "Webhooks" WHERE "Id" = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// GetDeliveries получает журнал доставок подписки, новые записи первыми. Пустой status - все статусы.
+func (r *WebhookRepository) GetDeliveries(ctx context.Context, webhookID int64, status string, limit, offset int) ([]models.WebhookDelivery, int64, error) {
+	q := r.DB.QuerierFromContext(ctx)
+
+	var total int64
+	err := q.QueryRow(`SELECT COUNT(*) FROM "WebhookDeliveries" WHERE "WebhookId" = $1 AND ($2 = '' OR "Status" = $2)`, webhookID, status).
+		Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := `
+		SELECT ` + deliveryColumns + `
+		FROM "WebhookDeliveries"
+		WHERE "WebhookId" = $1 AND ($2 = '' OR "Status" = $2)
+		ORDER BY "Id" DESC
+		LIMIT $3 OFFSET $4
+	`
+
+	rows, err := q.Query(query, webhookID, status, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	deliveries := []models.WebhookDelivery{}
+	for rows.Next() {
+		var delivery models.WebhookDelivery
+		if err := scanDelivery(rows, &delivery); err != nil {
+			return nil, 0, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, total, rows.Err()
+}
+
+// RequeueDelivery возвращает доставку из dead letter в очередь с обнулением попыток.
+// Возвращает sql.ErrNoRows, если доставки нет или она не в статусе dead.
+func (r *WebhookRepository) RequeueDelivery(ctx context.Context, id int64) (*models.WebhookDelivery, error) {
+	query := `
+		UPDATE "WebhookDeliveries"
+		SET "Status" = $2, "Attempts" = 0, "NextAttemptAt" = CURRENT_TIMESTAMP, "UpdatedAt" = CURRENT_TIMESTAMP
+		WHERE "Id" = $1 AND "Status" = $3
+		RETURNING ` + deliveryColumns
+
+	var delivery models.WebhookDelivery
+	err := scanDelivery(r.DB.QuerierFromContext(ctx).QueryRow(query, id, models.DeliveryPending, models.DeliveryDead), &delivery)
+	if err != nil {
+		return nil, err
+	}
+
+	return &delivery, nil
+}
+
+// DispatchOutbox раскладывает необработанные события outbox по подходящим активным подпискам
+// и отмечает их обработанными. Возвращает количество обработанных событий.
+func (r *WebhookRepository) DispatchOutbox(ctx context.Context, batchSize int) (int64, error) {
+	query := `
+		WITH batch AS (
+			SELECT "Id", "EventType", "CategoryIds"
+			FROM "WebhookOutbox"
+			WHERE "ProcessedAt" IS NULL
+			ORDER BY "Id"
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		), deliveries AS (
+			INSERT INTO "WebhookDeliveries" ("WebhookId", "OutboxId", "EventType")
+			SELECT w."Id", b."Id", b."EventType"
+			FROM batch b
+			JOIN "Webhooks" w ON w."Active"
+				AND (cardinality(w."EventTypes") = 0 OR b."EventType" = ANY(w."EventTypes"))
+				AND (cardinality(w."CategoryIds") = 0 OR w."CategoryIds" && b."CategoryIds")
+			ON CONFLICT ("WebhookId", "OutboxId") DO NOTHING
+		)
+		UPDATE "WebhookOutbox" o
+		SET "ProcessedAt" = CURRENT_TIMESTAMP
+		FROM batch b
+		WHERE o."Id" = b."Id"
+	`
+
+	result, err := r.DB.QuerierFromContext(ctx).Exec(query, batchSize)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// ClaimDeliveries берет в работу доставки, время попытки которых наступило.
+// Следующая попытка сразу переносится на lease вперед, поэтому доставку не возьмет другой экземпляр,
+// а при падении процесса она будет повторена после истечения lease.
+func (r *WebhookRepository) ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]models.WebhookTask, error) {
+	query := `
+		UPDATE "WebhookDeliveries" d
+		SET "Attempts" = d."Attempts" + 1,
+			"NextAttemptAt" = CURRENT_TIMESTAMP + $2::float8 * INTERVAL '1 millisecond',
+			"UpdatedAt" = CURRENT_TIMESTAMP
+		FROM "Webhooks" w, "WebhookOutbox" o
+		WHERE d."Id" IN (
+				SELECT pd."Id"
+				FROM "WebhookDeliveries" pd
+				JOIN "Webhooks" pw ON pw."Id" = pd."WebhookId" AND pw."Active"
+				WHERE pd."Status" = $3 AND pd."NextAttemptAt" <= CURRENT_TIMESTAMP
+				ORDER BY pd."NextAttemptAt"
+				LIMIT $1
+				FOR UPDATE OF pd SKIP LOCKED
+			)
+			AND w."Id" = d."WebhookId"
+			AND o."Id" = d."OutboxId"
+		RETURNING d."Id", d."WebhookId", d."OutboxId", d."EventType", d."Status", d."Attempts", d."NextAttemptAt",
+			d."LastStatusCode", d."LastError", d."CreatedAt", d."UpdatedAt", w."Url", w."Secret", o."Payload", o."CreatedAt"
+	`
+
+	rows, err := r.DB.QuerierFromContext(ctx).Query(query, limit, lease.Milliseconds(), models.DeliveryPending)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tasks []models.WebhookTask
+	for rows.Next() {
+		var task models.WebhookTask
+		var payload []byte
+		if err := scanDelivery(rows, &task.Delivery, &task.Url, &task.Secret, &payload, &task.CreatedAt); err != nil {
+			return nil, err
+		}
+		task.Payload = payload
+		tasks = append(tasks, task)
+	}
+
+	return tasks, rows.Err()
+}
+
+// SaveDeliveryResult сохраняет результат попытки доставки.
+// Для статуса pending следующая попытка назначается через retryAfter.
+func (r *WebhookRepository) SaveDeliveryResult(ctx context.Context, id int64, status string, statusCode int, lastError string, retryAfter time.Duration) error {
+	query := `
+		UPDATE "WebhookDeliveries"
+		SET "Status" = $2,
+			"LastStatusCode" = NULLIF($3, 0),
+			"LastError" = NULLIF($4, ''),
+			"NextAttemptAt" = CURRENT_TIMESTAMP + $5::float8 * INTERVAL '1 millisecond',
+			"UpdatedAt" = CURRENT_TIMESTAMP
+		WHERE "Id" = $1
+	`
+
+	_, err := r.DB.QuerierFromContext(ctx).Exec(query, id, status, statusCode, lastError, retryAfter.Milliseconds())
+	return err
+}
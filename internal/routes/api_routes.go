@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"time"
+
+	v1 "go_news_server/internal/api/v1"
+	"go_news_server/internal/middleware"
+	"go_news_server/pkg/config"
+	"go_news_server/pkg/locale"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// legacyDeprecatedSince - дата, с которой маршруты без версии, замененные /api/v1, считаются устаревшими
+var legacyDeprecatedSince = time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+
+// deprecated помечает маршрут без версии устаревшим со ссылкой на маршрут замены successor
+func deprecated(successor string) func(*fiber.Ctx) error {
+	return middleware.Deprecated(legacyDeprecatedSince, successor)
+}
+
+// APIRoutes настраивает версионированный API. Каждая версия - отдельный пакет internal/api/vN
+// со своими DTO и маршрутами в группе /api/vN; версии работают одновременно.
+func APIRoutes(a *fiber.App, v1Handler *v1.Handler, locales *locale.Locales, cfg *config.Config) {
+	api := a.Group("/api")
+	v1.Routes(api.Group("/v1"), v1Handler, locales, cfg)
+}
@@ -2,23 +2,33 @@ package routes
 
 import (
 	"go_news_server/internal/handlers"
+	"go_news_server/internal/middleware"
+	"go_news_server/pkg/locale"
 
 	"github.com/gofiber/fiber/v2"
 )
 
-// SetupCategoryRoutes настраивает маршруты для категорий
-func SetupCategoryRoutes(app *fiber.App, categoryHandler *handlers.CategoryHandler) {
+// SetupCategoryRoutes настраивает маршруты для категорий. Чтение возвращает названия на языке,
+// выбранном по ?lang и Accept-Language.
+func SetupCategoryRoutes(app *fiber.App, categoryHandler *handlers.CategoryHandler, locales *locale.Locales) {
 	// Группа маршрутов для категорий
 	categories := app.Group("/categories")
+	localized := middleware.Locale(locales)
 
 	// CRUD операции для категорий
-	categories.Post("/", categoryHandler.CreateCategory)      // Создание категории
-	categories.Get("/", categoryHandler.GetAllCategories)     // Получение всех категорий
-	categories.Get("/:id", categoryHandler.GetCategoryByID)   // Получение категории по ID
-	categories.Put("/:id", categoryHandler.UpdateCategory)    // Обновление категории
-	categories.Delete("/:id", categoryHandler.DeleteCategory) // Удаление категории
+	categories.Post("/", deprecated("/api/v1/categories"), categoryHandler.CreateCategory)                                         // Создание категории
+	categories.Get("/", deprecated("/api/v1/categories"), localized, categoryHandler.GetAllCategories)                             // Получение всех категорий
+	categories.Get("/tree", deprecated("/api/v1/categories/tree"), localized, categoryHandler.GetCategoryTree)                     // Дерево категорий
+	categories.Get("/by-slug/:slug", deprecated("/api/v1/categories/by-slug/:slug"), localized, categoryHandler.GetCategoryBySlug) // Получение категории по slug
+	categories.Get("/:id", deprecated("/api/v1/categories/:id"), localized, categoryHandler.GetCategoryByID)                       // Получение категории по ID
+	categories.Put("/:id", deprecated("/api/v1/categories/:id"), categoryHandler.UpdateCategory)                                   // Обновление категории
+	categories.Delete("/:id", deprecated("/api/v1/categories/:id"), categoryHandler.DeleteCategory)                                // Удаление категории
+
+	// Операции с деревом категорий
+	categories.Get("/:id/descendants", deprecated("/api/v1/categories/:id/descendants"), localized, categoryHandler.GetCategoryDescendants) // Все потомки категории
+	categories.Put("/:id/move", deprecated("/api/v1/categories/:id/move"), categoryHandler.MoveCategory)                                    // Перенос и изменение позиции
 
 	// Дополнительные маршруты
 	news := app.Group("/news")
-	news.Get("/:id/categories", categoryHandler.GetCategoriesByNewsID) // Получение категорий для новости
+	news.Get("/:id/categories", deprecated("/api/v1/news/:id/categories"), localized, categoryHandler.GetCategoriesByNewsID) // Получение категорий для новости
 }
@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"go_news_server/internal/handlers"
+	"go_news_server/internal/middleware"
+	"go_news_server/pkg/config"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// CommentRoutes настраивает маршруты комментариев и модерации.
+// Без READER_JWT_SECRET читатели не могут оставлять комментарии: маршрут добавления не регистрируется.
+func CommentRoutes(a *fiber.App, commentHandler *handlers.CommentHandler, cfg *config.Config) {
+	a.Get("/news/:id/comments", commentHandler.GetComments) // Ветки одобренных комментариев
+	if cfg.ReaderJWTSecret != "" {
+		a.Post("/news/:id/comments", middleware.Reader(cfg.ReaderJWTSecret), commentHandler.CreateComment) // Комментарий читателя
+	}
+
+	private := a.Group("/private/comments", middleware.KeyProtected(cfg.SecretKey))
+	private.Get("/", commentHandler.GetModerationQueue)   // Очередь модерации
+	private.Post("/:id/approve", commentHandler.Approve)  // Одобрение
+	private.Post("/:id/reject", commentHandler.Reject)    // Отклонение
+	private.Post("/:id/ban", commentHandler.Ban)          // Блокировка автора
+	private.Delete("/bans/:userId", commentHandler.Unban) // Снятие блокировки
+}
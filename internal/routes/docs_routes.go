@@ -0,0 +1,14 @@
+package routes
+
+import (
+	"go_news_server/internal/handlers"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// DocsRoutes настраивает маршруты описания API
+func DocsRoutes(a *fiber.App, docsHandler *handlers.DocsHandler) {
+	a.Get("/openapi.json", docsHandler.GetOpenAPI)                // Документ OpenAPI 3.1
+	a.Get("/docs", docsHandler.GetDocs)                           // Swagger UI
+	a.Get(handlers.DocsAssetsPath+"/:file", docsHandler.GetAsset) // Встроенные файлы Swagger UI
+}
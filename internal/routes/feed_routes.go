@@ -0,0 +1,14 @@
+package routes
+
+import (
+	"go_news_server/internal/handlers"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// FeedRoutes настраивает маршруты RSS и Atom лент
+func FeedRoutes(a *fiber.App, feedHandler *handlers.FeedHandler) {
+	a.Get("/feeds/rss.xml", feedHandler.GetRSS)                    // Лента RSS 2.0
+	a.Get("/feeds/atom.xml", feedHandler.GetAtom)                  // Лента Atom
+	a.Get("/categories/:id/feed.xml", feedHandler.GetCategoryFeed) // Лента категории
+}
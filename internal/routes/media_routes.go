@@ -0,0 +1,36 @@
+package routes
+
+import (
+	"go_news_server/internal/handlers"
+	"go_news_server/internal/middleware"
+	"go_news_server/pkg/config"
+	"net/url"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// MediaRoutes настраивает маршруты изображений новостей. При локальном хранилище
+// файлы раздаются по MEDIA_BASE_URL из каталога MEDIA_LOCAL_DIR.
+func MediaRoutes(a *fiber.App, mediaHandler *handlers.MediaHandler, cfg *config.Config) {
+	news := a.Group("/private/news", middleware.KeyProtected(cfg.SecretKey))
+
+	news.Post("/:id/media", mediaHandler.Upload)            // Загрузка изображения
+	news.Put("/:id/media/:mediaId", mediaHandler.Update)    // Подпись, позиция и обложка
+	news.Delete("/:id/media/:mediaId", mediaHandler.Delete) // Удаление изображения
+
+	if cfg.MediaStorage == config.MediaStorageLocal {
+		// MEDIA_BASE_URL может быть абсолютным адресом, маршрут - его путь
+		prefix := cfg.MediaBaseURL
+		if u, err := url.Parse(prefix); err == nil && u.Path != "" {
+			prefix = u.Path
+		}
+		a.Static(prefix, cfg.MediaLocalDir, fiber.Static{
+			MaxAge: 86400,
+			ModifyResponse: func(c *fiber.Ctx) error {
+				// тип файла определен при загрузке, браузер не должен угадывать его заново
+				c.Set(fiber.HeaderXContentTypeOptions, "nosniff")
+				return nil
+			},
+		})
+	}
+}
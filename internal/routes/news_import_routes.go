@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"go_news_server/internal/handlers"
+	"go_news_server/internal/middleware"
+	"go_news_server/pkg/config"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// NewsImportRoutes настраивает маршруты импорта и экспорта новостей
+func NewsImportRoutes(a *fiber.App, importHandler *handlers.NewsImportHandler, cfg *config.Config) {
+	news := a.Group("/private/news", middleware.KeyProtected(cfg.SecretKey))
+
+	news.Post("/import", importHandler.Import) // Импорт из NDJSON или CSV
+	news.Get("/export", importHandler.Export)  // Экспорт в NDJSON или CSV
+}
@@ -8,11 +8,8 @@ func NotFoundRoute(a *fiber.App) {
 	a.Use(
 		// Anonymous function.
 		func(c *fiber.Ctx) error {
-			// Return HTTP 404 status and JSON response.
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"error": true,
-				"msg":   "sorry, endpoint is not found",
-			})
+			// Return HTTP 404 error, the response is rendered by the app ErrorHandler.
+			return fiber.NewError(fiber.StatusNotFound, "Endpoint is not found")
 		},
 	)
 }
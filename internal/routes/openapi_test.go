@@ -0,0 +1,62 @@
+package routes
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"go_news_server/internal/api"
+	"go_news_server/pkg/config"
+	"go_news_server/pkg/locale"
+	"go_news_server/pkg/openapi"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestOpenAPIMatchesRoutes проверяет, что маршруты, описанные в api.OpenAPI, совпадают
+// с маршрутами Setup: новые маршруты нужно описать в документе, а удаленные - убрать из него.
+func TestOpenAPIMatchesRoutes(t *testing.T) {
+	locales, err := locale.New([]string{"ru"}, "")
+	require.NoError(t, err)
+	// все необязательные маршруты включены; раздача локальных файлов изображений не входит в API
+	cfg := &config.Config{SecretKey: "secret", ReaderJWTSecret: "reader", MediaStorage: config.MediaStorageS3}
+
+	app := fiber.New()
+	Setup(app, Handlers{}, locales, cfg)
+
+	registered := map[string]bool{}
+	for _, route := range app.GetRoutes(true) {
+		// Fiber добавляет HEAD к каждому GET
+		if route.Method == http.MethodHead {
+			continue
+		}
+		registered[route.Method+" "+openapi.Path(normalizePath(route.Path))] = true
+	}
+
+	documented := map[string]bool{}
+	for path, item := range api.OpenAPI("").Paths {
+		for method := range item {
+			documented[strings.ToUpper(method)+" "+path] = true
+		}
+	}
+
+	for route := range registered {
+		assert.True(t, documented[route], "route %s is not described in api.OpenAPI", route)
+	}
+	for route := range documented {
+		assert.True(t, registered[route], "route %s is described in api.OpenAPI but not registered", route)
+	}
+}
+
+// normalizePath убирает повторные и конечные "/", которые Fiber без StrictRouting не различает
+func normalizePath(path string) string {
+	for strings.Contains(path, "//") {
+		path = strings.ReplaceAll(path, "//", "/")
+	}
+	if len(path) > 1 {
+		path = strings.TrimSuffix(path, "/")
+	}
+	return path
+}
@@ -5,11 +5,15 @@ import (
 	"go_news_server/internal/handlers"
 	"go_news_server/internal/middleware"
 	"go_news_server/pkg/config"
+	"go_news_server/pkg/sanitize"
 )
 
-func PrivateRoutes(a *fiber.App, handler *handlers.NewsHandlers, cfg *config.Config) {
+func PrivateRoutes(a *fiber.App, handler *handlers.NewsHandlers, debugHandler *handlers.DebugHandler, cfg *config.Config) {
 	route := a.Group("/private/")
 
-	route.Get("/list", middleware.KeyProtected(cfg.SecretKey), handler.GetNewsList)
-	route.Post("/edit/:Id", middleware.KeyProtected(cfg.SecretKey), handler.EditNewsHandler)
+	route.Get("/list", deprecated("/api/v1/private/news"), middleware.KeyProtected(cfg.SecretKey), handler.GetPrivateNewsList)
+	route.Post("/edit/:Id", deprecated("/api/v1/private/news/:Id"), middleware.KeyProtected(cfg.SecretKey), middleware.ContentPolicy(sanitize.Relaxed), handler.EditNewsHandler)
+
+	route.Get("/debug/queries", middleware.KeyProtected(cfg.SecretKey), debugHandler.GetQueryStats)
+	route.Delete("/debug/queries", middleware.KeyProtected(cfg.SecretKey), debugHandler.ResetQueryStats)
 }
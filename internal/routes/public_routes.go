@@ -3,10 +3,15 @@ package routes
 import (
 	"github.com/gofiber/fiber/v2"
 	"go_news_server/internal/handlers"
+	"go_news_server/internal/middleware"
+	"go_news_server/pkg/locale"
+	"go_news_server/pkg/sanitize"
 )
 
-func PublicRoutes(a *fiber.App, handler *handlers.NewsHandlers) {
+func PublicRoutes(a *fiber.App, handler *handlers.NewsHandlers, locales *locale.Locales) {
 	route := a.Group("")
-	route.Get("/list", handler.GetNewsList)
-	route.Post("/edit/:Id", handler.EditNewsHandler)
+	route.Get("/list", deprecated("/api/v1/news"), middleware.Locale(locales), handler.GetNewsList)
+	route.Get("/news/by-slug/:slug", deprecated("/api/v1/news/by-slug/:slug"), middleware.Locale(locales), handler.GetNewsBySlug)
+	route.Get("/news/:id/related", deprecated("/api/v1/news/:id/related"), middleware.Locale(locales), handler.GetRelatedNews)
+	route.Post("/edit/:Id", deprecated("/api/v1/news/:Id"), middleware.ContentPolicy(sanitize.Strict), handler.EditNewsHandler)
 }
@@ -0,0 +1,50 @@
+package routes
+
+import (
+	v1 "go_news_server/internal/api/v1"
+	"go_news_server/internal/handlers"
+	"go_news_server/pkg/config"
+	"go_news_server/pkg/locale"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Handlers - обработчики всех маршрутов приложения
+type Handlers struct {
+	News        *handlers.NewsHandlers
+	Category    *handlers.CategoryHandler
+	Tag         *handlers.TagHandler
+	Media       *handlers.MediaHandler
+	Translation *handlers.TranslationHandler
+	View        *handlers.ViewHandler
+	Comment     *handlers.CommentHandler
+	Debug       *handlers.DebugHandler
+	Stream      *handlers.StreamHandler
+	Webhook     *handlers.WebhookHandler
+	Feed        *handlers.FeedHandler
+	Sitemap     *handlers.SitemapHandler
+	Import      *handlers.NewsImportHandler
+	APIv1       *v1.Handler
+	Docs        *handlers.DocsHandler
+}
+
+// Setup настраивает все маршруты приложения. Новые маршруты добавляются здесь,
+// тогда тест описания API проверит, что они есть в api.OpenAPI.
+func Setup(app *fiber.App, h Handlers, locales *locale.Locales, cfg *config.Config) {
+	APIRoutes(app, h.APIv1, locales, cfg)
+	PublicRoutes(app, h.News, locales)
+	StreamRoutes(app, h.Stream)
+	PrivateRoutes(app, h.News, h.Debug, cfg)
+	NewsImportRoutes(app, h.Import, cfg)
+	FeedRoutes(app, h.Feed)
+	SitemapRoutes(app, h.Sitemap)
+	SetupCategoryRoutes(app, h.Category, locales)
+	TagRoutes(app, h.Tag)
+	MediaRoutes(app, h.Media, cfg)
+	TranslationRoutes(app, h.Translation, cfg)
+	ViewRoutes(app, h.View, h.News, locales, cfg)
+	CommentRoutes(app, h.Comment, cfg)
+	WebhookRoutes(app, h.Webhook, cfg)
+	DocsRoutes(app, h.Docs)
+	NotFoundRoute(app)
+}
@@ -0,0 +1,14 @@
+package routes
+
+import (
+	"go_news_server/internal/handlers"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SitemapRoutes настраивает маршруты sitemap
+func SitemapRoutes(a *fiber.App, sitemapHandler *handlers.SitemapHandler) {
+	a.Get("/sitemap.xml", sitemapHandler.GetSitemap)                              // Sitemap или индекс sitemap
+	a.Get("/sitemaps/categories-:chunk.xml", sitemapHandler.GetCategoriesSitemap) // Категории из диапазона ID
+	a.Get("/sitemaps/news-:chunk.xml", sitemapHandler.GetNewsSitemap)             // Новости из диапазона ID
+}
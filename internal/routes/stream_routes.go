@@ -0,0 +1,12 @@
+package routes
+
+import (
+	"go_news_server/internal/handlers"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// StreamRoutes настраивает маршруты потока изменений
+func StreamRoutes(a *fiber.App, streamHandler *handlers.StreamHandler) {
+	a.Get("/news/stream", streamHandler.StreamNews) // Server-Sent Events с изменениями новостей и категорий
+}
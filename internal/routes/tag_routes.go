@@ -0,0 +1,12 @@
+package routes
+
+import (
+	"go_news_server/internal/handlers"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// TagRoutes настраивает маршруты тегов
+func TagRoutes(a *fiber.App, tagHandler *handlers.TagHandler) {
+	a.Get("/tags", deprecated("/api/v1/tags"), tagHandler.GetTags) // Теги с числом новостей и подсказки по префиксу
+}
@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"go_news_server/internal/handlers"
+	"go_news_server/internal/middleware"
+	"go_news_server/pkg/config"
+	"go_news_server/pkg/sanitize"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// TranslationRoutes настраивает маршруты переводов новостей и категорий
+func TranslationRoutes(a *fiber.App, translationHandler *handlers.TranslationHandler, cfg *config.Config) {
+	news := a.Group("/private/news", middleware.KeyProtected(cfg.SecretKey))
+
+	news.Get("/:id/translations", translationHandler.GetNewsTranslations)                                                    // Все переводы новости
+	news.Put("/:id/translations/:locale", middleware.ContentPolicy(sanitize.Relaxed), translationHandler.PutNewsTranslation) // Создание или замена перевода
+	news.Delete("/:id/translations/:locale", translationHandler.DeleteNewsTranslation)                                       // Удаление перевода
+
+	categories := a.Group("/categories")
+	categories.Put("/:id/translations/:locale", translationHandler.PutCategoryTranslation)       // Создание или замена перевода категории
+	categories.Delete("/:id/translations/:locale", translationHandler.DeleteCategoryTranslation) // Удаление перевода категории
+}
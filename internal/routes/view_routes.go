@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"go_news_server/internal/handlers"
+	"go_news_server/internal/middleware"
+	"go_news_server/pkg/config"
+	"go_news_server/pkg/locale"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ViewRoutes настраивает маршруты просмотров и популярных новостей
+func ViewRoutes(a *fiber.App, viewHandler *handlers.ViewHandler, newsHandler *handlers.NewsHandlers, locales *locale.Locales, cfg *config.Config) {
+	a.Post("/news/:id/view", viewHandler.RecordView)                                                                   // Учет просмотра новости
+	a.Get("/news/popular", deprecated("/api/v1/news/popular"), middleware.Locale(locales), newsHandler.GetPopularNews) // Популярные новости за период
+
+	private := a.Group("/private/news", middleware.KeyProtected(cfg.SecretKey))
+	private.Get("/popular", deprecated("/api/v1/private/news/popular"), middleware.Locale(locales), newsHandler.GetPrivatePopularNews) // Популярные новости с числом просмотров
+	private.Get("/:id/views", viewHandler.GetNewsViews)                                                                                // Счетчики просмотров новости
+}
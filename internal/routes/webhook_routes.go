@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"go_news_server/internal/handlers"
+	"go_news_server/internal/middleware"
+	"go_news_server/pkg/config"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// WebhookRoutes настраивает маршруты управления вебхуками
+func WebhookRoutes(a *fiber.App, webhookHandler *handlers.WebhookHandler, cfg *config.Config) {
+	webhooks := a.Group("/private/webhooks", middleware.KeyProtected(cfg.SecretKey))
+
+	webhooks.Post("/", webhookHandler.CreateWebhook)                     // Создание подписки
+	webhooks.Get("/", webhookHandler.GetAllWebhooks)                     // Получение всех подписок
+	webhooks.Post("/deliveries/:id/retry", webhookHandler.RetryDelivery) // Повтор доставки из dead letter
+	webhooks.Get("/:id", webhookHandler.GetWebhookByID)                  // Получение подписки по ID
+	webhooks.Put("/:id", webhookHandler.UpdateWebhook)                   // Обновление подписки
+	webhooks.Delete("/:id", webhookHandler.DeleteWebhook)                // Удаление подписки
+	webhooks.Get("/:id/deliveries", webhookHandler.GetDeliveries)        // Журнал доставок
+}
@@ -5,14 +5,27 @@ import (
 	"go.uber.org/zap"
 	"go_news_server/pkg/config"
 	"log"
+	"net"
 	"os"
 	"os/signal"
 
 	"github.com/gofiber/fiber/v2"
 )
 
-// StartServerWithGracefulShutdown function for starting server with a graceful shutdown.
-func StartServerWithGracefulShutdown(a *fiber.App, logger *zap.SugaredLogger, cfg *config.Config) {
+// Listen func for binding server address from config.
+// Call it before starting server in a goroutine, so bind errors are returned to the caller.
+func Listen(cfg *config.Config) (net.Listener, error) {
+	fiberConnURL := fmt.Sprintf(
+		"%v:%v",
+		cfg.ServeHost,
+		cfg.ServerPort,
+	)
+
+	return net.Listen("tcp", fiberConnURL)
+}
+
+// StartServerWithGracefulShutdown function for starting server on listener ln with a graceful shutdown.
+func StartServerWithGracefulShutdown(a *fiber.App, ln net.Listener, logger *zap.SugaredLogger) {
 	// Create channel for idle connections.
 	idleConnsClosed := make(chan struct{})
 
@@ -30,30 +43,18 @@ func StartServerWithGracefulShutdown(a *fiber.App, logger *zap.SugaredLogger, cf
 		close(idleConnsClosed)
 	}()
 
-	fiberConnURL := fmt.Sprintf(
-		"%v:%v",
-		cfg.ServeHost,
-		cfg.ServerPort,
-	)
-
 	// Run server.
-	if err := a.Listen(fiberConnURL); err != nil {
+	if err := a.Listener(ln); err != nil {
 		log.Printf("Oops... Server is not running! Reason: %v", err)
 	}
 
 	<-idleConnsClosed
 }
 
-// StartServer func for starting a simple server.
-func StartServer(a *fiber.App, logger *zap.SugaredLogger, cfg *config.Config) {
-	fiberConnURL := fmt.Sprintf(
-		"%v:%v",
-		cfg.ServeHost,
-		cfg.ServerPort,
-	)
-
+// StartServer func for starting a simple server on listener ln.
+func StartServer(a *fiber.App, ln net.Listener, logger *zap.SugaredLogger) {
 	// Run server.
-	if err := a.Listen(fiberConnURL); err != nil {
+	if err := a.Listener(ln); err != nil {
 		logger.Errorw("Oops... Server is not running! Reason: %v", err)
 	}
 }
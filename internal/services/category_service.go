@@ -5,15 +5,36 @@ import (
 	"database/sql"
 	"go_news_server/internal/models"
 	"go_news_server/internal/repository"
+	"go_news_server/pkg/validate"
 )
 
+// CategoryServiceInterface - интерфейс для CategoryService
+type CategoryServiceInterface interface {
+	CreateCategory(ctx context.Context, req *models.CategoryCreateRequest) (*models.Category, error)
+	GetCategoryByID(ctx context.Context, id int64) (*models.Category, error)
+	GetCategoryBySlug(ctx context.Context, slug string) (*models.Category, string, error)
+	GetAllCategories(ctx context.Context, limit, offset int) ([]models.Category, int64, error)
+	UpdateCategory(ctx context.Context, id int64, req *models.CategoryUpdateRequest) (*models.Category, error)
+	DeleteCategory(ctx context.Context, id int64) error
+	GetCategoriesByNewsID(ctx context.Context, newsID int64) ([]models.Category, error)
+	GetCategoryTree(ctx context.Context) ([]*models.CategoryNode, error)
+	GetCategoryDescendants(ctx context.Context, id int64) ([]models.Category, error)
+	MoveCategory(ctx context.Context, id int64, req *models.CategoryMoveRequest) (*models.Category, error)
+}
+
 type CategoryService struct {
 	categoryRepo *repository.CategoryRepository
+	// translations переводит категории на язык из контекста, nil - без переводов
+	translations *TranslationService
 }
 
-func NewCategoryService(categoryRepo *repository.CategoryRepository) *CategoryService {
+// Проверка, что CategoryService реализует интерфейс
+var _ CategoryServiceInterface = (*CategoryService)(nil)
+
+func NewCategoryService(categoryRepo *repository.CategoryRepository, translations *TranslationService) *CategoryService {
 	return &CategoryService{
 		categoryRepo: categoryRepo,
+		translations: translations,
 	}
 }
 
@@ -26,12 +47,23 @@ func (s *CategoryService) CreateCategory(ctx context.Context, req *models.Catego
 	}
 
 	if existingCategory != nil {
-		return nil, &models.ValidationError{Message: "Category with this name already exists"}
+		return nil, &models.ConflictError{Message: "Category with this name already exists"}
 	}
 
 	category := &models.Category{
 		Name:        req.Name,
 		Description: req.Description,
+		ParentId:    normalizeParentID(req.ParentId),
+	}
+
+	if category.ParentId != nil {
+		parent, err := s.categoryRepo.GetCategoryByID(ctx, *category.ParentId)
+		if err != nil {
+			return nil, err
+		}
+		if parent == nil {
+			return nil, models.NewFieldError("parent_id", models.FieldCodeNotFound, "Parent category not found")
+		}
 	}
 
 	err = s.categoryRepo.CreateCategory(ctx, category)
@@ -53,12 +85,46 @@ func (s *CategoryService) GetCategoryByID(ctx context.Context, id int64) (*model
 		return nil, &models.NotFoundError{Message: "Category not found"}
 	}
 
+	if err := s.translate(ctx, category); err != nil {
+		return nil, err
+	}
 	return category, nil
 }
 
+// GetCategoryBySlug получает категорию по slug. Если slug устарел, категория не возвращается,
+// а вторым значением возвращается ее текущий slug для перенаправления.
+func (s *CategoryService) GetCategoryBySlug(ctx context.Context, slug string) (*models.Category, string, error) {
+	category, err := s.categoryRepo.GetCategoryBySlug(ctx, slug)
+	if err != nil {
+		return nil, "", err
+	}
+	if category != nil {
+		if err := s.translate(ctx, category); err != nil {
+			return nil, "", err
+		}
+		return category, "", nil
+	}
+
+	current, err := s.categoryRepo.GetCategorySlugRedirect(ctx, slug)
+	if err != nil {
+		return nil, "", err
+	}
+	if current == "" {
+		return nil, "", &models.NotFoundError{Message: "Category not found"}
+	}
+	return nil, current, nil
+}
+
 // GetAllCategories получает все категории с пагинацией
 func (s *CategoryService) GetAllCategories(ctx context.Context, limit, offset int) ([]models.Category, int64, error) {
-	return s.categoryRepo.GetAllCategories(ctx, limit, offset)
+	categories, total, err := s.categoryRepo.GetAllCategories(ctx, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := s.translateList(ctx, categories); err != nil {
+		return nil, 0, err
+	}
+	return categories, total, nil
 }
 
 // UpdateCategory обновляет категорию
@@ -81,7 +147,7 @@ func (s *CategoryService) UpdateCategory(ctx context.Context, id int64, req *mod
 		}
 
 		if categoryWithSameName != nil {
-			return nil, &models.ValidationError{Message: "Category with this name already exists"}
+			return nil, &models.ConflictError{Message: "Category with this name already exists"}
 		}
 	}
 
@@ -89,7 +155,16 @@ func (s *CategoryService) UpdateCategory(ctx context.Context, id int64, req *mod
 	existingCategory.Name = req.Name
 	existingCategory.Description = req.Description
 
-	err = s.categoryRepo.UpdateCategory(ctx, existingCategory)
+	err = s.categoryRepo.InTransaction(ctx, func(ctx context.Context) error {
+		if req.ParentId != nil {
+			parentID := normalizeParentID(req.ParentId)
+			if err := s.checkParent(ctx, id, parentID); err != nil {
+				return err
+			}
+			existingCategory.ParentId = parentID
+		}
+		return s.categoryRepo.UpdateCategory(ctx, existingCategory)
+	})
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, &models.NotFoundError{Message: "Category not found"}
@@ -125,5 +200,147 @@ func (s *CategoryService) DeleteCategory(ctx context.Context, id int64) error {
 
 // GetCategoriesByNewsID получает категории для конкретной новости
 func (s *CategoryService) GetCategoriesByNewsID(ctx context.Context, newsID int64) ([]models.Category, error) {
-	return s.categoryRepo.GetCategoriesByNewsID(ctx, newsID)
+	categories, err := s.categoryRepo.GetCategoriesByNewsID(ctx, newsID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.translateList(ctx, categories); err != nil {
+		return nil, err
+	}
+	return categories, nil
+}
+
+// GetCategoryTree получает дерево всех категорий
+func (s *CategoryService) GetCategoryTree(ctx context.Context) ([]*models.CategoryNode, error) {
+	tree, err := s.categoryRepo.GetCategoryTree(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var categories []*models.Category
+	var collect func(nodes []*models.CategoryNode)
+	collect = func(nodes []*models.CategoryNode) {
+		for _, node := range nodes {
+			categories = append(categories, &node.Category)
+			collect(node.Children)
+		}
+	}
+	collect(tree)
+
+	if err := s.translate(ctx, categories...); err != nil {
+		return nil, err
+	}
+	return tree, nil
+}
+
+// GetCategoryDescendants получает всех потомков категории в порядке обхода дерева
+func (s *CategoryService) GetCategoryDescendants(ctx context.Context, id int64) ([]models.Category, error) {
+	category, err := s.categoryRepo.GetCategoryByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if category == nil {
+		return nil, &models.NotFoundError{Message: "Category not found"}
+	}
+
+	descendants, err := s.categoryRepo.GetCategoryDescendants(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.translateList(ctx, descendants); err != nil {
+		return nil, err
+	}
+	return descendants, nil
+}
+
+// MoveCategory переносит категорию к другому родителю и/или на другую позицию среди дочерних категорий
+func (s *CategoryService) MoveCategory(ctx context.Context, id int64, req *models.CategoryMoveRequest) (*models.Category, error) {
+	position := -1
+	if req.Position != nil {
+		if *req.Position < 0 {
+			return nil, models.NewFieldError("position", validate.CodeMin, "Position must not be negative")
+		}
+		position = *req.Position
+	}
+
+	var category *models.Category
+	err := s.categoryRepo.InTransaction(ctx, func(ctx context.Context) error {
+		existing, err := s.categoryRepo.GetCategoryByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			return &models.NotFoundError{Message: "Category not found"}
+		}
+
+		parentID := normalizeParentID(req.ParentId)
+		if err := s.checkParent(ctx, id, parentID); err != nil {
+			return err
+		}
+
+		category, err = s.categoryRepo.MoveCategory(ctx, id, parentID, position)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return category, nil
+}
+
+// checkParent проверяет, что категорию id можно перенести к parentID: родитель существует
+// и не является самой категорией или ее потомком. Блокирует дерево до конца транзакции,
+// чтобы параллельный перенос не создал цикл после проверки.
+func (s *CategoryService) checkParent(ctx context.Context, id int64, parentID *int64) error {
+	if parentID == nil {
+		return nil
+	}
+	if *parentID == id {
+		return models.NewFieldError("parent_id", models.FieldCodeInvalid, "Category cannot be its own parent")
+	}
+
+	if err := s.categoryRepo.LockCategoryTree(ctx); err != nil {
+		return err
+	}
+
+	parent, err := s.categoryRepo.GetCategoryByID(ctx, *parentID)
+	if err != nil {
+		return err
+	}
+	if parent == nil {
+		return models.NewFieldError("parent_id", models.FieldCodeNotFound, "Parent category not found")
+	}
+
+	cycle, err := s.categoryRepo.IsCategoryInSubtree(ctx, *parentID, id)
+	if err != nil {
+		return err
+	}
+	if cycle {
+		return models.NewFieldError("parent_id", models.FieldCodeInvalid, "Category cannot be moved into its own descendant")
+	}
+	return nil
+}
+
+// translate переводит категории на язык из контекста
+func (s *CategoryService) translate(ctx context.Context, categories ...*models.Category) error {
+	if s.translations == nil || len(categories) == 0 {
+		return nil
+	}
+	return s.translations.TranslateCategories(ctx, categories)
+}
+
+func (s *CategoryService) translateList(ctx context.Context, categories []models.Category) error {
+	pointers := make([]*models.Category, len(categories))
+	for i := range categories {
+		pointers[i] = &categories[i]
+	}
+	return s.translate(ctx, pointers...)
+}
+
+// normalizeParentID приводит родителя 0 к nil - корню дерева
+func normalizeParentID(parentID *int64) *int64 {
+	if parentID == nil || *parentID == 0 {
+		return nil
+	}
+	return parentID
 }
@@ -0,0 +1,280 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"go_news_server/internal/models"
+	"go_news_server/internal/repository"
+	"go_news_server/pkg/textfilter"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// MaxCommentsLimit - наибольшее число комментариев (веток) на странице
+const MaxCommentsLimit = 100
+
+// CommentConfig - параметры комментариев
+type CommentConfig struct {
+	// Premoderation - все комментарии ждут модерации; иначе на модерацию попадают только задержанные фильтром
+	Premoderation bool
+	// RateLimit комментариев одного читателя за RateWindow
+	RateLimit  int
+	RateWindow time.Duration
+	// MaxLength - наибольшая длина комментария в символах
+	MaxLength int
+}
+
+// CommentService принимает комментарии читателей, проверяет их фильтром и частотой
+// и отдает одобренные комментарии ветками
+type CommentService struct {
+	repo   *repository.CommentRepository
+	filter *textfilter.Filter
+	config CommentConfig
+}
+
+func NewCommentService(repo *repository.CommentRepository, filter *textfilter.Filter, config CommentConfig) *CommentService {
+	if config.RateLimit <= 0 {
+		config.RateLimit = 5
+	}
+	if config.RateWindow <= 0 {
+		config.RateWindow = time.Minute
+	}
+	if config.MaxLength <= 0 {
+		config.MaxLength = 2000
+	}
+
+	return &CommentService{
+		repo:   repo,
+		filter: filter,
+		config: config,
+	}
+}
+
+// CreateComment создает комментарий читателя reader к новости newsID. Ответ возможен только
+// на одобренный комментарий той же новости. Комментарий сразу одобряется, если премодерация выключена
+// и фильтр его не задержал.
+func (s *CommentService) CreateComment(ctx context.Context, newsID int64, reader models.Reader, req *models.CommentRequest) (*models.Comment, error) {
+	content := strings.TrimSpace(req.Content)
+	if content == "" {
+		return nil, &models.ValidationError{Message: "Comment content is required"}
+	}
+	if utf8.RuneCountInString(content) > s.config.MaxLength {
+		return nil, &models.ValidationError{Message: fmt.Sprintf("Comment must be at most %d characters", s.config.MaxLength)}
+	}
+
+	comment := &models.Comment{
+		NewsId:   newsID,
+		ParentId: req.ParentId,
+		UserId:   reader.Id,
+		UserName: reader.Name,
+		Content:  content,
+		Status:   models.CommentStatusApproved,
+	}
+	if s.filter != nil {
+		comment.FilterReason = s.filter.Check(content)
+	}
+	if s.config.Premoderation || comment.FilterReason != "" {
+		comment.Status = models.CommentStatusPending
+	}
+
+	err := s.repo.InTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.LockCommentAuthor(ctx, reader.Id); err != nil {
+			return err
+		}
+
+		banned, err := s.repo.IsUserBanned(ctx, reader.Id)
+		if err != nil {
+			return err
+		}
+		if banned {
+			return &models.ForbiddenError{Message: "You are not allowed to comment"}
+		}
+
+		count, retryAfter, err := s.repo.CountRecentComments(ctx, reader.Id, s.config.RateWindow)
+		if err != nil {
+			return err
+		}
+		if count >= s.config.RateLimit {
+			return &models.RateLimitError{Message: "Too many comments, try again later", RetryAfter: retryAfter}
+		}
+
+		if req.ParentId != nil {
+			parent, err := s.repo.GetComment(ctx, *req.ParentId)
+			if err != nil {
+				return err
+			}
+			if parent == nil || parent.NewsId != newsID || parent.Status != models.CommentStatusApproved {
+				return &models.ValidationError{Message: "Parent comment not found"}
+			}
+		}
+
+		return s.repo.CreateComment(ctx, comment)
+	})
+	if err == sql.ErrNoRows {
+		return nil, &models.NotFoundError{Message: "News not found"}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+// GetComments получает страницу одобренных веток комментариев новости, от новых к старым.
+// cursor - значение next_cursor предыдущей страницы, пустой - первая страница.
+func (s *CommentService) GetComments(ctx context.Context, newsID int64, cursor string, limit int) ([]*models.Comment, string, error) {
+	before, err := parseCommentCursor(cursor)
+	if err != nil {
+		return nil, "", err
+	}
+	if limit <= 0 || limit > MaxCommentsLimit {
+		limit = MaxCommentsLimit
+	}
+
+	// лишняя ветка показывает, есть ли следующая страница
+	comments, err := s.repo.GetCommentThreads(ctx, newsID, before, limit+1)
+	if err != nil {
+		return nil, "", err
+	}
+
+	threads, next := buildCommentThreads(comments, limit)
+	return threads, formatCommentCursor(next), nil
+}
+
+// buildCommentThreads собирает ветки из комментариев, отсортированных по ID: ответ всегда новее комментария,
+// на который он дан. Ветки возвращаются от новых к старым, ответы - от старых к новым. Если веток больше limit,
+// самая старая отбрасывается, а вторым значением возвращается ID самой старой оставшейся для следующей страницы.
+func buildCommentThreads(comments []models.Comment, limit int) ([]*models.Comment, int64) {
+	var roots []*models.Comment
+	for i := range comments {
+		if comments[i].ParentId == nil {
+			roots = append(roots, &comments[i])
+		}
+	}
+
+	var next, dropped int64
+	if len(roots) > limit {
+		// ветки идут по возрастанию ID, лишняя - первая
+		dropped = roots[0].Id
+		roots = roots[1:]
+		next = roots[0].Id
+	}
+
+	byID := make(map[int64]*models.Comment, len(comments))
+	for i := range comments {
+		comment := &comments[i]
+		if comment.ParentId == nil {
+			if comment.Id != dropped {
+				byID[comment.Id] = comment
+			}
+			continue
+		}
+		if parent, ok := byID[*comment.ParentId]; ok {
+			parent.Replies = append(parent.Replies, comment)
+			byID[comment.Id] = comment
+		}
+	}
+
+	threads := make([]*models.Comment, len(roots))
+	for i, root := range roots {
+		threads[len(roots)-1-i] = root
+	}
+	return threads, next
+}
+
+// GetModerationQueue получает комментарии со статусом status (по умолчанию ожидающие модерации), от старых к новым
+func (s *CommentService) GetModerationQueue(ctx context.Context, status, cursor string, limit int) ([]*models.Comment, string, error) {
+	if status == "" {
+		status = models.CommentStatusPending
+	}
+	switch status {
+	case models.CommentStatusPending, models.CommentStatusApproved, models.CommentStatusRejected:
+	default:
+		return nil, "", &models.ValidationError{Message: "Unsupported comment status: " + status}
+	}
+
+	after, err := parseCommentCursor(cursor)
+	if err != nil {
+		return nil, "", err
+	}
+	if limit <= 0 || limit > MaxCommentsLimit {
+		limit = MaxCommentsLimit
+	}
+
+	comments, err := s.repo.GetCommentsByStatus(ctx, status, after, limit)
+	if err != nil {
+		return nil, "", err
+	}
+
+	result := make([]*models.Comment, len(comments))
+	for i := range comments {
+		result[i] = &comments[i]
+	}
+	var next int64
+	if len(comments) == limit {
+		next = comments[len(comments)-1].Id
+	}
+	return result, formatCommentCursor(next), nil
+}
+
+// Approve одобряет комментарий
+func (s *CommentService) Approve(ctx context.Context, id int64) (*models.Comment, error) {
+	return s.setStatus(ctx, id, models.CommentStatusApproved)
+}
+
+// Reject отклоняет комментарий, ответы на него перестают показываться вместе с ним
+func (s *CommentService) Reject(ctx context.Context, id int64) (*models.Comment, error) {
+	return s.setStatus(ctx, id, models.CommentStatusRejected)
+}
+
+func (s *CommentService) setStatus(ctx context.Context, id int64, status string) (*models.Comment, error) {
+	comment, err := s.repo.SetCommentStatus(ctx, id, status)
+	if err != nil {
+		return nil, err
+	}
+	if comment == nil {
+		return nil, &models.NotFoundError{Message: "Comment not found"}
+	}
+	return comment, nil
+}
+
+// Ban запрещает комментировать автору комментария id и отклоняет его комментарии, ожидающие модерации
+func (s *CommentService) Ban(ctx context.Context, id int64, reason string) (*models.CommentBanResponse, error) {
+	userID, rejected, err := s.repo.BanCommentAuthor(ctx, id, strings.TrimSpace(reason))
+	if err == sql.ErrNoRows {
+		return nil, &models.NotFoundError{Message: "Comment not found"}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &models.CommentBanResponse{Success: true, UserId: userID, Rejected: rejected}, nil
+}
+
+// Unban снимает запрет комментировать
+func (s *CommentService) Unban(ctx context.Context, userID string) error {
+	err := s.repo.DeleteCommentBan(ctx, userID)
+	if err == sql.ErrNoRows {
+		return &models.NotFoundError{Message: "Ban not found"}
+	}
+	return err
+}
+
+func parseCommentCursor(cursor string) (int64, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+	id, err := strconv.ParseInt(cursor, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, &models.ValidationError{Message: "Invalid cursor"}
+	}
+	return id, nil
+}
+
+func formatCommentCursor(id int64) string {
+	if id == 0 {
+		return ""
+	}
+	return strconv.FormatInt(id, 10)
+}
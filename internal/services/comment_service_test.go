@@ -0,0 +1,39 @@
+package services
+
+import (
+	"go_news_server/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildCommentThreads(t *testing.T) {
+	parent := func(id int64) *int64 { return &id }
+	comments := []models.Comment{
+		{Id: 1},
+		{Id: 2, ParentId: parent(1)},
+		{Id: 3},
+		{Id: 4, ParentId: parent(3)},
+		{Id: 5, ParentId: parent(4)},
+		{Id: 6},
+		{Id: 7, ParentId: parent(3)},
+	}
+
+	threads, next := buildCommentThreads(comments, 2)
+	assert.Equal(t, int64(3), next)
+	assert.Len(t, threads, 2)
+	assert.Equal(t, int64(6), threads[0].Id)
+	assert.Equal(t, int64(3), threads[1].Id)
+
+	// ответы по времени создания, ответы на отброшенную ветку не попадают в страницу
+	replies := threads[1].Replies
+	assert.Len(t, replies, 2)
+	assert.Equal(t, int64(4), replies[0].Id)
+	assert.Equal(t, int64(7), replies[1].Id)
+	assert.Equal(t, int64(5), replies[0].Replies[0].Id)
+
+	threads, next = buildCommentThreads(comments, 3)
+	assert.Zero(t, next)
+	assert.Len(t, threads, 3)
+	assert.Equal(t, int64(2), threads[2].Replies[0].Id)
+}
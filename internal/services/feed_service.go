@@ -0,0 +1,260 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"go_news_server/internal/models"
+	"go_news_server/internal/repository"
+	"strconv"
+	"time"
+)
+
+// Content-Type лент
+const (
+	RSSContentType  = "application/rss+xml; charset=utf-8"
+	AtomContentType = "application/atom+xml; charset=utf-8"
+)
+
+// FeedConfig настройки лент
+type FeedConfig struct {
+	// BaseURL - адрес публичного сайта без завершающего "/", используется в ссылках и идентификаторах
+	BaseURL string
+	// Title - название ленты
+	Title string
+	// Items - количество новостей в ленте
+	Items int
+}
+
+// Feed сформированная лента
+type Feed struct {
+	Body        []byte
+	ContentType string
+	// LastModified - время последнего изменения входящих в ленту данных, нулевое для пустой ленты
+	LastModified time.Time
+}
+
+type FeedService struct {
+	newsRepo     *repository.NewsRepository
+	categoryRepo *repository.CategoryRepository
+	config       FeedConfig
+}
+
+func NewFeedService(newsRepo *repository.NewsRepository, categoryRepo *repository.CategoryRepository, config FeedConfig) *FeedService {
+	if config.Items <= 0 {
+		config.Items = 20
+	}
+	if config.Title == "" {
+		config.Title = "News"
+	}
+
+	return &FeedService{
+		newsRepo:     newsRepo,
+		categoryRepo: categoryRepo,
+		config:       config,
+	}
+}
+
+// feedSource данные для ленты: все новости или новости одной категории
+type feedSource struct {
+	title        string
+	description  string
+	news         []models.News
+	lastModified time.Time
+}
+
+func (s *FeedService) load(ctx context.Context, categoryID int64) (*feedSource, error) {
+	src := &feedSource{
+		title:       s.config.Title,
+		description: "Latest news",
+	}
+
+	if categoryID != 0 {
+		category, err := s.categoryRepo.GetCategoryByID(ctx, categoryID)
+		if err != nil {
+			return nil, err
+		}
+		if category == nil {
+			return nil, &models.NotFoundError{Message: "Category not found"}
+		}
+
+		src.title = s.config.Title + ": " + category.Name
+		src.description = category.Description
+		src.lastModified = category.UpdatedAt
+	}
+
+	news, err := s.newsRepo.GetLatestNews(ctx, categoryID, s.config.Items)
+	if err != nil {
+		return nil, err
+	}
+	src.news = news
+
+	for _, n := range news {
+		if n.UpdatedAt.After(src.lastModified) {
+			src.lastModified = n.UpdatedAt
+		}
+	}
+	return src, nil
+}
+
+// newsURL возвращает адрес новости на сайте. Он же служит постоянным идентификатором записи в ленте.
+func (s *FeedService) newsURL(id int64) string {
+	return s.config.BaseURL + "/news/" + strconv.FormatInt(id, 10)
+}
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	AtomNS  string     `xml:"xmlns:atom,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	AtomLink      atomLink  `xml:"atom:link"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	Description string  `xml:"description"`
+	GUID        rssGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Author  atomAuthor  `xml:"author"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomEntry struct {
+	Title     string      `xml:"title"`
+	ID        string      `xml:"id"`
+	Link      atomLink    `xml:"link"`
+	Published string      `xml:"published"`
+	Updated   string      `xml:"updated"`
+	Summary   atomContent `xml:"summary"`
+	Content   atomContent `xml:"content"`
+}
+
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+// RSS формирует ленту RSS 2.0. selfPath - путь ленты на сервере для ссылки rel="self".
+// В description передается текст новости без разметки.
+func (s *FeedService) RSS(ctx context.Context, categoryID int64, selfPath string) (*Feed, error) {
+	src, err := s.load(ctx, categoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	channel := rssChannel{
+		Title:       src.title,
+		Link:        s.config.BaseURL + "/",
+		Description: src.description,
+		AtomLink:    atomLink{Href: s.config.BaseURL + selfPath, Rel: "self", Type: "application/rss+xml"},
+	}
+	if !src.lastModified.IsZero() {
+		channel.LastBuildDate = src.lastModified.UTC().Format(time.RFC1123Z)
+	}
+
+	for _, n := range src.news {
+		link := s.newsURL(n.Id)
+		channel.Items = append(channel.Items, rssItem{
+			Title:       n.Title,
+			Link:        link,
+			Description: n.ContentText,
+			GUID:        rssGUID{IsPermaLink: false, Value: link},
+			PubDate:     n.CreatedAt.UTC().Format(time.RFC1123Z),
+		})
+	}
+
+	body, err := marshalFeed(rssFeed{Version: "2.0", AtomNS: "http://www.w3.org/2005/Atom", Channel: channel})
+	if err != nil {
+		return nil, err
+	}
+	return &Feed{Body: body, ContentType: RSSContentType, LastModified: src.lastModified}, nil
+}
+
+// Atom формирует ленту Atom 1.0. selfPath - путь ленты на сервере, он же идентификатор ленты.
+func (s *FeedService) Atom(ctx context.Context, categoryID int64, selfPath string) (*Feed, error) {
+	src, err := s.load(ctx, categoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	// updated в Atom обязателен: у пустой ленты всех новостей нет времени изменения, берется начало эпохи Unix.
+	// Значение постоянно, чтобы тело и ETag пустой ленты не менялись и условный запрос получал 304.
+	updated := src.lastModified
+	if updated.IsZero() {
+		updated = time.Unix(0, 0)
+	}
+
+	feed := atomFeed{
+		Title:   src.title,
+		ID:      s.config.BaseURL + selfPath,
+		Updated: updated.UTC().Format(time.RFC3339),
+		Author:  atomAuthor{Name: s.config.Title},
+		Links: []atomLink{
+			{Href: s.config.BaseURL + selfPath, Rel: "self", Type: "application/atom+xml"},
+			{Href: s.config.BaseURL + "/", Rel: "alternate", Type: "text/html"},
+		},
+	}
+
+	for _, n := range src.news {
+		link := s.newsURL(n.Id)
+		feed.Entries = append(feed.Entries, atomEntry{
+			Title:     n.Title,
+			ID:        link,
+			Link:      atomLink{Href: link, Rel: "alternate", Type: "text/html"},
+			Published: n.CreatedAt.UTC().Format(time.RFC3339),
+			Updated:   n.UpdatedAt.UTC().Format(time.RFC3339),
+			Summary:   atomContent{Type: "text", Body: n.ContentText},
+			Content:   atomContent{Type: "html", Body: n.Content},
+		})
+	}
+
+	body, err := marshalFeed(feed)
+	if err != nil {
+		return nil, err
+	}
+	return &Feed{Body: body, ContentType: AtomContentType, LastModified: src.lastModified}, nil
+}
+
+func marshalFeed(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+
+	enc := xml.NewEncoder(&buf)
+	enc.Indent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,193 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"go_news_server/internal/models"
+	"go_news_server/internal/repository"
+	"go_news_server/pkg/media"
+	"go_news_server/pkg/storage"
+
+	"go.uber.org/zap"
+)
+
+// ErrMediaTooLarge - размер файла больше MediaConfig.MaxSize
+var ErrMediaTooLarge = errors.New("media file is too large")
+
+// MediaConfig - параметры загрузки изображений
+type MediaConfig struct {
+	// MaxSize - наибольший размер файла в байтах
+	MaxSize int64
+	// ThumbnailSize - наибольшая сторона миниатюры в пикселях
+	ThumbnailSize int
+}
+
+type MediaService struct {
+	repo    *repository.MediaRepository
+	storage storage.Storage
+	config  MediaConfig
+	logger  *zap.SugaredLogger
+}
+
+func NewMediaService(repo *repository.MediaRepository, store storage.Storage, config MediaConfig, logger *zap.SugaredLogger) *MediaService {
+	if config.MaxSize <= 0 {
+		config.MaxSize = 10 << 20
+	}
+	if config.ThumbnailSize <= 0 {
+		config.ThumbnailSize = 320
+	}
+
+	return &MediaService{
+		repo:    repo,
+		storage: store,
+		config:  config,
+		logger:  logger,
+	}
+}
+
+// MaxSize возвращает наибольший размер загружаемого файла в байтах
+func (s *MediaService) MaxSize() int64 {
+	return s.config.MaxSize
+}
+
+// Upload проверяет изображение по содержимому, сохраняет его и миниатюру в хранилище и добавляет к новости.
+// Возвращает ErrMediaTooLarge, media.ErrUnsupportedType, ValidationError или NotFoundError, если новости нет.
+func (s *MediaService) Upload(ctx context.Context, newsID int64, data []byte, alt string, cover bool) (*models.NewsMedia, error) {
+	if int64(len(data)) > s.config.MaxSize {
+		return nil, ErrMediaTooLarge
+	}
+
+	info, err := media.Inspect(data)
+	if errors.Is(err, media.ErrTooLarge) {
+		return nil, &models.ValidationError{Message: fmt.Sprintf("Image must be at most %d pixels", media.MaxPixels)}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	thumbnail, thumbInfo, err := media.Thumbnail(data, info, s.config.ThumbnailSize)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := randomName()
+	if err != nil {
+		return nil, err
+	}
+	item := &models.NewsMedia{
+		NewsId:          newsID,
+		StorageKey:      fmt.Sprintf("news/%d/%s%s", newsID, name, info.Extension),
+		ThumbnailKey:    fmt.Sprintf("news/%d/%s_thumb%s", newsID, name, thumbInfo.Extension),
+		ContentType:     info.ContentType,
+		Size:            int64(len(data)),
+		Width:           info.Width,
+		Height:          info.Height,
+		ThumbnailWidth:  thumbInfo.Width,
+		ThumbnailHeight: thumbInfo.Height,
+		Alt:             alt,
+		IsCover:         cover,
+	}
+
+	if err := s.storage.Put(ctx, item.StorageKey, info.ContentType, data); err != nil {
+		return nil, err
+	}
+	if err := s.storage.Put(ctx, item.ThumbnailKey, thumbInfo.ContentType, thumbnail); err != nil {
+		s.deleteFiles(item)
+		return nil, err
+	}
+
+	if err := s.repo.CreateMedia(ctx, item); err != nil {
+		s.deleteFiles(item)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, &models.NotFoundError{Message: "News not found"}
+		}
+		return nil, err
+	}
+
+	s.setURLs(item)
+	return item, nil
+}
+
+// Update меняет подпись, позицию и признак обложки изображения новости
+func (s *MediaService) Update(ctx context.Context, newsID, id int64, req *models.NewsMediaUpdateRequest) (*models.NewsMedia, error) {
+	if req.Position != nil && *req.Position < 0 {
+		return nil, &models.ValidationError{Message: "Position must not be negative"}
+	}
+
+	item, err := s.repo.UpdateMedia(ctx, newsID, id, req)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, &models.NotFoundError{Message: "Media not found"}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.setURLs(item)
+	return item, nil
+}
+
+// Delete удаляет изображение новости. Файлы удаляются из хранилища после записи в базу,
+// ошибка их удаления только логируется: запись уже удалена, а лишний файл ничему не мешает.
+func (s *MediaService) Delete(ctx context.Context, newsID, id int64) error {
+	item, err := s.repo.DeleteMedia(ctx, newsID, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return &models.NotFoundError{Message: "Media not found"}
+	}
+	if err != nil {
+		return err
+	}
+
+	s.deleteFiles(item)
+	return nil
+}
+
+// AttachMedia добавляет к новостям их изображения
+func (s *MediaService) AttachMedia(ctx context.Context, newsList []models.News) error {
+	ids := make([]int64, len(newsList))
+	for i := range newsList {
+		ids[i] = newsList[i].Id
+	}
+
+	byNews, err := s.repo.GetMediaByNewsIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	for i := range newsList {
+		items := byNews[newsList[i].Id]
+		for j := range items {
+			s.setURLs(&items[j])
+		}
+		newsList[i].Media = items
+	}
+	return nil
+}
+
+func (s *MediaService) setURLs(item *models.NewsMedia) {
+	item.URL = s.storage.URL(item.StorageKey)
+	item.ThumbnailURL = s.storage.URL(item.ThumbnailKey)
+}
+
+// deleteFiles удаляет файлы изображения из хранилища. Вызывается и после отмены запроса,
+// поэтому не использует его контекст.
+func (s *MediaService) deleteFiles(item *models.NewsMedia) {
+	for _, key := range []string{item.StorageKey, item.ThumbnailKey} {
+		if err := s.storage.Delete(context.Background(), key); err != nil {
+			s.logger.Errorw("Failed to delete media file", "key", key, "error", err)
+		}
+	}
+}
+
+// randomName возвращает случайное имя файла: по нему нельзя перебрать чужие файлы,
+// а повторная загрузка не перезаписывает прежнюю
+func randomName() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
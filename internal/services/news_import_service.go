@@ -0,0 +1,599 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go_news_server/internal/models"
+	"go_news_server/internal/repository"
+	"go_news_server/pkg/sanitize"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultImportBatchSize - количество строк в одной транзакции по умолчанию
+	defaultImportBatchSize = 500
+	// maxImportErrors ограничивает количество ошибок в ответе, счетчик Failed при этом продолжает расти
+	maxImportErrors = 1000
+	// csvCategorySeparator разделяет категории в колонке categories CSV
+	csvCategorySeparator = ';'
+	// csvCategoryEscape экранирует следующий символ в колонке categories CSV
+	csvCategoryEscape = '\\'
+)
+
+// errImportDryRun откатывает транзакцию пакета в режиме dry-run
+var errImportDryRun = errors.New("dry run")
+
+// csvExportHeader колонки CSV экспорта; импорт принимает те же колонки, updated_at при импорте игнорируется
+var csvExportHeader = []string{"id", "title", "content", "content_format", "categories", "created_at", "updated_at"}
+
+// IsNewsFormat проверяет, что формат поддерживается импортом и экспортом
+func IsNewsFormat(format string) bool {
+	return format == models.FormatNDJSON || format == models.FormatCSV
+}
+
+// NewsImportService импортирует и экспортирует новости. Импорт выполняют редакторы,
+// поэтому Content очищается политикой sanitize.Relaxed.
+type NewsImportService struct {
+	newsRepo     *repository.NewsRepository
+	categoryRepo *repository.CategoryRepository
+	sanitizer    *sanitize.Sanitizer
+	logger       *zap.SugaredLogger
+}
+
+func NewNewsImportService(newsRepo *repository.NewsRepository, categoryRepo *repository.CategoryRepository, sanitizer *sanitize.Sanitizer, logger *zap.SugaredLogger) *NewsImportService {
+	return &NewsImportService{
+		newsRepo:     newsRepo,
+		categoryRepo: categoryRepo,
+		sanitizer:    sanitizer,
+		logger:       logger,
+	}
+}
+
+// Import импортирует новости из r. Строки выполняются пакетами по opts.BatchSize в одной транзакции,
+// каждая строка - в своем savepoint, поэтому ошибка в строке не отменяет остальные строки пакета.
+// Ошибки строк возвращаются в результате; ошибка функции означает, что импорт прерван.
+func (s *NewsImportService) Import(ctx context.Context, format string, r io.Reader, opts models.NewsImportOptions) (*models.NewsImportResult, error) {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = defaultImportBatchSize
+	}
+
+	var read func(io.Reader, func(models.NewsImportRow, error) error) error
+	switch format {
+	case models.FormatNDJSON:
+		read = readNewsNDJSON
+	case models.FormatCSV:
+		read = readNewsCSV
+	default:
+		return nil, &models.ValidationError{Message: "Unsupported import format: " + format}
+	}
+
+	imp := &newsImport{
+		s:             s,
+		opts:          opts,
+		result:        &models.NewsImportResult{DryRun: opts.DryRun, CreatedCategories: []string{}, Errors: []models.NewsImportError{}},
+		categoryIDs:   make(map[int64]bool),
+		categoryNames: make(map[string]int64),
+		created:       make(map[string]bool),
+	}
+
+	err := read(r, func(row models.NewsImportRow, rowErr error) error {
+		imp.result.Total++
+		if rowErr != nil {
+			imp.fail(row.Line, rowErr.Error())
+			return nil
+		}
+
+		imp.batch = append(imp.batch, row)
+		if len(imp.batch) >= opts.BatchSize {
+			return imp.flush(ctx)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := imp.flush(ctx); err != nil {
+		return nil, err
+	}
+
+	if imp.explicitIDs && !opts.DryRun {
+		if err := s.newsRepo.SyncNewsSequence(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	return imp.result, nil
+}
+
+// newsImport состояние одного импорта
+type newsImport struct {
+	s      *NewsImportService
+	opts   models.NewsImportOptions
+	result *models.NewsImportResult
+	batch  []models.NewsImportRow
+
+	// categoryIDs и categoryNames - категории, существование которых уже проверено (в закоммиченных данных)
+	categoryIDs   map[int64]bool
+	categoryNames map[string]int64
+	// created - названия созданных категорий для результата
+	created     map[string]bool
+	explicitIDs bool
+}
+
+func (imp *newsImport) fail(line int, message string) {
+	imp.result.Failed++
+	if len(imp.result.Errors) < maxImportErrors {
+		imp.result.Errors = append(imp.result.Errors, models.NewsImportError{Line: line, Message: message})
+	}
+}
+
+// failRow добавляет ошибку строки. Текст ошибки проверки возвращается в результате,
+// остальные ошибки только пишутся в журнал: они могут содержать детали базы данных.
+func (imp *newsImport) failRow(line int, err error) {
+	var validationErr *models.ValidationError
+	if errors.As(err, &validationErr) {
+		imp.fail(line, validationErr.Message)
+		return
+	}
+	imp.s.logger.Errorw("News import row failed", "error", err, "line", line, "request_id", imp.opts.RequestId)
+	imp.fail(line, "failed to save row")
+}
+
+// flush выполняет накопленный пакет строк в одной транзакции
+func (imp *newsImport) flush(ctx context.Context) error {
+	if len(imp.batch) == 0 {
+		return nil
+	}
+	defer func() { imp.batch = imp.batch[:0] }()
+
+	var created, updated int
+	var okLines []int
+	var explicitIDs bool
+	batchNames := make(map[string]int64)
+
+	err := imp.s.newsRepo.InTransaction(ctx, func(txCtx context.Context) error {
+		for _, row := range imp.batch {
+			rowNames := make(map[string]int64)
+			var isNew bool
+
+			err := imp.s.newsRepo.InTransaction(txCtx, func(rowCtx context.Context) error {
+				var err error
+				isNew, err = imp.importRow(rowCtx, row, batchNames, rowNames)
+				return err
+			})
+			if err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				imp.failRow(row.Line, err)
+				continue
+			}
+
+			// категории, созданные строкой, видны следующим строкам пакета только после ее успешного выполнения
+			for name, id := range rowNames {
+				batchNames[name] = id
+			}
+			okLines = append(okLines, row.Line)
+			if isNew {
+				created++
+			} else {
+				updated++
+			}
+			if row.Id != 0 {
+				explicitIDs = true
+			}
+		}
+
+		if imp.opts.DryRun {
+			return errImportDryRun
+		}
+		return nil
+	})
+
+	if err != nil && !errors.Is(err, errImportDryRun) {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		imp.s.logger.Errorw("News import batch rolled back", "error", err, "lines", len(okLines), "request_id", imp.opts.RequestId)
+		for _, line := range okLines {
+			imp.fail(line, "batch rolled back")
+		}
+		return nil
+	}
+
+	imp.result.Created += created
+	imp.result.Updated += updated
+	imp.explicitIDs = imp.explicitIDs || explicitIDs
+	for name, id := range batchNames {
+		if !imp.created[name] {
+			imp.created[name] = true
+			imp.result.CreatedCategories = append(imp.result.CreatedCategories, name)
+		}
+		// после dry-run созданные категории откатываются, в следующем пакете они будут созданы заново
+		if !imp.opts.DryRun {
+			imp.categoryNames[name] = id
+		}
+	}
+
+	return nil
+}
+
+// importRow проверяет строку, находит или создает ее категории и сохраняет новость
+func (imp *newsImport) importRow(ctx context.Context, row models.NewsImportRow, batchNames, rowNames map[string]int64) (bool, error) {
+	if err := validateImportRow(row); err != nil {
+		return false, err
+	}
+
+	seen := make(map[int64]bool)
+	var categories []int64
+	add := func(id int64) {
+		if !seen[id] {
+			seen[id] = true
+			categories = append(categories, id)
+		}
+	}
+
+	for _, id := range row.CategoryIds {
+		if !imp.categoryIDs[id] {
+			category, err := imp.s.categoryRepo.GetCategoryByID(ctx, id)
+			if err != nil {
+				return false, err
+			}
+			if category == nil {
+				return false, &models.ValidationError{Message: fmt.Sprintf("category %d not found", id)}
+			}
+			imp.categoryIDs[id] = true
+		}
+		add(id)
+	}
+
+	for _, name := range row.CategoryNames {
+		id, err := imp.resolveCategoryName(ctx, name, batchNames, rowNames)
+		if err != nil {
+			return false, err
+		}
+		add(id)
+	}
+
+	news := &models.News{
+		Id:            row.Id,
+		Title:         row.Title,
+		Content:       row.Content,
+		ContentFormat: row.ContentFormat,
+		CreatedAt:     row.CreatedAt,
+	}
+	if err := prepareNewsContent(imp.s.sanitizer, sanitize.Relaxed, news); err != nil {
+		return false, err
+	}
+	return imp.s.newsRepo.ImportNews(ctx, news, categories)
+}
+
+// resolveCategoryName возвращает ID категории по названию, при необходимости создавая ее
+func (imp *newsImport) resolveCategoryName(ctx context.Context, name string, batchNames, rowNames map[string]int64) (int64, error) {
+	for _, known := range []map[string]int64{imp.categoryNames, batchNames, rowNames} {
+		if id, ok := known[name]; ok {
+			return id, nil
+		}
+	}
+
+	category, err := imp.s.categoryRepo.GetCategoryByName(ctx, name)
+	if err != nil {
+		return 0, err
+	}
+	if category != nil {
+		imp.categoryNames[name] = category.Id
+		return category.Id, nil
+	}
+
+	if !imp.opts.CreateMissingCategories {
+		return 0, &models.ValidationError{Message: fmt.Sprintf("category %q not found", name)}
+	}
+	if utf8.RuneCountInString(name) > 100 {
+		return 0, &models.ValidationError{Message: fmt.Sprintf("category name %q must be less than 100 characters", name)}
+	}
+
+	category = &models.Category{Name: name}
+	if err := imp.s.categoryRepo.CreateCategory(ctx, category); err != nil {
+		return 0, err
+	}
+	rowNames[name] = category.Id
+	return category.Id, nil
+}
+
+// validateImportRow проверяет поля строки
+func validateImportRow(row models.NewsImportRow) error {
+	switch {
+	case row.Id < 0:
+		return &models.ValidationError{Message: "id must be positive"}
+	case strings.TrimSpace(row.Title) == "":
+		return &models.ValidationError{Message: "title is required"}
+	case utf8.RuneCountInString(row.Title) > 255:
+		return &models.ValidationError{Message: "title must be at most 255 characters"}
+	case strings.TrimSpace(row.Content) == "":
+		return &models.ValidationError{Message: "content is required"}
+	}
+	return nil
+}
+
+// ndjsonNewsRow строка NDJSON: категории - числа (ID) или строки (названия)
+type ndjsonNewsRow struct {
+	Id            int64             `json:"id"`
+	Title         string            `json:"title"`
+	Content       string            `json:"content"`
+	ContentFormat string            `json:"content_format"`
+	Categories    []json.RawMessage `json:"categories"`
+	CreatedAt     *time.Time        `json:"created_at"`
+}
+
+// readNewsNDJSON читает новости по одной JSON записи в строке, пустые строки пропускаются.
+// Некорректная строка передается в fn как ошибка строки.
+func readNewsNDJSON(r io.Reader, fn func(models.NewsImportRow, error) error) error {
+	br := bufio.NewReader(r)
+	for line := 1; ; line++ {
+		data, err := br.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return err
+		}
+
+		if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 {
+			row, rowErr := parseNDJSONRow(trimmed)
+			row.Line = line
+			if err := fn(row, rowErr); err != nil {
+				return err
+			}
+		}
+
+		if err == io.EOF {
+			return nil
+		}
+	}
+}
+
+func parseNDJSONRow(data []byte) (models.NewsImportRow, error) {
+	var raw ndjsonNewsRow
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return models.NewsImportRow{}, fmt.Errorf("invalid JSON: %v", err)
+	}
+
+	row := models.NewsImportRow{Id: raw.Id, Title: raw.Title, Content: raw.Content, ContentFormat: raw.ContentFormat}
+	if raw.CreatedAt != nil {
+		row.CreatedAt = *raw.CreatedAt
+	}
+
+	for _, c := range raw.Categories {
+		var id int64
+		if err := json.Unmarshal(c, &id); err == nil {
+			row.CategoryIds = append(row.CategoryIds, id)
+			continue
+		}
+		var name string
+		if err := json.Unmarshal(c, &name); err == nil && strings.TrimSpace(name) != "" {
+			row.CategoryNames = append(row.CategoryNames, strings.TrimSpace(name))
+			continue
+		}
+		return row, fmt.Errorf("invalid category %s: expected ID or name", c)
+	}
+
+	return row, nil
+}
+
+// readNewsCSV читает новости из CSV с заголовком. Обязательны колонки title и content,
+// необязательны id, content_format, categories (ID или названия через ";", см. parseCSVCategories) и created_at (RFC 3339);
+// остальные колонки игнорируются.
+func readNewsCSV(r io.Reader, fn func(models.NewsImportRow, error) error) error {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return &models.ValidationError{Message: "Invalid CSV header: " + err.Error()}
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		if i == 0 {
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["title"]; !ok {
+		return &models.ValidationError{Message: "CSV header must contain title column"}
+	}
+	if _, ok := columns["content"]; !ok {
+		return &models.ValidationError{Message: "CSV header must contain content column"}
+	}
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+
+		var parseErr *csv.ParseError
+		if err != nil && !errors.As(err, &parseErr) {
+			return err
+		}
+
+		if err != nil {
+			if err := fn(models.NewsImportRow{Line: parseErr.Line}, fmt.Errorf("invalid CSV: %v", parseErr.Err)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		row, rowErr := parseCSVRow(record, columns)
+		row.Line, _ = reader.FieldPos(0)
+		if err := fn(row, rowErr); err != nil {
+			return err
+		}
+	}
+}
+
+func parseCSVRow(record []string, columns map[string]int) (models.NewsImportRow, error) {
+	field := func(name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return record[i]
+		}
+		return ""
+	}
+
+	row := models.NewsImportRow{
+		Title:         field("title"),
+		Content:       field("content"),
+		ContentFormat: strings.TrimSpace(field("content_format")),
+	}
+
+	if id := strings.TrimSpace(field("id")); id != "" {
+		v, err := strconv.ParseInt(id, 10, 64)
+		if err != nil {
+			return row, fmt.Errorf("invalid id %q", id)
+		}
+		row.Id = v
+	}
+
+	if createdAt := strings.TrimSpace(field("created_at")); createdAt != "" {
+		t, err := time.Parse(time.RFC3339, createdAt)
+		if err != nil {
+			return row, fmt.Errorf("invalid created_at %q: expected RFC 3339", createdAt)
+		}
+		row.CreatedAt = t
+	}
+
+	row.CategoryIds, row.CategoryNames = parseCSVCategories(field("categories"))
+
+	return row, nil
+}
+
+// parseCSVCategories разбирает колонку categories: значения разделены ";", "\" экранирует следующий символ.
+// Число без экранированных символов - ID категории, остальные значения - названия,
+// поэтому название "2024" записывается как "\2024", а "a;b" - как "a\;b".
+func parseCSVCategories(value string) ([]int64, []string) {
+	var ids []int64
+	var names []string
+	var b strings.Builder
+	escaped, name := false, false
+
+	add := func() {
+		c := strings.TrimSpace(b.String())
+		b.Reset()
+		if c != "" {
+			if id, err := strconv.ParseInt(c, 10, 64); err == nil && !name {
+				ids = append(ids, id)
+			} else {
+				names = append(names, c)
+			}
+		}
+		name = false
+	}
+
+	for _, r := range value {
+		switch {
+		case escaped:
+			b.WriteRune(r)
+			escaped = false
+		case r == csvCategoryEscape:
+			escaped, name = true, true
+		case r == csvCategorySeparator:
+			add()
+		default:
+			b.WriteRune(r)
+		}
+	}
+	if escaped {
+		b.WriteRune(csvCategoryEscape)
+	}
+	add()
+
+	return ids, names
+}
+
+// formatCSVCategories записывает названия категорий так, чтобы parseCSVCategories вернул их без изменений
+func formatCSVCategories(names []string) string {
+	var b strings.Builder
+	for i, name := range names {
+		if i > 0 {
+			b.WriteRune(csvCategorySeparator)
+		}
+		if _, err := strconv.ParseInt(strings.TrimSpace(name), 10, 64); err == nil {
+			b.WriteRune(csvCategoryEscape)
+		}
+		for _, r := range name {
+			if r == csvCategorySeparator || r == csvCategoryEscape {
+				b.WriteRune(csvCategoryEscape)
+			}
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// Export пишет все новости с названиями категорий в формате NDJSON или CSV по мере чтения из базы
+func (s *NewsImportService) Export(ctx context.Context, format string, w io.Writer) error {
+	switch format {
+	case models.FormatNDJSON:
+		enc := json.NewEncoder(w)
+		enc.SetEscapeHTML(false)
+		return s.newsRepo.EachNewsForExport(ctx, func(news models.News, categories []string) error {
+			return enc.Encode(models.NewsExportRecord{
+				Id:            news.Id,
+				Title:         news.Title,
+				Content:       exportContent(news),
+				ContentFormat: news.ContentFormat,
+				Categories:    categories,
+				CreatedAt:     news.CreatedAt,
+				UpdatedAt:     news.UpdatedAt,
+			})
+		})
+	case models.FormatCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(csvExportHeader); err != nil {
+			return err
+		}
+		err := s.newsRepo.EachNewsForExport(ctx, func(news models.News, categories []string) error {
+			return cw.Write(csvExportRecord(news, categories))
+		})
+		if err != nil {
+			return err
+		}
+		cw.Flush()
+		return cw.Error()
+	default:
+		return &models.ValidationError{Message: "Unsupported export format: " + format}
+	}
+}
+
+// csvExportRecord возвращает строку CSV экспорта в порядке csvExportHeader
+func csvExportRecord(news models.News, categories []string) []string {
+	return []string{
+		strconv.FormatInt(news.Id, 10),
+		news.Title,
+		exportContent(news),
+		news.ContentFormat,
+		formatCSVCategories(categories),
+		news.CreatedAt.Format(time.RFC3339),
+		news.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+// exportContent возвращает содержимое в том виде, в котором его передал автор, чтобы экспорт можно было импортировать
+func exportContent(news models.News) string {
+	if news.ContentFormat == models.SourceFormatMarkdown {
+		return news.ContentSource
+	}
+	return news.Content
+}
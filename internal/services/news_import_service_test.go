@@ -0,0 +1,148 @@
+package services
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"go_news_server/internal/models"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type parsedRow struct {
+	row models.NewsImportRow
+	err error
+}
+
+func collectRows(t *testing.T, read func(io.Reader, func(models.NewsImportRow, error) error) error, input string) []parsedRow {
+	var rows []parsedRow
+	require.NoError(t, read(strings.NewReader(input), func(row models.NewsImportRow, err error) error {
+		rows = append(rows, parsedRow{row: row, err: err})
+		return nil
+	}))
+	return rows
+}
+
+func TestReadNewsNDJSON(t *testing.T) {
+	input := `{"id": 7, "title": "Первая", "content": "Текст", "categories": [1, "Спорт"], "created_at": "2025-07-20T12:00:00Z"}
+
+{"title": "Вторая", "content": "Текст", "categories": [true]}
+not json
+{"title": "Третья", "content": "Без перевода строки"}`
+
+	rows := collectRows(t, readNewsNDJSON, input)
+	require.Len(t, rows, 4)
+
+	require.NoError(t, rows[0].err)
+	assert.Equal(t, 1, rows[0].row.Line)
+	assert.Equal(t, int64(7), rows[0].row.Id)
+	assert.Equal(t, []int64{1}, rows[0].row.CategoryIds)
+	assert.Equal(t, []string{"Спорт"}, rows[0].row.CategoryNames)
+	assert.True(t, rows[0].row.CreatedAt.Equal(time.Date(2025, 7, 20, 12, 0, 0, 0, time.UTC)))
+
+	assert.Error(t, rows[1].err)
+	assert.Equal(t, 3, rows[1].row.Line)
+
+	assert.Error(t, rows[2].err)
+	assert.Equal(t, 4, rows[2].row.Line)
+
+	require.NoError(t, rows[3].err)
+	assert.Equal(t, 5, rows[3].row.Line)
+	assert.Equal(t, "Без перевода строки", rows[3].row.Content)
+}
+
+func TestReadNewsCSV(t *testing.T) {
+	input := "\ufeffTitle,content,categories,extra\n" +
+		"Первая,\"Текст, с запятой\",\"1; Спорт ;\",x\n" +
+		"Вторая,\"многострочный\nтекст\",,\n" +
+		"Третья,Текст\n"
+
+	rows := collectRows(t, readNewsCSV, input)
+	require.Len(t, rows, 3)
+
+	require.NoError(t, rows[0].err)
+	assert.Equal(t, 2, rows[0].row.Line)
+	assert.Equal(t, "Текст, с запятой", rows[0].row.Content)
+	assert.Equal(t, []int64{1}, rows[0].row.CategoryIds)
+	assert.Equal(t, []string{"Спорт"}, rows[0].row.CategoryNames)
+
+	require.NoError(t, rows[1].err)
+	assert.Equal(t, 3, rows[1].row.Line)
+	assert.Equal(t, "многострочный\nтекст", rows[1].row.Content)
+
+	require.NoError(t, rows[2].err)
+	assert.Equal(t, 5, rows[2].row.Line)
+	assert.Equal(t, "Текст", rows[2].row.Content)
+}
+
+func TestCSVExportRoundTrip(t *testing.T) {
+	categories := []string{"2024", "Спорт; футбол", `C:\news`, "-1", "Политика"}
+	news := models.News{
+		Id:            3,
+		Title:         "Заголовок",
+		Content:       "<p>Текст</p>",
+		ContentFormat: models.SourceFormatHTML,
+		CreatedAt:     time.Date(2025, 7, 20, 12, 0, 0, 0, time.UTC),
+		UpdatedAt:     time.Date(2025, 7, 21, 12, 0, 0, 0, time.UTC),
+	}
+
+	var buf bytes.Buffer
+	cw := csv.NewWriter(&buf)
+	require.NoError(t, cw.Write(csvExportHeader))
+	require.NoError(t, cw.Write(csvExportRecord(news, categories)))
+	cw.Flush()
+	require.NoError(t, cw.Error())
+
+	rows := collectRows(t, readNewsCSV, buf.String())
+	require.Len(t, rows, 1)
+	require.NoError(t, rows[0].err)
+	assert.Equal(t, news.Id, rows[0].row.Id)
+	assert.Equal(t, news.Title, rows[0].row.Title)
+	assert.Equal(t, news.Content, rows[0].row.Content)
+	assert.Empty(t, rows[0].row.CategoryIds)
+	assert.Equal(t, categories, rows[0].row.CategoryNames)
+	assert.True(t, rows[0].row.CreatedAt.Equal(news.CreatedAt))
+}
+
+func TestParseCSVCategories(t *testing.T) {
+	ids, names := parseCSVCategories(`1; \2024 ;a\;b;;c\\`)
+	assert.Equal(t, []int64{1}, ids)
+	assert.Equal(t, []string{"2024", "a;b", `c\`}, names)
+}
+
+func TestReadNewsCSVRequiresColumns(t *testing.T) {
+	err := readNewsCSV(strings.NewReader("title,body\nA,B\n"), func(models.NewsImportRow, error) error {
+		return nil
+	})
+	assert.IsType(t, &models.ValidationError{}, err)
+}
+
+func TestValidateImportRow(t *testing.T) {
+	assert.NoError(t, validateImportRow(models.NewsImportRow{Title: "Заголовок", Content: "Текст"}))
+	assert.Error(t, validateImportRow(models.NewsImportRow{Title: " ", Content: "Текст"}))
+	assert.Error(t, validateImportRow(models.NewsImportRow{Title: "Заголовок"}))
+	assert.Error(t, validateImportRow(models.NewsImportRow{Title: strings.Repeat("я", 256), Content: "Текст"}))
+	assert.NoError(t, validateImportRow(models.NewsImportRow{Title: strings.Repeat("я", 255), Content: "Текст"}))
+}
+
+func TestNewsImportFailRow(t *testing.T) {
+	imp := &newsImport{
+		s:      &NewsImportService{logger: zap.NewNop().Sugar()},
+		result: &models.NewsImportResult{},
+	}
+
+	imp.failRow(2, &models.ValidationError{Message: "title is required"})
+	imp.failRow(3, errors.New(`pq: duplicate key value violates unique constraint "News_pkey"`))
+
+	assert.Equal(t, 2, imp.result.Failed)
+	assert.Equal(t, []models.NewsImportError{
+		{Line: 2, Message: "title is required"},
+		{Line: 3, Message: "failed to save row"},
+	}, imp.result.Errors)
+}
@@ -2,27 +2,308 @@ package services
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"fmt"
 	"go_news_server/internal/models"
 	"go_news_server/internal/repository"
+	"go_news_server/pkg/markdown"
+	"go_news_server/pkg/sanitize"
+	"slices"
+	"strings"
 )
 
 // NewsServiceInterface - интерфейс для NewsService
 type NewsServiceInterface interface {
 	UpdateNews(ctx context.Context, news *models.News, categories []int64) error
-	GetNewsList(ctx context.Context, limit, offset int) ([]models.News, error)
+	GetNewsList(ctx context.Context, filter models.NewsFilter, limit, offset int) ([]models.News, error)
+	GetNewsBySlug(ctx context.Context, slug string) (*models.News, string, error)
+	GetRelatedNews(ctx context.Context, id int64, limit int) ([]models.News, error)
+	GetPopularNews(ctx context.Context, window string, categoryID int64, limit int) ([]models.News, error)
+	AddViewTotals(ctx context.Context, newsList []models.News) error
 }
 
 type NewsService struct {
 	Repository *repository.NewsRepository
+	Sanitizer  *sanitize.Sanitizer
+	// Media добавляет к новостям их изображения, nil - без изображений
+	Media *MediaService
+	// Translations переводит новости на язык из контекста, nil - без переводов
+	Translations *TranslationService
+	// Related кэширует похожие новости, nil - похожие новости считаются при каждом запросе
+	Related *RelatedService
+	// Views считает просмотры для популярных новостей, nil - популярных новостей нет
+	Views *ViewService
 }
 
 // Проверка, что NewsService реализует интерфейс
 var _ NewsServiceInterface = (*NewsService)(nil)
 
+type contentPolicyKey struct{}
+
+// WithContentPolicy возвращает контекст с политикой очистки HTML для записи новостей
+func WithContentPolicy(ctx context.Context, policy string) context.Context {
+	return context.WithValue(ctx, contentPolicyKey{}, policy)
+}
+
+// ContentPolicyFromContext возвращает политику очистки HTML из контекста, по умолчанию самую строгую
+func ContentPolicyFromContext(ctx context.Context) string {
+	if policy, ok := ctx.Value(contentPolicyKey{}).(string); ok && policy != "" {
+		return policy
+	}
+	return sanitize.Strict
+}
+
+// UpdateNews формирует HTML из Content по его формату, очищает его по политике из контекста и сохраняет новость.
+// Если ContentFormat не задан, сохраняется формат, в котором новость хранится сейчас.
+// Несуществующие категории возвращаются ошибкой поля Categories до записи,
+// для несуществующей новости возвращается models.NotFoundError.
 func (s *NewsService) UpdateNews(ctx context.Context, news *models.News, categories []int64) error {
-	return s.Repository.UpdateNews(ctx, news, categories)
+	if err := s.checkCategories(ctx, categories); err != nil {
+		return err
+	}
+
+	if news.Content == "" {
+		if news.ContentFormat != "" {
+			return &models.ValidationError{Message: "Content is required to change ContentFormat"}
+		}
+		return s.updateNews(ctx, news, categories)
+	}
+
+	if news.ContentFormat == "" {
+		current, err := s.Repository.GetNewsByID(ctx, news.Id)
+		if err != nil {
+			return err
+		}
+		if current == nil {
+			return &models.NotFoundError{Message: "News not found"}
+		}
+		news.ContentFormat = current.ContentFormat
+	}
+
+	if err := prepareNewsContent(s.Sanitizer, ContentPolicyFromContext(ctx), news); err != nil {
+		return err
+	}
+	return s.updateNews(ctx, news, categories)
+}
+
+func (s *NewsService) updateNews(ctx context.Context, news *models.News, categories []int64) error {
+	err := s.Repository.UpdateNews(ctx, news, categories)
+	if errors.Is(err, sql.ErrNoRows) {
+		return &models.NotFoundError{Message: "News not found"}
+	}
+	return err
 }
 
-func (s *NewsService) GetNewsList(ctx context.Context, limit, offset int) ([]models.News, error) {
-	return s.Repository.GetNewsList(ctx, limit, offset)
+// checkCategories проверяет, что все категории новости существуют
+func (s *NewsService) checkCategories(ctx context.Context, categories []int64) error {
+	missing, err := s.Repository.GetMissingCategoryIDs(ctx, categories)
+	if err != nil || len(missing) == 0 {
+		return err
+	}
+
+	validationErr := &models.ValidationError{Message: "Categories not found"}
+	for i, id := range categories {
+		if slices.Contains(missing, id) {
+			validationErr.Fields = append(validationErr.Fields, models.FieldError{
+				Field:   fmt.Sprintf("Categories[%d]", i),
+				Code:    models.FieldCodeNotFound,
+				Message: fmt.Sprintf("Category %d not found", id),
+			})
+		}
+	}
+	return validationErr
+}
+
+func (s *NewsService) GetNewsList(ctx context.Context, filter models.NewsFilter, limit, offset int) ([]models.News, error) {
+	newsList, err := s.Repository.GetNewsList(ctx, filter, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.prepareResponse(ctx, newsList); err != nil {
+		return nil, err
+	}
+	return newsList, nil
+}
+
+// GetNewsBySlug получает новость по slug. Если slug устарел, новость не возвращается,
+// а вторым значением возвращается ее текущий slug для перенаправления.
+func (s *NewsService) GetNewsBySlug(ctx context.Context, slug string) (*models.News, string, error) {
+	news, err := s.Repository.GetNewsBySlug(ctx, slug)
+	if err != nil {
+		return nil, "", err
+	}
+	if news != nil {
+		newsList := []models.News{*news}
+		if err := s.prepareResponse(ctx, newsList); err != nil {
+			return nil, "", err
+		}
+		return &newsList[0], "", nil
+	}
+
+	current, err := s.Repository.GetNewsSlugRedirect(ctx, slug)
+	if err != nil {
+		return nil, "", err
+	}
+	if current == "" {
+		return nil, "", &models.NotFoundError{Message: "News not found"}
+	}
+	return nil, current, nil
+}
+
+// GetRelatedNews получает до limit новостей, похожих на новость id, по убыванию сходства
+func (s *NewsService) GetRelatedNews(ctx context.Context, id int64, limit int) ([]models.News, error) {
+	if limit <= 0 || limit > MaxRelatedLimit {
+		limit = MaxRelatedLimit
+	}
+
+	news, err := s.Repository.GetNewsByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if news == nil {
+		return nil, &models.NotFoundError{Message: "News not found"}
+	}
+
+	var ids []int64
+	if s.Related != nil {
+		ids, err = s.Related.GetRelatedIDs(ctx, id, limit)
+	} else {
+		var related []repository.RelatedNews
+		related, err = s.Repository.GetRelatedNewsIDs(ctx, id, limit)
+		for _, item := range related {
+			ids = append(ids, item.NewsId)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	newsList, err := s.Repository.GetNewsByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.prepareResponse(ctx, newsList); err != nil {
+		return nil, err
+	}
+	return newsList, nil
+}
+
+// GetPopularNews получает до limit самых просматриваемых за window новостей категории categoryID
+// и ее дочерних категорий (0 - всех новостей). Views новостей - просмотры за период.
+func (s *NewsService) GetPopularNews(ctx context.Context, window string, categoryID int64, limit int) ([]models.News, error) {
+	if s.Views == nil {
+		return []models.News{}, nil
+	}
+
+	popular, err := s.Views.GetPopular(ctx, window, categoryID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int64, len(popular))
+	views := make(map[int64]int64, len(popular))
+	for i, item := range popular {
+		ids[i] = item.NewsId
+		views[item.NewsId] = item.Views
+	}
+
+	newsList, err := s.Repository.GetNewsByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range newsList {
+		newsList[i].Views = views[newsList[i].Id]
+	}
+	if err := s.prepareResponse(ctx, newsList); err != nil {
+		return nil, err
+	}
+	return newsList, nil
+}
+
+// AddViewTotals заполняет Views новостей просмотрами за все время, без Views ничего не меняет
+func (s *NewsService) AddViewTotals(ctx context.Context, newsList []models.News) error {
+	if s.Views == nil {
+		return nil
+	}
+	return s.Views.AddTotals(ctx, newsList)
+}
+
+// prepareResponse дополняет новости для ответа: переводит на язык из контекста и добавляет изображения
+func (s *NewsService) prepareResponse(ctx context.Context, newsList []models.News) error {
+	if len(newsList) == 0 {
+		return nil
+	}
+	if s.Translations != nil {
+		if err := s.Translations.TranslateNews(ctx, newsList); err != nil {
+			return err
+		}
+	}
+	if s.Media != nil {
+		return s.Media.AttachMedia(ctx, newsList)
+	}
+	return nil
+}
+
+// prepareNewsContent формирует из переданного автором news.Content очищенный HTML (Content),
+// исходный Markdown (ContentSource) и текст без разметки (ContentText)
+func prepareNewsContent(sanitizer *sanitize.Sanitizer, policy string, news *models.News) error {
+	html := news.Content
+	switch news.ContentFormat {
+	case "", models.SourceFormatHTML:
+		news.ContentFormat = models.SourceFormatHTML
+		news.ContentSource = ""
+	case models.SourceFormatMarkdown:
+		rendered, err := markdown.Render(news.Content)
+		if err != nil {
+			return err
+		}
+		news.ContentSource = news.Content
+		html = rendered
+	default:
+		return &models.ValidationError{Message: "Unsupported ContentFormat: " + news.ContentFormat}
+	}
+
+	content, err := sanitizer.Sanitize(policy, html)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(content) == "" {
+		return &models.ValidationError{Message: "Content is empty after removing disallowed HTML"}
+	}
+
+	news.Content = content
+	news.ContentText = sanitize.PlainText(content)
+	return nil
+}
+
+// ApplyContentFormat заменяет Content новостей представлением в формате format.
+// Исходный Markdown (ContentSource и format=markdown-source) доступен только при withSource.
+func ApplyContentFormat(newsList []models.News, format string, withSource bool) error {
+	switch format {
+	case "", models.ContentFormatHTML:
+	case models.ContentFormatText:
+		for i := range newsList {
+			newsList[i].Content = newsList[i].ContentText
+		}
+	case models.ContentFormatMarkdownSource:
+		if !withSource {
+			return &models.ValidationError{Message: "markdown-source format is available only on private endpoints"}
+		}
+		// у новостей в HTML исходник совпадает с Content
+		for i := range newsList {
+			if newsList[i].ContentFormat == models.SourceFormatMarkdown {
+				newsList[i].Content = newsList[i].ContentSource
+			}
+		}
+	default:
+		return &models.ValidationError{Message: "Unsupported content format: " + format}
+	}
+
+	if !withSource {
+		for i := range newsList {
+			newsList[i].ContentSource = ""
+		}
+	}
+	return nil
 }
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"go_news_server/internal/models"
+	"go_news_server/pkg/sanitize"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrepareNewsContent(t *testing.T) {
+	sanitizer := sanitize.New(sanitize.Config{})
+
+	// встроенные HTML теги Markdown не выводятся, текст между ними остается
+	news := &models.News{Content: "## Итоги матча\n\nСчет **2:1** <script>alert(1)</script>", ContentFormat: models.SourceFormatMarkdown}
+	require.NoError(t, prepareNewsContent(sanitizer, sanitize.Relaxed, news))
+	assert.Equal(t, "<h2 id=\"итоги-матча\">Итоги матча</h2>\n<p>Счет <strong>2:1</strong> alert(1)</p>\n", news.Content)
+	assert.Equal(t, "## Итоги матча\n\nСчет **2:1** <script>alert(1)</script>", news.ContentSource)
+	assert.Equal(t, "Итоги матча\nСчет 2:1 alert(1)", news.ContentText)
+
+	news = &models.News{Content: `<p onclick="x()">Text</p>`, ContentSource: "old"}
+	require.NoError(t, prepareNewsContent(sanitizer, sanitize.Strict, news))
+	assert.Equal(t, models.SourceFormatHTML, news.ContentFormat)
+	assert.Equal(t, "<p>Text</p>", news.Content)
+	assert.Empty(t, news.ContentSource)
+
+	news = &models.News{Content: `<script>alert(1)</script>`}
+	assert.IsType(t, &models.ValidationError{}, prepareNewsContent(sanitizer, sanitize.Strict, news))
+
+	news = &models.News{Content: "x", ContentFormat: "rst"}
+	assert.IsType(t, &models.ValidationError{}, prepareNewsContent(sanitizer, sanitize.Strict, news))
+}
+
+func TestApplyContentFormat(t *testing.T) {
+	list := func() []models.News {
+		return []models.News{
+			{Content: "<p>html</p>", ContentText: "html", ContentFormat: models.SourceFormatHTML},
+			{Content: "<p><em>md</em></p>", ContentText: "md", ContentFormat: models.SourceFormatMarkdown, ContentSource: "*md*"},
+		}
+	}
+
+	news := list()
+	require.NoError(t, ApplyContentFormat(news, "", false))
+	assert.Equal(t, "<p><em>md</em></p>", news[1].Content)
+	assert.Empty(t, news[1].ContentSource)
+
+	news = list()
+	require.NoError(t, ApplyContentFormat(news, models.ContentFormatText, true))
+	assert.Equal(t, "md", news[1].Content)
+	assert.Equal(t, "*md*", news[1].ContentSource)
+
+	news = list()
+	require.NoError(t, ApplyContentFormat(news, models.ContentFormatMarkdownSource, true))
+	assert.Equal(t, "<p>html</p>", news[0].Content)
+	assert.Equal(t, "*md*", news[1].Content)
+
+	assert.Error(t, ApplyContentFormat(list(), models.ContentFormatMarkdownSource, false))
+	assert.Error(t, ApplyContentFormat(list(), "pdf", true))
+}
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"context"
+	"go_news_server/internal/events"
+	"go_news_server/internal/repository"
+	"slices"
+	"sync"
+	"time"
+)
+
+// MaxRelatedLimit - наибольшее число похожих новостей в ответе. Кэшируется всегда столько,
+// меньшие limit берут начало списка.
+const MaxRelatedLimit = 20
+
+// RelatedConfig - параметры кэша похожих новостей
+type RelatedConfig struct {
+	// TTL - время жизни списка в кэше: новые новости попадают в списки других новостей не позже него
+	TTL time.Duration
+	// MaxEntries - наибольшее число новостей в кэше
+	MaxEntries int
+}
+
+type relatedEntry struct {
+	ids       []int64
+	expiresAt time.Time
+}
+
+// RelatedService кэширует ID похожих новостей. Кэшируются только ID, сами новости читаются
+// при каждом запросе, поэтому изменения похожих новостей видны сразу. При изменении новости или ее
+// категорий (события шины) сбрасываются ее список и списки, в которые она входит, весь кэш - после
+// переподключения к базе.
+type RelatedService struct {
+	repo   *repository.NewsRepository
+	bus    *events.Bus
+	config RelatedConfig
+	now    func() time.Time
+
+	mu    sync.Mutex
+	cache map[int64]relatedEntry
+	// generation увеличивается при каждом сбросе. Список, посчитанный до сброса, не сохраняется:
+	// изменение могло затронуть любую новость списка, а не только ту, для которой он считался.
+	generation uint64
+
+	sub *events.Subscription
+	wg  sync.WaitGroup
+}
+
+func NewRelatedService(repo *repository.NewsRepository, bus *events.Bus, config RelatedConfig) *RelatedService {
+	if config.TTL <= 0 {
+		config.TTL = 10 * time.Minute
+	}
+	if config.MaxEntries <= 0 {
+		config.MaxEntries = 10000
+	}
+
+	return &RelatedService{
+		repo:   repo,
+		bus:    bus,
+		config: config,
+		now:    time.Now,
+		cache:  make(map[int64]relatedEntry),
+	}
+}
+
+// Start подписывается на шину событий для сброса кэша
+func (s *RelatedService) Start() {
+	s.sub = s.bus.Subscribe(256)
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		for event := range s.sub.C() {
+			s.handleEvent(event)
+		}
+	}()
+}
+
+// Stop прекращает обработку событий
+func (s *RelatedService) Stop() {
+	if s.sub != nil {
+		s.sub.Close()
+	}
+	s.wg.Wait()
+}
+
+// GetRelatedIDs возвращает ID до limit новостей, похожих на новость id, по убыванию оценки
+func (s *RelatedService) GetRelatedIDs(ctx context.Context, id int64, limit int) ([]int64, error) {
+	if limit <= 0 || limit > MaxRelatedLimit {
+		limit = MaxRelatedLimit
+	}
+
+	now := s.now()
+	s.mu.Lock()
+	entry, ok := s.cache[id]
+	generation := s.generation
+	s.mu.Unlock()
+
+	if !ok || now.After(entry.expiresAt) {
+		related, err := s.repo.GetRelatedNewsIDs(ctx, id, MaxRelatedLimit)
+		if err != nil {
+			return nil, err
+		}
+		entry = relatedEntry{ids: make([]int64, len(related)), expiresAt: now.Add(s.config.TTL)}
+		for i, item := range related {
+			entry.ids[i] = item.NewsId
+		}
+		s.store(id, entry, generation)
+	}
+
+	if len(entry.ids) > limit {
+		return entry.ids[:limit], nil
+	}
+	return entry.ids, nil
+}
+
+// store сохраняет список, посчитанный в поколении generation, если с тех пор кэш не сбрасывался.
+// При переполнении удаляются устаревшие списки, а если их нет - весь кэш:
+// списки дешево пересчитать, а учет порядка использования для вытеснения не окупается.
+func (s *RelatedService) store(id int64, entry relatedEntry, generation uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if generation != s.generation {
+		return
+	}
+
+	if len(s.cache) >= s.config.MaxEntries {
+		now := s.now()
+		for key, existing := range s.cache {
+			if now.After(existing.expiresAt) {
+				delete(s.cache, key)
+			}
+		}
+		if len(s.cache) >= s.config.MaxEntries {
+			s.cache = make(map[int64]relatedEntry)
+		}
+	}
+	s.cache[id] = entry
+}
+
+// handleEvent сбрасывает списки, на которые влияет изменение
+func (s *RelatedService) handleEvent(event events.Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case event.Action == events.ActionResync:
+		s.cache = make(map[int64]relatedEntry)
+	case event.Table == events.TableNews:
+		s.evict(event.Id)
+	case event.Table == events.TableNewsCategories:
+		s.evict(event.NewsId)
+	default:
+		return
+	}
+	s.generation++
+}
+
+// evict удаляет список новости id и списки, в которые она входит
+func (s *RelatedService) evict(id int64) {
+	delete(s.cache, id)
+	for key, entry := range s.cache {
+		if slices.Contains(entry.ids, id) {
+			delete(s.cache, key)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"go_news_server/internal/events"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRelatedServiceInvalidation(t *testing.T) {
+	s := NewRelatedService(nil, events.NewBus(), RelatedConfig{MaxEntries: 3})
+	now := time.Date(2025, 7, 20, 12, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	s.config.MaxEntries = 5
+	for id := int64(1); id <= 3; id++ {
+		s.store(id, relatedEntry{ids: []int64{id + 10}, expiresAt: now.Add(time.Minute)}, 0)
+	}
+	s.store(4, relatedEntry{ids: []int64{3, 12}, expiresAt: now.Add(time.Minute)}, 0)
+
+	s.handleEvent(events.Event{Table: events.TableNews, Action: events.ActionUpdate, Id: 1})
+	s.handleEvent(events.Event{Table: events.TableNewsCategories, Action: events.ActionInsert, NewsId: 2, CategoryId: 5})
+	s.handleEvent(events.Event{Table: events.TableCategories, Action: events.ActionUpdate, Id: 3})
+	assert.NotContains(t, s.cache, int64(1))
+	assert.NotContains(t, s.cache, int64(2))
+	assert.Contains(t, s.cache, int64(3))
+
+	// удаленная новость 12 входит в список новости 4
+	s.handleEvent(events.Event{Table: events.TableNews, Action: events.ActionDelete, Id: 12})
+	assert.NotContains(t, s.cache, int64(4))
+	assert.Contains(t, s.cache, int64(3))
+
+	// список, посчитанный до события, не сохраняется
+	generation := s.generation
+	s.handleEvent(events.Event{Table: events.TableNews, Action: events.ActionUpdate, Id: 15})
+	s.store(5, relatedEntry{ids: []int64{15}, expiresAt: now.Add(time.Minute)}, generation)
+	assert.NotContains(t, s.cache, int64(5))
+
+	s.handleEvent(events.Event{Action: events.ActionResync})
+	assert.Empty(t, s.cache)
+}
+
+func TestRelatedServiceEviction(t *testing.T) {
+	s := NewRelatedService(nil, events.NewBus(), RelatedConfig{MaxEntries: 2})
+	now := time.Date(2025, 7, 20, 12, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	s.store(1, relatedEntry{expiresAt: now.Add(-time.Second)}, 0)
+	s.store(2, relatedEntry{expiresAt: now.Add(time.Minute)}, 0)
+
+	// устаревший список вытесняется первым
+	s.store(3, relatedEntry{expiresAt: now.Add(time.Minute)}, 0)
+	assert.Len(t, s.cache, 2)
+	assert.NotContains(t, s.cache, int64(1))
+
+	// без устаревших кэш очищается целиком
+	s.store(4, relatedEntry{expiresAt: now.Add(time.Minute)}, 0)
+	assert.Len(t, s.cache, 1)
+	assert.Contains(t, s.cache, int64(4))
+}
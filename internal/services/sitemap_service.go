@@ -0,0 +1,217 @@
+package services
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"go_news_server/internal/repository"
+	"io"
+	"strconv"
+	"time"
+)
+
+// SitemapMaxURLs - ограничение протокола sitemaps на количество адресов в одном файле
+const SitemapMaxURLs = 50000
+
+const (
+	sitemapURLSetOpen  = xml.Header + `<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">` + "\n"
+	sitemapURLSetClose = "</urlset>\n"
+	sitemapIndexOpen   = xml.Header + `<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">` + "\n"
+	sitemapIndexClose  = "</sitemapindex>\n"
+)
+
+// errSitemapFull - в файл уже записано MaxURLs адресов
+var errSitemapFull = errors.New("sitemap is full")
+
+// SitemapConfig настройки sitemap
+type SitemapConfig struct {
+	// BaseURL - адрес публичного сайта без завершающего "/"
+	BaseURL string
+	// MaxURLs - количество адресов, после которого /sitemap.xml становится индексом
+	MaxURLs int64
+}
+
+// SitemapService формирует sitemap страниц новостей и категорий.
+// Пока адресов не больше MaxURLs, /sitemap.xml содержит их все. Иначе /sitemap.xml - индекс,
+// ссылающийся на файлы категорий и новостей по диапазонам ID размера MaxURLs.
+// Данные пишутся в ответ по мере чтения из базы.
+type SitemapService struct {
+	newsRepo     *repository.NewsRepository
+	categoryRepo *repository.CategoryRepository
+	config       SitemapConfig
+}
+
+func NewSitemapService(newsRepo *repository.NewsRepository, categoryRepo *repository.CategoryRepository, config SitemapConfig) *SitemapService {
+	if config.MaxURLs <= 0 || config.MaxURLs > SitemapMaxURLs {
+		config.MaxURLs = SitemapMaxURLs
+	}
+
+	return &SitemapService{
+		newsRepo:     newsRepo,
+		categoryRepo: categoryRepo,
+		config:       config,
+	}
+}
+
+// UseIndex проверяет, нужно ли разбивать sitemap на несколько файлов
+func (s *SitemapService) UseIndex(ctx context.Context) (bool, error) {
+	news, err := s.newsRepo.CountNews(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	categories, err := s.categoryRepo.CountCategories(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	return news+categories > s.config.MaxURLs, nil
+}
+
+// CategoryChunkPath возвращает путь файла sitemap с категориями диапазона chunk
+func CategoryChunkPath(chunk int64) string {
+	return "/sitemaps/categories-" + strconv.FormatInt(chunk, 10) + ".xml"
+}
+
+// NewsChunkPath возвращает путь файла sitemap с новостями диапазона chunk
+func NewsChunkPath(chunk int64) string {
+	return "/sitemaps/news-" + strconv.FormatInt(chunk, 10) + ".xml"
+}
+
+// WriteURLSet пишет sitemap со всеми категориями и новостями.
+// UseIndex считает адреса отдельным запросом, и к началу записи их может стать больше:
+// адреса сверх MaxURLs отбрасываются, следующий запрос /sitemap.xml получит индекс.
+func (s *SitemapService) WriteURLSet(ctx context.Context, w io.Writer) error {
+	if _, err := io.WriteString(w, sitemapURLSetOpen); err != nil {
+		return err
+	}
+	urls := s.newURLWriter(w)
+	err := s.writeCategories(ctx, urls, 0, 0)
+	if err == nil {
+		err = s.writeNews(ctx, urls, 0, 0)
+	}
+	if err != nil && !errors.Is(err, errSitemapFull) {
+		return err
+	}
+	_, err = io.WriteString(w, sitemapURLSetClose)
+	return err
+}
+
+// HasCategoryChunk проверяет, что в диапазоне chunk есть категории
+func (s *SitemapService) HasCategoryChunk(ctx context.Context, chunk int64) (bool, error) {
+	if chunk < 0 {
+		return false, nil
+	}
+	return s.categoryRepo.HasCategoriesInRange(ctx, chunk*s.config.MaxURLs, (chunk+1)*s.config.MaxURLs)
+}
+
+// WriteCategoryChunk пишет sitemap категорий с ID из диапазона chunk
+func (s *SitemapService) WriteCategoryChunk(ctx context.Context, chunk int64, w io.Writer) error {
+	if _, err := io.WriteString(w, sitemapURLSetOpen); err != nil {
+		return err
+	}
+	if err := s.writeCategories(ctx, s.newURLWriter(w), chunk*s.config.MaxURLs, (chunk+1)*s.config.MaxURLs); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, sitemapURLSetClose)
+	return err
+}
+
+// HasNewsChunk проверяет, что в диапазоне chunk есть новости
+func (s *SitemapService) HasNewsChunk(ctx context.Context, chunk int64) (bool, error) {
+	if chunk < 0 {
+		return false, nil
+	}
+	return s.newsRepo.HasNewsInRange(ctx, chunk*s.config.MaxURLs, (chunk+1)*s.config.MaxURLs)
+}
+
+// WriteNewsChunk пишет sitemap новостей с ID из диапазона chunk
+func (s *SitemapService) WriteNewsChunk(ctx context.Context, chunk int64, w io.Writer) error {
+	if _, err := io.WriteString(w, sitemapURLSetOpen); err != nil {
+		return err
+	}
+	if err := s.writeNews(ctx, s.newURLWriter(w), chunk*s.config.MaxURLs, (chunk+1)*s.config.MaxURLs); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, sitemapURLSetClose)
+	return err
+}
+
+// WriteIndex пишет индекс sitemap: по одному файлу на каждый непустой диапазон ID категорий и новостей
+func (s *SitemapService) WriteIndex(ctx context.Context, w io.Writer) error {
+	categoryChunks, err := s.categoryRepo.GetSitemapChunks(ctx, s.config.MaxURLs)
+	if err != nil {
+		return err
+	}
+	newsChunks, err := s.newsRepo.GetSitemapChunks(ctx, s.config.MaxURLs)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(w, sitemapIndexOpen); err != nil {
+		return err
+	}
+	for _, chunk := range categoryChunks {
+		if err := writeSitemapEntry(w, "sitemap", s.config.BaseURL+CategoryChunkPath(chunk.Chunk), chunk.LastMod); err != nil {
+			return err
+		}
+	}
+	for _, chunk := range newsChunks {
+		if err := writeSitemapEntry(w, "sitemap", s.config.BaseURL+NewsChunkPath(chunk.Chunk), chunk.LastMod); err != nil {
+			return err
+		}
+	}
+	_, err = io.WriteString(w, sitemapIndexClose)
+	return err
+}
+
+func (s *SitemapService) writeCategories(ctx context.Context, urls *sitemapURLWriter, fromID, toID int64) error {
+	return s.categoryRepo.EachCategoryModified(ctx, fromID, toID, func(id int64, updatedAt time.Time) error {
+		return urls.write(s.config.BaseURL+"/categories/"+strconv.FormatInt(id, 10), updatedAt)
+	})
+}
+
+func (s *SitemapService) writeNews(ctx context.Context, urls *sitemapURLWriter, fromID, toID int64) error {
+	return s.newsRepo.EachNewsModified(ctx, fromID, toID, func(id int64, updatedAt time.Time) error {
+		return urls.write(s.config.BaseURL+"/news/"+strconv.FormatInt(id, 10), updatedAt)
+	})
+}
+
+func (s *SitemapService) newURLWriter(w io.Writer) *sitemapURLWriter {
+	return &sitemapURLWriter{w: w, left: s.config.MaxURLs}
+}
+
+// sitemapURLWriter пишет элементы <url> одного файла и не дает превысить MaxURLs
+type sitemapURLWriter struct {
+	w    io.Writer
+	left int64
+}
+
+// write пишет адрес или возвращает errSitemapFull, если лимит файла исчерпан
+func (u *sitemapURLWriter) write(loc string, lastMod time.Time) error {
+	if u.left <= 0 {
+		return errSitemapFull
+	}
+	u.left--
+	return writeSitemapEntry(u.w, "url", loc, lastMod)
+}
+
+// writeSitemapEntry пишет элемент <url> или <sitemap> с адресом и временем изменения
+func writeSitemapEntry(w io.Writer, element, loc string, lastMod time.Time) error {
+	if _, err := io.WriteString(w, "  <"+element+"><loc>"); err != nil {
+		return err
+	}
+	if err := xml.EscapeText(w, []byte(loc)); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(w, "</loc>"); err != nil {
+		return err
+	}
+	if !lastMod.IsZero() {
+		if _, err := io.WriteString(w, "<lastmod>"+lastMod.UTC().Format(time.RFC3339)+"</lastmod>"); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(w, "</"+element+">\n")
+	return err
+}
@@ -0,0 +1,45 @@
+package services
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteSitemapEntry(t *testing.T) {
+	var buf bytes.Buffer
+
+	lastMod := time.Date(2025, 7, 20, 12, 56, 38, 0, time.FixedZone("MSK", 3*60*60))
+	require.NoError(t, writeSitemapEntry(&buf, "url", "https://news.ru/news/1?a=1&b=2", lastMod))
+	require.NoError(t, writeSitemapEntry(&buf, "sitemap", "https://news.ru/sitemaps/categories-0.xml", time.Time{}))
+
+	expected := "  <url><loc>https://news.ru/news/1?a=1&amp;b=2</loc><lastmod>2025-07-20T09:56:38Z</lastmod></url>\n" +
+		"  <sitemap><loc>https://news.ru/sitemaps/categories-0.xml</loc></sitemap>\n"
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestSitemapChunks(t *testing.T) {
+	s := NewSitemapService(nil, nil, SitemapConfig{MaxURLs: 100000})
+	assert.Equal(t, int64(SitemapMaxURLs), s.config.MaxURLs)
+
+	assert.Equal(t, "/sitemaps/categories-0.xml", CategoryChunkPath(0))
+	assert.Equal(t, "/sitemaps/news-0.xml", NewsChunkPath(0))
+	assert.Equal(t, "/sitemaps/news-12.xml", NewsChunkPath(12))
+}
+
+func TestSitemapURLWriterLimit(t *testing.T) {
+	var buf bytes.Buffer
+	s := NewSitemapService(nil, nil, SitemapConfig{BaseURL: "https://news.ru", MaxURLs: 2})
+	urls := s.newURLWriter(&buf)
+
+	require.NoError(t, urls.write("https://news.ru/categories/1", time.Time{}))
+	require.NoError(t, urls.write("https://news.ru/news/1", time.Time{}))
+	assert.ErrorIs(t, urls.write("https://news.ru/news/2", time.Time{}), errSitemapFull)
+
+	expected := "  <url><loc>https://news.ru/categories/1</loc></url>\n" +
+		"  <url><loc>https://news.ru/news/1</loc></url>\n"
+	assert.Equal(t, expected, buf.String())
+}
@@ -0,0 +1,351 @@
+package services
+
+import (
+	"context"
+	"go_news_server/internal/events"
+	"go_news_server/internal/models"
+	"go_news_server/internal/repository"
+	"go_news_server/pkg/database"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Типы событий потока изменений
+const (
+	StreamNewsCreated     = "news.created"
+	StreamNewsUpdated     = "news.updated"
+	StreamNewsDeleted     = "news.deleted"
+	StreamCategoryCreated = "category.created"
+	StreamCategoryUpdated = "category.updated"
+	StreamCategoryDeleted = "category.deleted"
+	// StreamResync сообщает клиенту, что часть событий потеряна и состояние нужно перезагрузить
+	StreamResync = "resync"
+)
+
+const (
+	// streamCoalesceWindow - окно, в котором изменения одной записи объединяются в одно событие.
+	// Обновление новости меняет несколько строк ("News" и "NewsCategories"), клиенту достаточно одного события.
+	streamCoalesceWindow = 100 * time.Millisecond
+	// streamClientBuffer - размер очереди событий клиента; медленный клиент отключается и переподключается с Last-Event-ID
+	streamClientBuffer = 64
+)
+
+// StreamEvent событие потока изменений новостей и категорий
+type StreamEvent struct {
+	ID         uint64           `json:"-"`
+	Type       string           `json:"type"`
+	Id         int64            `json:"id,omitempty"`
+	News       *models.News     `json:"news,omitempty"`
+	Category   *models.Category `json:"category,omitempty"`
+	OccurredAt time.Time        `json:"occurred_at"`
+}
+
+// Matches проверяет, относится ли событие к одной из категорий фильтра.
+// Пустой фильтр пропускает все события. События удаления новостей и resync передаются всегда:
+// категории удаленной новости уже неизвестны.
+func (e *StreamEvent) Matches(categories map[int64]struct{}) bool {
+	if len(categories) == 0 {
+		return true
+	}
+
+	switch {
+	case e.News != nil:
+		for _, id := range e.News.Categories {
+			if _, ok := categories[id]; ok {
+				return true
+			}
+		}
+		return false
+	case e.Type == StreamCategoryCreated || e.Type == StreamCategoryUpdated || e.Type == StreamCategoryDeleted:
+		_, ok := categories[e.Id]
+		return ok
+	default:
+		return true
+	}
+}
+
+// StreamClient подписка клиента на поток изменений
+type StreamClient struct {
+	ch chan StreamEvent
+}
+
+// C возвращает канал событий. Канал закрывается при остановке сервиса или если клиент не успевает читать события.
+func (c *StreamClient) C() <-chan StreamEvent {
+	return c.ch
+}
+
+// StreamServiceConfig настройки потока изменений
+type StreamServiceConfig struct {
+	// BufferSize - количество последних событий, которые хранятся для возобновления по Last-Event-ID
+	BufferSize int
+}
+
+type streamKey struct {
+	table string
+	id    int64
+}
+
+// StreamService получает изменения из шины событий, дополняет их данными записей
+// и рассылает подключенным клиентам. Последние события хранятся в кольцевом буфере.
+type StreamService struct {
+	newsRepo     *repository.NewsRepository
+	categoryRepo *repository.CategoryRepository
+	bus          *events.Bus
+	logger       *zap.SugaredLogger
+
+	mu      sync.Mutex
+	seq     uint64
+	buffer  []StreamEvent
+	next    int
+	clients map[*StreamClient]struct{}
+	closed  bool
+
+	sub  *events.Subscription
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewStreamService создает сервис потока изменений
+func NewStreamService(
+	newsRepo *repository.NewsRepository,
+	categoryRepo *repository.CategoryRepository,
+	bus *events.Bus,
+	config StreamServiceConfig,
+	logger *zap.SugaredLogger,
+) *StreamService {
+	if config.BufferSize <= 0 {
+		config.BufferSize = 1000
+	}
+
+	return &StreamService{
+		newsRepo:     newsRepo,
+		categoryRepo: categoryRepo,
+		bus:          bus,
+		logger:       logger,
+		buffer:       make([]StreamEvent, 0, config.BufferSize),
+		clients:      make(map[*StreamClient]struct{}),
+		done:         make(chan struct{}),
+	}
+}
+
+// Start подписывается на шину событий
+func (s *StreamService) Start() {
+	s.sub = s.bus.Subscribe(256)
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.run()
+	}()
+}
+
+// Stop отключает всех клиентов и прекращает обработку событий
+func (s *StreamService) Stop() {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.closed = true
+	close(s.done)
+	for c := range s.clients {
+		close(c.ch)
+		delete(s.clients, c)
+	}
+	s.mu.Unlock()
+
+	if s.sub != nil {
+		s.sub.Close()
+	}
+	s.wg.Wait()
+}
+
+// Done возвращает канал, который закрывается при остановке сервиса
+func (s *StreamService) Done() <-chan struct{} {
+	return s.done
+}
+
+// Subscribe подключает клиента. Если передан lastEventID, возвращаются события из буфера после него;
+// resync равен true, если часть событий уже вытеснена из буфера или ID неизвестен (например, после перезапуска).
+func (s *StreamService) Subscribe(lastEventID uint64, resume bool) (client *StreamClient, backlog []StreamEvent, resync bool) {
+	client = &StreamClient{ch: make(chan StreamEvent, streamClientBuffer)}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		close(client.ch)
+		return client, nil, false
+	}
+	s.clients[client] = struct{}{}
+
+	if !resume || lastEventID == s.seq {
+		return client, nil, false
+	}
+
+	oldest := s.seq - uint64(len(s.buffer)) + 1
+	if lastEventID > s.seq || lastEventID+1 < oldest {
+		return client, nil, true
+	}
+
+	for i := 0; i < len(s.buffer); i++ {
+		e := s.buffer[(s.next+i)%len(s.buffer)]
+		if e.ID > lastEventID {
+			backlog = append(backlog, e)
+		}
+	}
+	return client, backlog, false
+}
+
+// Unsubscribe отключает клиента
+func (s *StreamService) Unsubscribe(client *StreamClient) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.clients[client]; ok {
+		close(client.ch)
+		delete(s.clients, client)
+	}
+}
+
+// run объединяет изменения за короткое окно и публикует их клиентам
+func (s *StreamService) run() {
+	pending := make(map[streamKey]string)
+	var order []streamKey
+	var timer <-chan time.Time
+
+	for {
+		select {
+		case e, ok := <-s.sub.C():
+			if !ok {
+				return
+			}
+			if e.Action == events.ActionResync {
+				s.publish(StreamEvent{Type: StreamResync, OccurredAt: e.ReceivedAt})
+				continue
+			}
+
+			key, action := streamKeyOf(e)
+			prev, exists := pending[key]
+			if !exists {
+				order = append(order, key)
+			}
+			pending[key] = mergeActions(prev, action)
+
+			if timer == nil {
+				timer = time.After(streamCoalesceWindow)
+			}
+		case <-timer:
+			for _, key := range order {
+				s.publish(s.buildEvent(key, pending[key]))
+			}
+			pending = make(map[streamKey]string)
+			order = nil
+			timer = nil
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// streamKeyOf определяет запись, к которой относится изменение.
+// Изменение связей новости с категориями считается обновлением новости.
+func streamKeyOf(e events.Event) (streamKey, string) {
+	if e.Table == events.TableNewsCategories {
+		return streamKey{table: events.TableNews, id: e.NewsId}, events.ActionUpdate
+	}
+	return streamKey{table: e.Table, id: e.Id}, e.Action
+}
+
+// mergeActions объединяет последовательные изменения одной записи
+func mergeActions(prev, next string) string {
+	switch {
+	case prev == "":
+		return next
+	case next == events.ActionDelete:
+		return events.ActionDelete
+	case prev == events.ActionDelete && next != events.ActionInsert:
+		// каскадное удаление связей приходит вместе с удалением новости
+		return events.ActionDelete
+	case prev == events.ActionInsert:
+		return events.ActionInsert
+	default:
+		return next
+	}
+}
+
+// buildEvent дополняет изменение актуальными данными записи. Запись читается с primary:
+// отстающая реплика вернула бы только что созданную запись как удаленную.
+func (s *StreamService) buildEvent(key streamKey, action string) StreamEvent {
+	ctx, cancel := context.WithTimeout(database.WithPrimary(context.Background()), 5*time.Second)
+	defer cancel()
+
+	e := StreamEvent{Id: key.id, OccurredAt: time.Now()}
+
+	switch key.table {
+	case events.TableNews:
+		e.Type = StreamNewsDeleted
+		if action != events.ActionDelete {
+			news, err := s.newsRepo.GetNewsByID(ctx, key.id)
+			if err != nil {
+				s.logger.Warnw("Failed to load news for stream event", "id", key.id, "error", err)
+			}
+			if news != nil {
+				e.News = news
+				e.Type = StreamNewsUpdated
+				if action == events.ActionInsert {
+					e.Type = StreamNewsCreated
+				}
+			}
+		}
+	case events.TableCategories:
+		e.Type = StreamCategoryDeleted
+		if action != events.ActionDelete {
+			category, err := s.categoryRepo.GetCategoryByID(ctx, key.id)
+			if err != nil {
+				s.logger.Warnw("Failed to load category for stream event", "id", key.id, "error", err)
+			}
+			if category != nil {
+				e.Category = category
+				e.Type = StreamCategoryUpdated
+				if action == events.ActionInsert {
+					e.Type = StreamCategoryCreated
+				}
+			}
+		}
+	}
+
+	return e
+}
+
+// publish присваивает событию ID, сохраняет его в буфере и рассылает клиентам
+func (s *StreamService) publish(e StreamEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return
+	}
+
+	s.seq++
+	e.ID = s.seq
+
+	if len(s.buffer) < cap(s.buffer) {
+		s.buffer = append(s.buffer, e)
+	} else {
+		s.buffer[s.next] = e
+		s.next = (s.next + 1) % len(s.buffer)
+	}
+
+	for c := range s.clients {
+		select {
+		case c.ch <- e:
+		default:
+			// Клиент не успевает читать: отключаем его, при переподключении он получит пропущенное из буфера
+			close(c.ch)
+			delete(s.clients, c)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"go_news_server/internal/events"
+	"go_news_server/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestStreamServiceResume(t *testing.T) {
+	s := NewStreamService(nil, nil, events.NewBus(), StreamServiceConfig{BufferSize: 3}, zap.NewNop().Sugar())
+	defer s.Stop()
+
+	for i := int64(1); i <= 5; i++ {
+		s.publish(StreamEvent{Type: StreamCategoryUpdated, Id: i})
+	}
+
+	// В буфере остались события 3, 4, 5
+	_, backlog, resync := s.Subscribe(3, true)
+	assert.False(t, resync)
+	if assert.Len(t, backlog, 2) {
+		assert.Equal(t, uint64(4), backlog[0].ID)
+		assert.Equal(t, uint64(5), backlog[1].ID)
+	}
+
+	_, backlog, resync = s.Subscribe(1, true)
+	assert.True(t, resync)
+	assert.Empty(t, backlog)
+
+	// ID из будущего означает перезапуск сервера
+	_, _, resync = s.Subscribe(42, true)
+	assert.True(t, resync)
+
+	client, backlog, resync := s.Subscribe(0, false)
+	assert.False(t, resync)
+	assert.Empty(t, backlog)
+
+	s.publish(StreamEvent{Type: StreamNewsDeleted, Id: 7})
+	e := <-client.C()
+	assert.Equal(t, uint64(6), e.ID)
+	assert.Equal(t, StreamNewsDeleted, e.Type)
+}
+
+func TestStreamEventMatches(t *testing.T) {
+	filter := map[int64]struct{}{2: {}}
+
+	news := StreamEvent{Type: StreamNewsUpdated, Id: 1, News: &models.News{Id: 1, Categories: []int64{1, 2}}}
+	assert.True(t, news.Matches(filter))
+
+	news.News.Categories = []int64{3}
+	assert.False(t, news.Matches(filter))
+	assert.True(t, news.Matches(nil))
+
+	assert.True(t, (&StreamEvent{Type: StreamCategoryDeleted, Id: 2}).Matches(filter))
+	assert.False(t, (&StreamEvent{Type: StreamCategoryCreated, Id: 3}).Matches(filter))
+	assert.True(t, (&StreamEvent{Type: StreamNewsDeleted, Id: 5}).Matches(filter))
+	assert.True(t, (&StreamEvent{Type: StreamResync}).Matches(filter))
+}
+
+func TestMergeActions(t *testing.T) {
+	assert.Equal(t, events.ActionInsert, mergeActions(events.ActionInsert, events.ActionUpdate))
+	assert.Equal(t, events.ActionDelete, mergeActions(events.ActionInsert, events.ActionDelete))
+	assert.Equal(t, events.ActionDelete, mergeActions(events.ActionDelete, events.ActionUpdate))
+	assert.Equal(t, events.ActionUpdate, mergeActions("", events.ActionUpdate))
+}
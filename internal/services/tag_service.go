@@ -0,0 +1,28 @@
+package services
+
+import (
+	"context"
+	"go_news_server/internal/models"
+	"go_news_server/internal/repository"
+)
+
+// maxTagsLimit - наибольшее число тегов в одном ответе
+const maxTagsLimit = 200
+
+type TagService struct {
+	tagRepo *repository.TagRepository
+}
+
+func NewTagService(tagRepo *repository.TagRepository) *TagService {
+	return &TagService{
+		tagRepo: tagRepo,
+	}
+}
+
+// GetTags получает теги с числом новостей; prefix нормализуется так же, как названия тегов
+func (s *TagService) GetTags(ctx context.Context, prefix string, limit int) ([]models.Tag, error) {
+	if limit <= 0 || limit > maxTagsLimit {
+		limit = maxTagsLimit
+	}
+	return s.tagRepo.GetTags(ctx, models.NormalizeTag(prefix), limit)
+}
@@ -0,0 +1,229 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"go_news_server/internal/models"
+	"go_news_server/internal/repository"
+	"go_news_server/pkg/locale"
+	"go_news_server/pkg/sanitize"
+	"strings"
+)
+
+type localeKey struct{}
+
+// WithLocale возвращает контекст с языком ответа, выбранным по запросу
+func WithLocale(ctx context.Context, tag string) context.Context {
+	return context.WithValue(ctx, localeKey{}, tag)
+}
+
+// LocaleFromContext возвращает язык ответа из контекста, "" - язык не выбирался
+func LocaleFromContext(ctx context.Context) string {
+	tag, _ := ctx.Value(localeKey{}).(string)
+	return tag
+}
+
+type TranslationService struct {
+	repo      *repository.TranslationRepository
+	locales   *locale.Locales
+	sanitizer *sanitize.Sanitizer
+}
+
+func NewTranslationService(repo *repository.TranslationRepository, locales *locale.Locales, sanitizer *sanitize.Sanitizer) *TranslationService {
+	return &TranslationService{
+		repo:      repo,
+		locales:   locales,
+		sanitizer: sanitizer,
+	}
+}
+
+// checkLocale нормализует язык перевода: он должен быть поддерживаемым и отличаться от языка исходных записей
+func (s *TranslationService) checkLocale(tag string) (string, error) {
+	tag = locale.Normalize(tag)
+	if !s.locales.IsSupported(tag) {
+		return "", &models.ValidationError{Message: "Unsupported locale, allowed: " + strings.Join(s.locales.Supported, ", ")}
+	}
+	if tag == s.locales.Default {
+		return "", &models.ValidationError{Message: "Locale " + tag + " is the default locale, edit the news or category itself"}
+	}
+	return tag, nil
+}
+
+// PutNewsTranslation создает или заменяет перевод новости. Содержимое обрабатывается так же,
+// как при изменении новости: Markdown преобразуется в HTML и очищается по политике из контекста.
+// Обязательность и длина полей req проверяются по тегам validate до вызова.
+func (s *TranslationService) PutNewsTranslation(ctx context.Context, newsID int64, tag string, req *models.NewsTranslationRequest) (*models.NewsTranslation, error) {
+	tag, err := s.checkLocale(tag)
+	if err != nil {
+		return nil, err
+	}
+
+	news := &models.News{Title: req.Title, Content: req.Content, ContentFormat: req.ContentFormat}
+	if err := prepareNewsContent(s.sanitizer, ContentPolicyFromContext(ctx), news); err != nil {
+		return nil, err
+	}
+
+	translation := &models.NewsTranslation{
+		NewsId:        newsID,
+		Locale:        tag,
+		Title:         news.Title,
+		Content:       news.Content,
+		ContentFormat: news.ContentFormat,
+		ContentSource: news.ContentSource,
+		ContentText:   news.ContentText,
+	}
+	if err := s.repo.PutNewsTranslation(ctx, translation); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, &models.NotFoundError{Message: "News not found"}
+		}
+		return nil, err
+	}
+	return translation, nil
+}
+
+// DeleteNewsTranslation удаляет перевод новости
+func (s *TranslationService) DeleteNewsTranslation(ctx context.Context, newsID int64, tag string) error {
+	err := s.repo.DeleteNewsTranslation(ctx, newsID, locale.Normalize(tag))
+	if errors.Is(err, sql.ErrNoRows) {
+		return &models.NotFoundError{Message: "Translation not found"}
+	}
+	return err
+}
+
+// GetNewsTranslations получает все переводы новости
+func (s *TranslationService) GetNewsTranslations(ctx context.Context, newsID int64) ([]models.NewsTranslation, error) {
+	return s.repo.GetNewsTranslations(ctx, newsID)
+}
+
+// PutCategoryTranslation создает или заменяет перевод категории, поля req проверяются по тегам validate до вызова
+func (s *TranslationService) PutCategoryTranslation(ctx context.Context, categoryID int64, tag string, req *models.CategoryTranslationRequest) (*models.CategoryTranslation, error) {
+	tag, err := s.checkLocale(tag)
+	if err != nil {
+		return nil, err
+	}
+
+	translation := &models.CategoryTranslation{
+		CategoryId:  categoryID,
+		Locale:      tag,
+		Name:        req.Name,
+		Description: req.Description,
+	}
+	if err := s.repo.PutCategoryTranslation(ctx, translation); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, &models.NotFoundError{Message: "Category not found"}
+		}
+		return nil, err
+	}
+	return translation, nil
+}
+
+// DeleteCategoryTranslation удаляет перевод категории
+func (s *TranslationService) DeleteCategoryTranslation(ctx context.Context, categoryID int64, tag string) error {
+	err := s.repo.DeleteCategoryTranslation(ctx, categoryID, locale.Normalize(tag))
+	if errors.Is(err, sql.ErrNoRows) {
+		return &models.NotFoundError{Message: "Translation not found"}
+	}
+	return err
+}
+
+// translatedChain возвращает цепочку языков для языка из контекста без языка по умолчанию
+// (его текст хранится в самих записях). nil - переводы не нужны.
+func (s *TranslationService) translatedChain(ctx context.Context) (string, []string) {
+	tag := LocaleFromContext(ctx)
+	if tag == "" {
+		return "", nil
+	}
+
+	var chain []string
+	for _, item := range s.locales.Chain(tag) {
+		if item == s.locales.Default {
+			break
+		}
+		chain = append(chain, item)
+	}
+	return tag, chain
+}
+
+// TranslateNews заменяет заголовок и содержимое новостей переводом на язык из контекста или первый
+// язык его цепочки, для которого есть перевод, и заполняет Locale. Без языка в контексте ничего не меняет.
+func (s *TranslationService) TranslateNews(ctx context.Context, newsList []models.News) error {
+	tag, chain := s.translatedChain(ctx)
+	if tag == "" {
+		return nil
+	}
+
+	byNews := make(map[int64]map[string]models.NewsTranslation)
+	if len(chain) > 0 {
+		ids := make([]int64, len(newsList))
+		for i := range newsList {
+			ids[i] = newsList[i].Id
+		}
+		translations, err := s.repo.GetNewsTranslationsIn(ctx, ids, chain)
+		if err != nil {
+			return err
+		}
+		for _, t := range translations {
+			if byNews[t.NewsId] == nil {
+				byNews[t.NewsId] = make(map[string]models.NewsTranslation)
+			}
+			byNews[t.NewsId][t.Locale] = t
+		}
+	}
+
+	for i := range newsList {
+		news := &newsList[i]
+		news.Locale = s.locales.Default
+		for _, item := range chain {
+			if t, ok := byNews[news.Id][item]; ok {
+				news.Title = t.Title
+				news.Content = t.Content
+				news.ContentFormat = t.ContentFormat
+				news.ContentSource = t.ContentSource
+				news.ContentText = t.ContentText
+				news.Locale = t.Locale
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// TranslateCategories заменяет название и описание категорий переводом так же, как TranslateNews
+func (s *TranslationService) TranslateCategories(ctx context.Context, categories []*models.Category) error {
+	tag, chain := s.translatedChain(ctx)
+	if tag == "" {
+		return nil
+	}
+
+	byCategory := make(map[int64]map[string]models.CategoryTranslation)
+	if len(chain) > 0 {
+		ids := make([]int64, len(categories))
+		for i, category := range categories {
+			ids[i] = category.Id
+		}
+		translations, err := s.repo.GetCategoryTranslationsIn(ctx, ids, chain)
+		if err != nil {
+			return err
+		}
+		for _, t := range translations {
+			if byCategory[t.CategoryId] == nil {
+				byCategory[t.CategoryId] = make(map[string]models.CategoryTranslation)
+			}
+			byCategory[t.CategoryId][t.Locale] = t
+		}
+	}
+
+	for _, category := range categories {
+		category.Locale = s.locales.Default
+		for _, item := range chain {
+			if t, ok := byCategory[category.Id][item]; ok {
+				category.Name = t.Name
+				category.Description = t.Description
+				category.Locale = t.Locale
+				break
+			}
+		}
+	}
+	return nil
+}
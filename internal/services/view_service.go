@@ -0,0 +1,381 @@
+package services
+
+import (
+	"context"
+	"go_news_server/internal/models"
+	"go_news_server/internal/repository"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// MaxPopularLimit - наибольшее число популярных новостей в ответе
+const MaxPopularLimit = 50
+
+// PopularWindows - периоды популярных новостей (?window=). Часовые счетчики хранятся за самый длинный период.
+var PopularWindows = map[string]time.Duration{
+	"1h":  time.Hour,
+	"24h": 24 * time.Hour,
+	"7d":  7 * 24 * time.Hour,
+	"30d": 30 * 24 * time.Hour,
+}
+
+// viewRetention - срок хранения часовых счетчиков, самый длинный из PopularWindows
+const viewRetention = 30 * 24 * time.Hour
+
+// viewSeenMaxEntries - наибольшее число пар клиент-новость, запоминаемых для отсеивания повторов
+const viewSeenMaxEntries = 1 << 20
+
+// viewPendingMaxEntries - наибольшее число незаписанных счетчиков новость-час. Пока база недоступна,
+// просмотры сверх него отбрасываются, чтобы очередь не росла без ограничений.
+const viewPendingMaxEntries = 1 << 16
+
+// viewKnownMaxEntries - наибольшее число ID новостей, существование которых запомнено
+const viewKnownMaxEntries = 1 << 16
+
+// popularMaxEntries - наибольшее число списков популярных новостей в кэше
+const popularMaxEntries = 1000
+
+// ViewConfig - параметры учета просмотров
+type ViewConfig struct {
+	// DedupWindow - повторные просмотры новости одним клиентом в пределах окна не учитываются
+	DedupWindow time.Duration
+	// FlushInterval - период записи накопленных просмотров в базу; популярные новости пересчитываются после записи
+	FlushInterval time.Duration
+}
+
+type viewKey struct {
+	client uint64
+	newsID int64
+}
+
+type viewBucket struct {
+	newsID int64
+	hour   time.Time
+}
+
+type popularKey struct {
+	window     string
+	categoryID int64
+}
+
+// popularEntry - список популярных новостей, посчитанный в час hour: с началом следующего часа период сдвигается
+type popularEntry struct {
+	items []repository.PopularNews
+	hour  time.Time
+}
+
+// ViewService учитывает просмотры новостей. Просмотры копятся в памяти по новостям и часам
+// и записываются в базу пачкой раз в FlushInterval, а не при каждом запросе.
+type ViewService struct {
+	repo   *repository.ViewRepository
+	config ViewConfig
+	logger *zap.SugaredLogger
+	now    func() time.Time
+
+	mu          sync.Mutex
+	seen        map[viewKey]time.Time
+	pending     map[viewBucket]int64
+	popular     map[popularKey]popularEntry
+	lastCleanup time.Time
+	// known - ID существующих новостей: просмотры других ID не принимаются
+	known map[int64]struct{}
+	// dropped - просмотры, отброшенные из-за переполнения очереди с последней записи, droppedTotal - за все время
+	dropped      int64
+	droppedTotal int64
+
+	// flushMu не дает записям из тикера и Stop пересечься
+	flushMu sync.Mutex
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+func NewViewService(repo *repository.ViewRepository, config ViewConfig, logger *zap.SugaredLogger) *ViewService {
+	if config.DedupWindow <= 0 {
+		config.DedupWindow = 30 * time.Minute
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = 10 * time.Second
+	}
+
+	return &ViewService{
+		repo:    repo,
+		config:  config,
+		logger:  logger,
+		now:     time.Now,
+		seen:    make(map[viewKey]time.Time),
+		pending: make(map[viewBucket]int64),
+		popular: make(map[popularKey]popularEntry),
+		known:   make(map[int64]struct{}),
+	}
+}
+
+// Start запускает периодическую запись просмотров
+func (s *ViewService) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(s.config.FlushInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.Flush(ctx)
+			}
+		}
+	}()
+}
+
+// Stop останавливает периодическую запись и записывает оставшиеся просмотры
+func (s *ViewService) Stop(ctx context.Context) {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+	s.Flush(ctx)
+}
+
+// Record учитывает просмотр новости клиентом client (адрес и User-Agent).
+// Возвращает false, если клиент уже смотрел новость в пределах DedupWindow,
+// и models.NotFoundError, если новости нет.
+func (s *ViewService) Record(ctx context.Context, newsID int64, client string) (bool, error) {
+	if err := s.checkNews(ctx, newsID); err != nil {
+		return false, err
+	}
+	return s.record(newsID, client), nil
+}
+
+// checkNews проверяет, что новость существует. Существующие ID запоминаются,
+// поэтому база запрашивается только при первом просмотре новости.
+func (s *ViewService) checkNews(ctx context.Context, newsID int64) error {
+	s.mu.Lock()
+	_, ok := s.known[newsID]
+	s.mu.Unlock()
+	if ok {
+		return nil
+	}
+
+	exists, err := s.repo.NewsExists(ctx, newsID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return &models.NotFoundError{Message: "News not found"}
+	}
+
+	s.mu.Lock()
+	if len(s.known) >= viewKnownMaxEntries {
+		s.known = make(map[int64]struct{})
+	}
+	s.known[newsID] = struct{}{}
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *ViewService) record(newsID int64, client string) bool {
+	key := viewKey{client: s.clientHash(client), newsID: newsID}
+
+	now := s.now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if expiresAt, ok := s.seen[key]; ok && now.Before(expiresAt) {
+		return false
+	}
+	if len(s.seen) >= viewSeenMaxEntries {
+		s.forgetExpired(now)
+	}
+	s.seen[key] = now.Add(s.config.DedupWindow)
+	s.addPending(viewBucket{newsID: newsID, hour: now.UTC().Truncate(time.Hour)}, 1)
+	return true
+}
+
+// addPending прибавляет просмотры к очереди записи. Новый счетчик сверх viewPendingMaxEntries
+// не создается, просмотры отбрасываются и учитываются в dropped. Вызывается под mu.
+func (s *ViewService) addPending(bucket viewBucket, count int64) {
+	if _, ok := s.pending[bucket]; !ok && len(s.pending) >= viewPendingMaxEntries {
+		s.dropped += count
+		s.droppedTotal += count
+		return
+	}
+	s.pending[bucket] += count
+}
+
+// clientHash сокращает адрес и User-Agent клиента до 8 байт: полные строки в памяти не нужны
+func (s *ViewService) clientHash(client string) uint64 {
+	hash := fnv.New64a()
+	hash.Write([]byte(client))
+	return hash.Sum64()
+}
+
+// forgetExpired удаляет истекшие отметки о просмотрах, а если их нет - все: лишний учтенный просмотр
+// лучше неограниченного роста памяти. Вызывается под mu.
+func (s *ViewService) forgetExpired(now time.Time) {
+	for key, expiresAt := range s.seen {
+		if !now.Before(expiresAt) {
+			delete(s.seen, key)
+		}
+	}
+	if len(s.seen) >= viewSeenMaxEntries {
+		s.seen = make(map[viewKey]time.Time)
+	}
+}
+
+// Flush записывает накопленные просмотры. Если запись не удалась, просмотры возвращаются
+// в очередь и записываются при следующей попытке; не поместившиеся в очередь отбрасываются.
+func (s *ViewService) Flush(ctx context.Context) {
+	s.flushMu.Lock()
+	defer s.flushMu.Unlock()
+
+	now := s.now()
+	s.mu.Lock()
+	pending := s.pending
+	s.pending = make(map[viewBucket]int64)
+	s.forgetExpired(now)
+	s.mu.Unlock()
+
+	defer s.logDropped()
+
+	if len(pending) > 0 {
+		counts := make([]models.NewsViewCount, 0, len(pending))
+		for bucket, count := range pending {
+			counts = append(counts, models.NewsViewCount{NewsId: bucket.newsID, Hour: bucket.hour, Count: count})
+		}
+
+		if err := s.repo.AddViews(ctx, counts); err != nil {
+			s.logger.Errorw("Failed to save news views", "error", err, "buckets", len(counts))
+			s.mu.Lock()
+			for bucket, count := range pending {
+				s.addPending(bucket, count)
+			}
+			s.mu.Unlock()
+			return
+		}
+
+		s.mu.Lock()
+		s.popular = make(map[popularKey]popularEntry)
+		s.mu.Unlock()
+	}
+
+	if now.Sub(s.lastCleanup) >= time.Hour {
+		if err := s.repo.DeleteViewsBefore(ctx, now.Add(-viewRetention)); err != nil {
+			s.logger.Errorw("Failed to delete old news views", "error", err)
+			return
+		}
+		s.lastCleanup = now
+	}
+}
+
+// logDropped сообщает о просмотрах, отброшенных с прошлой записи
+func (s *ViewService) logDropped() {
+	s.mu.Lock()
+	dropped, total := s.dropped, s.droppedTotal
+	s.dropped = 0
+	s.mu.Unlock()
+
+	if dropped > 0 {
+		s.logger.Warnw("News views dropped: pending queue is full", "dropped", dropped, "dropped_total", total, "max_buckets", viewPendingMaxEntries)
+	}
+}
+
+// GetPopular получает до limit самых просматриваемых за window новостей категории categoryID
+// и ее дочерних категорий (0 - всех новостей). Период отсчитывается от начала текущего часа,
+// результат хранится до следующей записи просмотров или начала следующего часа.
+func (s *ViewService) GetPopular(ctx context.Context, window string, categoryID int64, limit int) ([]repository.PopularNews, error) {
+	duration, ok := PopularWindows[window]
+	if !ok {
+		return nil, &models.ValidationError{Message: "Unsupported window: " + window}
+	}
+	if limit <= 0 || limit > MaxPopularLimit {
+		limit = MaxPopularLimit
+	}
+
+	hour := s.now().UTC().Truncate(time.Hour)
+	key := popularKey{window: window, categoryID: categoryID}
+	s.mu.Lock()
+	entry, ok := s.popular[key]
+	s.mu.Unlock()
+
+	if !ok || !entry.hour.Equal(hour) {
+		items, err := s.repo.GetPopularNewsIDs(ctx, hour.Add(-duration), categoryID, MaxPopularLimit)
+		if err != nil {
+			return nil, err
+		}
+		entry = popularEntry{items: items, hour: hour}
+
+		s.mu.Lock()
+		if len(s.popular) >= popularMaxEntries {
+			s.popular = make(map[popularKey]popularEntry)
+		}
+		s.popular[key] = entry
+		s.mu.Unlock()
+	}
+
+	if len(entry.items) > limit {
+		return entry.items[:limit], nil
+	}
+	return entry.items, nil
+}
+
+// GetNewsViews получает счетчики просмотров новости вместе с еще не записанными просмотрами
+func (s *ViewService) GetNewsViews(ctx context.Context, newsID int64) (*models.NewsViews, error) {
+	now := s.now()
+	views, err := s.repo.GetNewsViews(ctx, newsID, now.UTC().Truncate(time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	if views == nil {
+		return nil, &models.NotFoundError{Message: "News not found"}
+	}
+
+	s.mu.Lock()
+	for bucket, count := range s.pending {
+		if bucket.newsID == newsID {
+			views.Pending += count
+		}
+	}
+	s.mu.Unlock()
+
+	views.Total += views.Pending
+	views.Last24h += views.Pending
+	views.Last7d += views.Pending
+	return views, nil
+}
+
+// AddTotals заполняет Views новостей просмотрами за все время вместе с еще не записанными
+func (s *ViewService) AddTotals(ctx context.Context, newsList []models.News) error {
+	if len(newsList) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, len(newsList))
+	listed := make(map[int64]bool, len(newsList))
+	for i := range newsList {
+		ids[i] = newsList[i].Id
+		listed[newsList[i].Id] = true
+	}
+	totals, err := s.repo.GetViewTotals(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	for bucket, count := range s.pending {
+		if listed[bucket.newsID] {
+			totals[bucket.newsID] += count
+		}
+	}
+	s.mu.Unlock()
+
+	for i := range newsList {
+		newsList[i].Views = totals[newsList[i].Id]
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestViewServiceRecordDedup(t *testing.T) {
+	s := NewViewService(nil, ViewConfig{DedupWindow: 30 * time.Minute}, nil)
+	now := time.Date(2025, 7, 20, 12, 50, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	assert.True(t, s.record(1, "10.0.0.1\x00curl"))
+	assert.False(t, s.record(1, "10.0.0.1\x00curl"))
+	assert.True(t, s.record(1, "10.0.0.2\x00curl"))
+	assert.True(t, s.record(2, "10.0.0.1\x00curl"))
+
+	// после окна просмотр учитывается снова и попадает в следующий час
+	now = now.Add(31 * time.Minute)
+	assert.True(t, s.record(1, "10.0.0.1\x00curl"))
+
+	hour := time.Date(2025, 7, 20, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, map[viewBucket]int64{
+		{newsID: 1, hour: hour}:                2,
+		{newsID: 2, hour: hour}:                1,
+		{newsID: 1, hour: hour.Add(time.Hour)}: 1,
+	}, s.pending)
+}
+
+func TestViewServiceForgetExpired(t *testing.T) {
+	s := NewViewService(nil, ViewConfig{DedupWindow: time.Minute}, nil)
+	now := time.Date(2025, 7, 20, 12, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	s.record(1, "a")
+	now = now.Add(30 * time.Second)
+	s.record(2, "a")
+	now = now.Add(45 * time.Second)
+
+	s.forgetExpired(now)
+	assert.Len(t, s.seen, 1)
+	assert.Contains(t, s.seen, viewKey{client: s.clientHash("a"), newsID: 2})
+}
+
+func TestViewServicePendingLimit(t *testing.T) {
+	s := NewViewService(nil, ViewConfig{}, nil)
+	now := time.Date(2025, 7, 20, 12, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	for i := 0; i < viewPendingMaxEntries; i++ {
+		s.pending[viewBucket{newsID: int64(i + 1), hour: now}] = 1
+	}
+
+	// существующий счетчик растет, новый сверх предела не создается
+	assert.True(t, s.record(1, "a"))
+	assert.True(t, s.record(viewPendingMaxEntries+1, "a"))
+	assert.Len(t, s.pending, viewPendingMaxEntries)
+	assert.Equal(t, int64(2), s.pending[viewBucket{newsID: 1, hour: now}])
+	assert.Equal(t, int64(1), s.dropped)
+}
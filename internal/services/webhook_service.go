@@ -0,0 +1,186 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"go_news_server/internal/models"
+	"go_news_server/internal/repository"
+	"net/url"
+	"slices"
+)
+
+type WebhookService struct {
+	webhookRepo *repository.WebhookRepository
+}
+
+func NewWebhookService(webhookRepo *repository.WebhookRepository) *WebhookService {
+	return &WebhookService{
+		webhookRepo: webhookRepo,
+	}
+}
+
+// CreateWebhook создает подписку. Если секрет не задан, он генерируется и возвращается только в ответе на создание.
+func (s *WebhookService) CreateWebhook(ctx context.Context, req *models.WebhookRequest) (*models.Webhook, error) {
+	if err := validateWebhookRequest(req); err != nil {
+		return nil, err
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		var err error
+		if secret, err = generateWebhookSecret(); err != nil {
+			return nil, err
+		}
+	}
+
+	webhook := &models.Webhook{
+		Url:         req.Url,
+		Secret:      secret,
+		EventTypes:  req.EventTypes,
+		CategoryIds: req.CategoryIds,
+		Active:      req.Active == nil || *req.Active,
+	}
+
+	if err := s.webhookRepo.CreateWebhook(ctx, webhook); err != nil {
+		return nil, err
+	}
+
+	return webhook, nil
+}
+
+// GetWebhookByID получает подписку по ID без секрета
+func (s *WebhookService) GetWebhookByID(ctx context.Context, id int64) (*models.Webhook, error) {
+	webhook, err := s.webhookRepo.GetWebhookByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if webhook == nil {
+		return nil, &models.NotFoundError{Message: "Webhook not found"}
+	}
+
+	webhook.Secret = ""
+	return webhook, nil
+}
+
+// GetAllWebhooks получает все подписки без секретов
+func (s *WebhookService) GetAllWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	webhooks, err := s.webhookRepo.GetAllWebhooks(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range webhooks {
+		webhooks[i].Secret = ""
+	}
+	return webhooks, nil
+}
+
+// UpdateWebhook обновляет подписку. Пустой секрет оставляет прежний.
+func (s *WebhookService) UpdateWebhook(ctx context.Context, id int64, req *models.WebhookRequest) (*models.Webhook, error) {
+	if err := validateWebhookRequest(req); err != nil {
+		return nil, err
+	}
+
+	webhook, err := s.webhookRepo.GetWebhookByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if webhook == nil {
+		return nil, &models.NotFoundError{Message: "Webhook not found"}
+	}
+
+	webhook.Url = req.Url
+	webhook.EventTypes = req.EventTypes
+	webhook.CategoryIds = req.CategoryIds
+	if req.Secret != "" {
+		webhook.Secret = req.Secret
+	}
+	if req.Active != nil {
+		webhook.Active = *req.Active
+	}
+
+	err = s.webhookRepo.UpdateWebhook(ctx, webhook)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &models.NotFoundError{Message: "Webhook not found"}
+		}
+		return nil, err
+	}
+
+	webhook.Secret = ""
+	return webhook, nil
+}
+
+// DeleteWebhook удаляет подписку
+func (s *WebhookService) DeleteWebhook(ctx context.Context, id int64) error {
+	err := s.webhookRepo.DeleteWebhook(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return &models.NotFoundError{Message: "Webhook not found"}
+		}
+		return err
+	}
+
+	return nil
+}
+
+// GetDeliveries получает журнал доставок подписки
+func (s *WebhookService) GetDeliveries(ctx context.Context, webhookID int64, status string, limit, offset int) ([]models.WebhookDelivery, int64, error) {
+	if status != "" && status != models.DeliveryPending && status != models.DeliverySucceeded && status != models.DeliveryDead {
+		return nil, 0, &models.ValidationError{Message: "Invalid delivery status"}
+	}
+
+	if _, err := s.GetWebhookByID(ctx, webhookID); err != nil {
+		return nil, 0, err
+	}
+
+	return s.webhookRepo.GetDeliveries(ctx, webhookID, status, limit, offset)
+}
+
+// RetryDelivery возвращает доставку из dead letter в очередь
+func (s *WebhookService) RetryDelivery(ctx context.Context, id int64) (*models.WebhookDelivery, error) {
+	delivery, err := s.webhookRepo.RequeueDelivery(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &models.NotFoundError{Message: "Dead delivery not found"}
+		}
+		return nil, err
+	}
+
+	return delivery, nil
+}
+
+// validateWebhookRequest проверяет адрес и фильтры подписки
+func validateWebhookRequest(req *models.WebhookRequest) error {
+	u, err := url.Parse(req.Url)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return &models.ValidationError{Message: "Webhook url must be an absolute http(s) URL"}
+	}
+
+	for _, eventType := range req.EventTypes {
+		if !slices.Contains(models.WebhookEventTypes, eventType) {
+			return &models.ValidationError{Message: "Unknown event type: " + eventType}
+		}
+	}
+
+	for _, id := range req.CategoryIds {
+		if id <= 0 {
+			return &models.ValidationError{Message: "Invalid category ID in category_ids"}
+		}
+	}
+
+	return nil
+}
+
+// generateWebhookSecret генерирует случайный секрет для подписи
+func generateWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,270 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"go_news_server/internal/models"
+	"go_news_server/internal/repository"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Заголовки запросов вебхуков
+const (
+	WebhookHeaderId        = "X-Webhook-Id"
+	WebhookHeaderEvent     = "X-Webhook-Event"
+	WebhookHeaderTimestamp = "X-Webhook-Timestamp"
+	WebhookHeaderSignature = "X-Webhook-Signature"
+)
+
+// WebhookSignature вычисляет подпись запроса: HMAC-SHA256 от "<timestamp>.<тело>" в hex с префиксом sha256=.
+// Временная метка входит в подпись, чтобы получатель мог отклонять повторно отправленные старые запросы.
+func WebhookSignature(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookEnvelope тело запроса вебхука. Id совпадает для всех попыток доставки события.
+type webhookEnvelope struct {
+	Id        int64           `json:"id"`
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// WebhookSender отправляет подписанные запросы вебхуков
+type WebhookSender struct {
+	Client *http.Client
+}
+
+// NewWebhookSender создает отправителя с таймаутом запроса. Редиректы не выполняются и считаются ошибкой доставки.
+func NewWebhookSender(timeout time.Duration) *WebhookSender {
+	return &WebhookSender{
+		Client: &http.Client{
+			Timeout: timeout,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// Send отправляет событие подписчику. Успешной считается доставка с ответом 2xx.
+// Возвращает код ответа (0, если ответ не получен).
+func (s *WebhookSender) Send(ctx context.Context, task models.WebhookTask) (int, error) {
+	body, err := json.Marshal(webhookEnvelope{
+		Id:        task.Delivery.OutboxId,
+		Type:      task.Delivery.EventType,
+		CreatedAt: task.CreatedAt,
+		Data:      task.Payload,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, task.Url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "go_news_server-webhooks")
+	req.Header.Set(WebhookHeaderId, strconv.FormatInt(task.Delivery.OutboxId, 10))
+	req.Header.Set(WebhookHeaderEvent, task.Delivery.EventType)
+	req.Header.Set(WebhookHeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(WebhookHeaderSignature, WebhookSignature(task.Secret, timestamp, body))
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	// дочитываем ответ, чтобы соединение вернулось в пул
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// WebhookBackoff возвращает задержку перед следующей попыткой: base * 2^(attempt-1), но не больше max
+func WebhookBackoff(attempt int, base, max time.Duration) time.Duration {
+	d := base
+	for i := 1; i < attempt && d < max; i++ {
+		d *= 2
+	}
+	if d > max {
+		d = max
+	}
+	return d
+}
+
+// WebhookWorkerConfig настройки доставки вебхуков
+type WebhookWorkerConfig struct {
+	// PollInterval - период проверки outbox и очереди доставок
+	PollInterval time.Duration
+	// BatchSize - количество событий и доставок, обрабатываемых за один проход
+	BatchSize int
+	// MaxAttempts - количество попыток, после которого доставка переносится в dead letter
+	MaxAttempts int
+	// BackoffBase и BackoffMax - границы экспоненциальной задержки между попытками
+	BackoffBase time.Duration
+	BackoffMax  time.Duration
+	// Timeout - таймаут одного запроса к подписчику
+	Timeout time.Duration
+	// Concurrency - количество одновременных запросов
+	Concurrency int
+}
+
+// WebhookWorker раскладывает события outbox по подпискам и доставляет их подписчикам
+type WebhookWorker struct {
+	repo   *repository.WebhookRepository
+	sender *WebhookSender
+	config WebhookWorkerConfig
+	logger *zap.SugaredLogger
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewWebhookWorker создает обработчик доставки вебхуков
+func NewWebhookWorker(repo *repository.WebhookRepository, config WebhookWorkerConfig, logger *zap.SugaredLogger) *WebhookWorker {
+	if config.PollInterval <= 0 {
+		config.PollInterval = time.Second
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 100
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = 8
+	}
+	if config.BackoffBase <= 0 {
+		config.BackoffBase = 10 * time.Second
+	}
+	if config.BackoffMax <= 0 {
+		config.BackoffMax = time.Hour
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 10 * time.Second
+	}
+	if config.Concurrency <= 0 {
+		config.Concurrency = 4
+	}
+
+	return &WebhookWorker{
+		repo:   repo,
+		sender: NewWebhookSender(config.Timeout),
+		config: config,
+		logger: logger,
+	}
+}
+
+// Start запускает фоновую обработку
+func (w *WebhookWorker) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	w.cancel = cancel
+
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+
+		ticker := time.NewTicker(w.config.PollInterval)
+		defer ticker.Stop()
+
+		for {
+			w.process(ctx)
+
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Stop останавливает обработку и ждет завершения текущих запросов.
+// Прерванные доставки будут повторены после истечения lease.
+func (w *WebhookWorker) Stop() {
+	if w.cancel == nil {
+		return
+	}
+	w.cancel()
+	w.wg.Wait()
+}
+
+func (w *WebhookWorker) process(ctx context.Context) {
+	if _, err := w.repo.DispatchOutbox(ctx, w.config.BatchSize); err != nil {
+		if ctx.Err() == nil {
+			w.logger.Errorw("Failed to dispatch webhook outbox", "error", err)
+		}
+		return
+	}
+
+	// lease с запасом покрывает таймаут запроса
+	tasks, err := w.repo.ClaimDeliveries(ctx, w.config.BatchSize, 2*w.config.Timeout+time.Minute)
+	if err != nil {
+		if ctx.Err() == nil {
+			w.logger.Errorw("Failed to claim webhook deliveries", "error", err)
+		}
+		return
+	}
+
+	sem := make(chan struct{}, w.config.Concurrency)
+	var wg sync.WaitGroup
+	for _, task := range tasks {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(task models.WebhookTask) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			w.deliver(ctx, task)
+		}(task)
+	}
+	wg.Wait()
+}
+
+func (w *WebhookWorker) deliver(ctx context.Context, task models.WebhookTask) {
+	statusCode, err := w.sender.Send(ctx, task)
+	if ctx.Err() != nil {
+		// остановка сервера: результат не сохраняем, доставка повторится после lease
+		return
+	}
+
+	status, lastError, retryAfter := models.DeliverySucceeded, "", time.Duration(0)
+	if err != nil {
+		lastError = err.Error()
+		if task.Delivery.Attempts >= w.config.MaxAttempts {
+			status = models.DeliveryDead
+			w.logger.Warnw("Webhook delivery moved to dead letter",
+				"delivery_id", task.Delivery.Id, "webhook_id", task.Delivery.WebhookId, "attempts", task.Delivery.Attempts, "error", err)
+		} else {
+			status = models.DeliveryPending
+			retryAfter = WebhookBackoff(task.Delivery.Attempts, w.config.BackoffBase, w.config.BackoffMax)
+			// разброс, чтобы повторы к одному подписчику не приходили одновременно
+			retryAfter += rand.N(retryAfter/5 + 1)
+		}
+	}
+
+	if err := w.repo.SaveDeliveryResult(ctx, task.Delivery.Id, status, statusCode, lastError, retryAfter); err != nil {
+		w.logger.Errorw("Failed to save webhook delivery result", "delivery_id", task.Delivery.Id, "error", err)
+	}
+}
@@ -0,0 +1,71 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"go_news_server/internal/models"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookSenderSend(t *testing.T) {
+	var received *http.Request
+	var body []byte
+	status := http.StatusOK
+
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(status)
+	}))
+	defer receiver.Close()
+
+	task := models.WebhookTask{
+		Delivery:  models.WebhookDelivery{Id: 3, OutboxId: 42, EventType: models.EventNewsUpdated},
+		Url:       receiver.URL,
+		Secret:    "s3cr3t",
+		Payload:   json.RawMessage(`{"id":1,"title":"Title"}`),
+		CreatedAt: time.Date(2025, 7, 20, 9, 56, 38, 0, time.UTC),
+	}
+	sender := NewWebhookSender(time.Second)
+
+	code, err := sender.Send(context.Background(), task)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, code)
+
+	// Получатель проверяет подпись так же, как описано в документации
+	timestamp, err := strconv.ParseInt(received.Header.Get(WebhookHeaderTimestamp), 10, 64)
+	require.NoError(t, err)
+	assert.Equal(t, WebhookSignature("s3cr3t", timestamp, body), received.Header.Get(WebhookHeaderSignature))
+	assert.Equal(t, "42", received.Header.Get(WebhookHeaderId))
+	assert.Equal(t, models.EventNewsUpdated, received.Header.Get(WebhookHeaderEvent))
+	assert.JSONEq(t, `{"id":42,"type":"news.updated","created_at":"2025-07-20T09:56:38Z","data":{"id":1,"title":"Title"}}`, string(body))
+
+	status = http.StatusInternalServerError
+	code, err = sender.Send(context.Background(), task)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusInternalServerError, code)
+
+	// Редиректы не выполняются
+	status = http.StatusFound
+	code, err = sender.Send(context.Background(), task)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusFound, code)
+}
+
+func TestWebhookBackoff(t *testing.T) {
+	base, max := 10*time.Second, time.Minute
+
+	assert.Equal(t, 10*time.Second, WebhookBackoff(1, base, max))
+	assert.Equal(t, 20*time.Second, WebhookBackoff(2, base, max))
+	assert.Equal(t, 40*time.Second, WebhookBackoff(3, base, max))
+	assert.Equal(t, time.Minute, WebhookBackoff(4, base, max))
+	assert.Equal(t, time.Minute, WebhookBackoff(100, base, max))
+}
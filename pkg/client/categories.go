@@ -0,0 +1,114 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// ListCategories возвращает страницу категорий с общим числом категорий в Total
+// GET /api/v1/categories
+func (c *Client) ListCategories(ctx context.Context, opts ListOptions) (*Page[Category], error) {
+	query := url.Values{}
+	setInt(query, "limit", int64(opts.Limit))
+	setInt(query, "offset", int64(opts.Offset))
+
+	var resp response[[]Category]
+	if err := c.do(ctx, http.MethodGet, "/api/v1/categories", query, nil, &resp); err != nil {
+		return nil, err
+	}
+	return newPage(resp), nil
+}
+
+// IterateCategories перебирает все категории по смещению, начиная с opts.Offset
+func (c *Client) IterateCategories(ctx context.Context, opts ListOptions) *Iterator[Category] {
+	offset := opts.Offset
+	return newIterator(ctx, opts.Limit, func(ctx context.Context, limit int) ([]Category, bool, error) {
+		page, err := c.ListCategories(ctx, ListOptions{Limit: limit, Offset: offset})
+		if err != nil {
+			return nil, false, err
+		}
+		offset += len(page.Items)
+		// неполная страница - последняя
+		return page.Items, len(page.Items) < limit || page.Total != nil && int64(offset) >= *page.Total, nil
+	})
+}
+
+// GetCategory возвращает категорию по ID
+// GET /api/v1/categories/:id
+func (c *Client) GetCategory(ctx context.Context, id int64) (*Category, error) {
+	return c.category(ctx, http.MethodGet, categoryPath(id), nil)
+}
+
+// GetCategoryBySlug возвращает категорию по текущему или прежнему slug
+// GET /api/v1/categories/by-slug/:slug
+func (c *Client) GetCategoryBySlug(ctx context.Context, slug string) (*Category, error) {
+	return c.category(ctx, http.MethodGet, "/api/v1/categories/by-slug/"+url.PathEscape(slug), nil)
+}
+
+// GetCategoryTree возвращает дерево категорий
+// GET /api/v1/categories/tree
+func (c *Client) GetCategoryTree(ctx context.Context) ([]CategoryNode, error) {
+	var resp response[[]CategoryNode]
+	if err := c.do(ctx, http.MethodGet, "/api/v1/categories/tree", nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+// GetCategoryDescendants возвращает всех потомков категории
+// GET /api/v1/categories/:id/descendants
+func (c *Client) GetCategoryDescendants(ctx context.Context, id int64) ([]Category, error) {
+	return c.categories(ctx, categoryPath(id)+"/descendants")
+}
+
+// GetNewsCategories возвращает категории новости
+// GET /api/v1/news/:id/categories
+func (c *Client) GetNewsCategories(ctx context.Context, newsID int64) ([]Category, error) {
+	return c.categories(ctx, newsPath(newsID)+"/categories")
+}
+
+// CreateCategory создает категорию. Создание не повторяется при 5xx: категория могла быть создана.
+// POST /api/v1/categories
+func (c *Client) CreateCategory(ctx context.Context, input CategoryInput) (*Category, error) {
+	return c.category(ctx, http.MethodPost, "/api/v1/categories", input)
+}
+
+// UpdateCategory изменяет название, описание и родителя категории
+// PUT /api/v1/categories/:id
+func (c *Client) UpdateCategory(ctx context.Context, id int64, input CategoryInput) (*Category, error) {
+	return c.category(ctx, http.MethodPut, categoryPath(id), input)
+}
+
+// MoveCategory переносит категорию в дереве и/или меняет ее позицию
+// PUT /api/v1/categories/:id/move
+func (c *Client) MoveCategory(ctx context.Context, id int64, move CategoryMove) (*Category, error) {
+	return c.category(ctx, http.MethodPut, categoryPath(id)+"/move", move)
+}
+
+// DeleteCategory удаляет категорию
+// DELETE /api/v1/categories/:id
+func (c *Client) DeleteCategory(ctx context.Context, id int64) error {
+	return c.do(ctx, http.MethodDelete, categoryPath(id), nil, nil, nil)
+}
+
+func (c *Client) category(ctx context.Context, method, path string, body any) (*Category, error) {
+	var resp response[Category]
+	if err := c.do(ctx, method, path, nil, body, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Data, nil
+}
+
+func (c *Client) categories(ctx context.Context, path string) ([]Category, error) {
+	var resp response[[]Category]
+	if err := c.do(ctx, http.MethodGet, path, nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+func categoryPath(id int64) string {
+	return "/api/v1/categories/" + strconv.FormatInt(id, 10)
+}
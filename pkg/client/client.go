@@ -0,0 +1,278 @@
+// Package client - типизированный клиент API /api/v1 сервера новостей для других сервисов на Go.
+//
+// Клиент повторяет запросы с экспоненциальной задержкой при ответах 429 и 5xx и при сетевых ошибках,
+// а ответы об ошибках (RFC 7807) возвращает как *Error:
+//
+//	c := client.New("https://news.example.com", client.WithAPIKey(key))
+//	news, err := c.GetNewsBySlug(ctx, "novost")
+//	if errors.Is(err, client.ErrNotFound) {
+//		...
+//	}
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Значения по умолчанию для повторов запросов
+const (
+	DefaultMaxRetries  = 3
+	DefaultBackoffBase = 200 * time.Millisecond
+	DefaultBackoffMax  = 5 * time.Second
+)
+
+// TokenSource возвращает токен для заголовка Authorization: Bearer перед каждым запросом
+type TokenSource func(ctx context.Context) (string, error)
+
+// Client - клиент API. Безопасен для одновременного использования из нескольких горутин.
+type Client struct {
+	baseURL     string
+	httpClient  *http.Client
+	token       TokenSource
+	language    string
+	userAgent   string
+	maxRetries  int
+	backoffBase time.Duration
+	backoffMax  time.Duration
+}
+
+// Option настраивает клиент
+type Option func(*Client)
+
+// New создает клиент сервера с адресом baseURL, например https://news.example.com
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:     strings.TrimRight(baseURL, "/"),
+		httpClient:  http.DefaultClient,
+		userAgent:   "go_news_server-client",
+		maxRetries:  DefaultMaxRetries,
+		backoffBase: DefaultBackoffBase,
+		backoffMax:  DefaultBackoffMax,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// WithHTTPClient задает HTTP клиент, например с таймаутом или своим Transport
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithAPIKey задает ключ закрытых маршрутов (SECRET_KEY сервера)
+func WithAPIKey(key string) Option {
+	return WithBearerToken(func(context.Context) (string, error) {
+		return key, nil
+	})
+}
+
+// WithBearerToken задает источник токена, который запрашивается перед каждой попыткой запроса,
+// например для токенов с ограниченным сроком действия
+func WithBearerToken(source TokenSource) Option {
+	return func(c *Client) {
+		c.token = source
+	}
+}
+
+// WithLanguage задает язык ответов (Accept-Language)
+func WithLanguage(tag string) Option {
+	return func(c *Client) {
+		c.language = tag
+	}
+}
+
+// WithUserAgent задает заголовок User-Agent
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithRetries задает число повторов после первой попытки (0 - без повторов) и границы задержки между ними.
+// Задержка удваивается с каждой попыткой и не превышает backoffMax; Retry-After ответа имеет приоритет.
+func WithRetries(maxRetries int, backoffBase, backoffMax time.Duration) Option {
+	return func(c *Client) {
+		c.maxRetries = maxRetries
+		c.backoffBase = backoffBase
+		c.backoffMax = backoffMax
+	}
+}
+
+// do выполняет запрос с повторами и разбирает ответ JSON в out (nil - тело ответа не нужно)
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out any) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return fmt.Errorf("news api: marshal request: %w", err)
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		// ошибка подготовки запроса, в том числе TokenSource, не исправится повтором
+		req, err := c.newRequest(ctx, method, path, query, payload)
+		if err != nil {
+			return err
+		}
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			if ctx.Err() != nil || attempt >= c.maxRetries || !idempotent(method) {
+				return err
+			}
+			if err := c.wait(ctx, c.backoff(attempt)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if attempt < c.maxRetries && retryable(method, resp.StatusCode) {
+			delay := retryAfter(resp.Header)
+			if delay <= 0 {
+				delay = c.backoff(attempt)
+			}
+			drain(resp)
+			if err := c.wait(ctx, delay); err != nil {
+				return err
+			}
+			continue
+		}
+
+		return decode(resp, out)
+	}
+}
+
+// newRequest создает запрос с заголовками клиента и токеном из TokenSource
+func (c *Client) newRequest(ctx context.Context, method, path string, query url.Values, payload []byte) (*http.Request, error) {
+	target := c.baseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+	if c.language != "" {
+		req.Header.Set("Accept-Language", c.language)
+	}
+	if c.token != nil {
+		token, err := c.token(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("news api: token: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return req, nil
+}
+
+// backoff - задержка перед повтором attempt (с 0) с разбросом до половины задержки
+func (c *Client) backoff(attempt int) time.Duration {
+	delay := c.backoffBase << attempt
+	if delay <= 0 || delay > c.backoffMax {
+		delay = c.backoffMax
+	}
+	if delay <= 1 {
+		return delay
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+func (c *Client) wait(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// idempotent - повтор запроса не меняет результат. Все изменения API, кроме создания (POST), идемпотентны.
+func idempotent(method string) bool {
+	return method != http.MethodPost
+}
+
+// retryable - ответ можно повторить: 429 означает, что запрос не выполнялся, 5xx повторяются
+// только для идемпотентных запросов
+func retryable(method string, status int) bool {
+	if status == http.StatusTooManyRequests {
+		return true
+	}
+	return status >= http.StatusInternalServerError && status != http.StatusNotImplemented && idempotent(method)
+}
+
+// retryAfter разбирает Retry-After в секундах или в виде даты, 0 - заголовка нет
+func retryAfter(header http.Header) time.Duration {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return time.Until(date)
+	}
+	return 0
+}
+
+func decode(resp *http.Response, out any) error {
+	defer drain(resp)
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return newError(resp)
+	}
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("news api: decode response: %w", err)
+	}
+	return nil
+}
+
+// drain дочитывает и закрывает тело ответа, чтобы соединение вернулось в пул
+func drain(resp *http.Response) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+	_ = resp.Body.Close()
+}
+
+func setInt(query url.Values, name string, value int64) {
+	if value != 0 {
+		query.Set(name, strconv.FormatInt(value, 10))
+	}
+}
+
+func setString(query url.Values, name, value string) {
+	if value != "" {
+		query.Set(name, value)
+	}
+}
@@ -0,0 +1,194 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	v1 "go_news_server/internal/api/v1"
+	"go_news_server/internal/api/v1/v1test"
+	"go_news_server/internal/handlers"
+	"go_news_server/internal/models"
+	"go_news_server/internal/routes"
+	"go_news_server/pkg/client"
+	"go_news_server/pkg/config"
+	"go_news_server/pkg/locale"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// appTransport передает запросы клиента приложению Fiber через app.Test.
+// Первые fail запросов получают ответ status без обращения к приложению.
+type appTransport struct {
+	app        *fiber.App
+	calls      atomic.Int32
+	fail       int32
+	status     int
+	retryAfter string
+}
+
+func (t *appTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.calls.Add(1) <= t.fail {
+		header := http.Header{}
+		if t.retryAfter != "" {
+			header.Set("Retry-After", t.retryAfter)
+		}
+		return &http.Response{StatusCode: t.status, Header: header, Body: http.NoBody, Request: req}, nil
+	}
+	return t.app.Test(req, -1)
+}
+
+const secretKey = "secret"
+
+func newTestClient(t *testing.T, news *v1test.NewsService, categories *v1test.CategoryService, opts ...client.Option) (*client.Client, *appTransport) {
+	locales, err := locale.New([]string{"ru"}, "")
+	require.NoError(t, err)
+
+	app := fiber.New(fiber.Config{ErrorHandler: handlers.ErrorHandler(zap.NewNop().Sugar())})
+	app.Use(requestid.New(requestid.Config{ContextKey: handlers.RequestIDKey}))
+	routes.APIRoutes(app, &v1.Handler{News: news, Categories: categories}, locales, &config.Config{SecretKey: secretKey})
+
+	transport := &appTransport{app: app}
+	opts = append([]client.Option{
+		client.WithHTTPClient(&http.Client{Transport: transport}),
+		client.WithRetries(2, time.Millisecond, time.Millisecond),
+	}, opts...)
+	return client.New("http://news.test", opts...), transport
+}
+
+func TestIterateNews(t *testing.T) {
+	news := new(v1test.NewsService)
+	// сервер запрашивает на одну новость больше, чтобы узнать о следующей странице
+	news.On("GetNewsList", models.NewsFilter{Tag: "go"}, 3, 0).Return([]models.News{{Id: 1}, {Id: 2}, {Id: 3}}, nil)
+	news.On("GetNewsList", models.NewsFilter{Tag: "go", AfterID: 2}, 3, 0).Return([]models.News{{Id: 3}}, nil)
+	c, _ := newTestClient(t, news, nil)
+
+	it := c.IterateNews(context.Background(), client.NewsListOptions{Limit: 2, Tag: "go"})
+	var ids []int64
+	for it.Next() {
+		ids = append(ids, it.Value().ID)
+	}
+
+	assert.NoError(t, it.Err())
+	assert.Equal(t, []int64{1, 2, 3}, ids)
+	assert.Equal(t, "3", it.Cursor())
+	news.AssertExpectations(t)
+}
+
+func TestIterateCategoriesStopsAtTotal(t *testing.T) {
+	categories := new(v1test.CategoryService)
+	categories.On("GetAllCategories", 2, 0).Return([]models.Category{{Id: 1}, {Id: 2}}, int64(2), nil)
+	c, _ := newTestClient(t, nil, categories)
+
+	it := c.IterateCategories(context.Background(), client.ListOptions{Limit: 2})
+	count := 0
+	for it.Next() {
+		count++
+	}
+
+	assert.NoError(t, it.Err())
+	assert.Equal(t, 2, count)
+	categories.AssertExpectations(t)
+}
+
+func TestGetNewsBySlugFollowsRedirect(t *testing.T) {
+	news := new(v1test.NewsService)
+	news.On("GetNewsBySlug", "staraya").Return(nil, "novaya", nil)
+	news.On("GetNewsBySlug", "novaya").Return(&models.News{Id: 7, Slug: "novaya", ContentFormat: "html"}, "", nil)
+	c, _ := newTestClient(t, news, nil)
+
+	got, err := c.GetNewsBySlug(context.Background(), "staraya")
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), got.ID)
+	assert.Equal(t, []int64{}, got.Categories)
+}
+
+func TestUpdatePrivateNewsAuth(t *testing.T) {
+	news := new(v1test.NewsService)
+	news.On("UpdateNews", &models.News{Id: 5, Title: "Заголовок"}, []int64{}).Return(nil)
+	update := client.NewsUpdate{Title: client.String("Заголовок"), Categories: []int64{}}
+
+	c, _ := newTestClient(t, news, nil)
+	err := c.UpdatePrivateNews(context.Background(), 5, update)
+	assert.ErrorIs(t, err, client.ErrUnauthorized)
+
+	c, _ = newTestClient(t, news, nil, client.WithAPIKey(secretKey))
+	assert.NoError(t, c.UpdatePrivateNews(context.Background(), 5, update))
+	news.AssertExpectations(t)
+}
+
+func TestValidationError(t *testing.T) {
+	c, transport := newTestClient(t, nil, new(v1test.CategoryService))
+
+	_, err := c.CreateCategory(context.Background(), client.CategoryInput{Name: " "})
+
+	var apiErr *client.Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.ErrorIs(t, err, client.ErrValidation)
+	assert.Equal(t, http.StatusUnprocessableEntity, apiErr.Status)
+	assert.Equal(t, []client.FieldError{{Field: "name", Code: "required", Message: "name is required"}}, apiErr.Errors)
+	assert.NotEmpty(t, apiErr.RequestID)
+	assert.Equal(t, int32(1), transport.calls.Load())
+}
+
+func TestRetries(t *testing.T) {
+	categories := new(v1test.CategoryService)
+	categories.On("GetCategoryByID", int64(3)).Return(nil, errors.New("pq: connection reset")).Once()
+	categories.On("GetCategoryByID", int64(3)).Return(&models.Category{Id: 3, Name: "Спорт"}, nil).Once()
+	c, transport := newTestClient(t, nil, categories)
+
+	// 500 от приложения повторяется для GET
+	category, err := c.GetCategory(context.Background(), 3)
+	require.NoError(t, err)
+	assert.Equal(t, "Спорт", category.Name)
+	assert.Equal(t, int32(2), transport.calls.Load())
+	categories.AssertExpectations(t)
+
+	// 429 повторяется и для POST, пока не закончатся попытки
+	transport.calls.Store(0)
+	transport.fail, transport.status, transport.retryAfter = 10, http.StatusTooManyRequests, "0"
+	_, err = c.CreateCategory(context.Background(), client.CategoryInput{Name: "Кино"})
+	assert.ErrorIs(t, err, client.ErrRateLimited)
+	assert.Equal(t, int32(3), transport.calls.Load())
+
+	// 503 для POST не повторяется: запрос мог быть выполнен
+	transport.calls.Store(0)
+	transport.status = http.StatusServiceUnavailable
+	_, err = c.CreateCategory(context.Background(), client.CategoryInput{Name: "Кино"})
+	assert.ErrorIs(t, err, client.ErrUnavailable)
+	assert.Equal(t, int32(1), transport.calls.Load())
+}
+
+func TestTokenErrorNotRetried(t *testing.T) {
+	tokenErr := errors.New("token expired")
+	var tokenCalls int
+	c, transport := newTestClient(t, nil, nil, client.WithBearerToken(func(context.Context) (string, error) {
+		tokenCalls++
+		return "", tokenErr
+	}))
+
+	_, err := c.GetCategory(context.Background(), 3)
+	assert.ErrorIs(t, err, tokenErr)
+	assert.Equal(t, 1, tokenCalls)
+	assert.Equal(t, int32(0), transport.calls.Load())
+}
+
+func TestRetryStopsOnContextCancel(t *testing.T) {
+	c, transport := newTestClient(t, nil, nil, client.WithRetries(5, time.Hour, time.Hour))
+	transport.fail, transport.status = 10, http.StatusBadGateway
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := c.DeleteCategory(ctx, 1)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, int32(1), transport.calls.Load())
+}
@@ -0,0 +1,128 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Коды ошибок сервера (поле code ответа об ошибке)
+const (
+	CodeBadRequest           = "bad_request"
+	CodeValidationFailed     = "validation_failed"
+	CodeUnauthorized         = "unauthorized"
+	CodeForbidden            = "forbidden"
+	CodeNotFound             = "not_found"
+	CodeMethodNotAllowed     = "method_not_allowed"
+	CodeConflict             = "conflict"
+	CodePayloadTooLarge      = "payload_too_large"
+	CodeUnsupportedMediaType = "unsupported_media_type"
+	CodeRateLimited          = "rate_limited"
+	CodeInternal             = "internal_error"
+	CodeUnavailable          = "service_unavailable"
+)
+
+// Ошибки для errors.Is, совпадают с *Error с тем же кодом
+var (
+	ErrBadRequest   = &Error{Code: CodeBadRequest}
+	ErrValidation   = &Error{Code: CodeValidationFailed}
+	ErrUnauthorized = &Error{Code: CodeUnauthorized}
+	ErrForbidden    = &Error{Code: CodeForbidden}
+	ErrNotFound     = &Error{Code: CodeNotFound}
+	ErrConflict     = &Error{Code: CodeConflict}
+	ErrRateLimited  = &Error{Code: CodeRateLimited}
+	ErrInternal     = &Error{Code: CodeInternal}
+	ErrUnavailable  = &Error{Code: CodeUnavailable}
+)
+
+// Error - ответ сервера об ошибке (RFC 7807)
+type Error struct {
+	Status int    `json:"status"`
+	Code   string `json:"code"`
+	Title  string `json:"title"`
+	Detail string `json:"detail"`
+	// Errors - ошибки отдельных полей запроса (ответ 422)
+	Errors    []FieldError `json:"errors"`
+	RequestID string       `json:"request_id"`
+	// RetryAfter - через сколько можно повторить запрос (ответ 429)
+	RetryAfter time.Duration `json:"-"`
+}
+
+// FieldError - ошибка поля запроса, Field - имя поля в JSON, например title или categories[1]
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	message := fmt.Sprintf("news api: %d %s", e.Status, e.Code)
+	if e.Detail != "" {
+		message += ": " + e.Detail
+	}
+	for _, field := range e.Errors {
+		message += "; " + field.Field + ": " + field.Message
+	}
+	return message
+}
+
+// Is сравнивает ошибки по коду, поэтому errors.Is(err, ErrNotFound) верно для любого ответа 404 not_found
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// newError разбирает ответ об ошибке; если тело не в формате RFC 7807, код берется по статусу
+func newError(resp *http.Response) *Error {
+	apiErr := &Error{}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if strings.Contains(resp.Header.Get("Content-Type"), "json") {
+		_ = json.Unmarshal(body, apiErr)
+	}
+
+	apiErr.Status = resp.StatusCode
+	if apiErr.Code == "" {
+		apiErr.Code = statusCode(resp.StatusCode)
+	}
+	if apiErr.Title == "" {
+		apiErr.Title = http.StatusText(resp.StatusCode)
+	}
+	if apiErr.RequestID == "" {
+		apiErr.RequestID = resp.Header.Get("X-Request-ID")
+	}
+	apiErr.RetryAfter = retryAfter(resp.Header)
+	return apiErr
+}
+
+// statusCode - код ошибки по статусу для ответов без кода, например от прокси перед сервером
+func statusCode(status int) string {
+	switch status {
+	case http.StatusUnauthorized:
+		return CodeUnauthorized
+	case http.StatusForbidden:
+		return CodeForbidden
+	case http.StatusNotFound:
+		return CodeNotFound
+	case http.StatusMethodNotAllowed:
+		return CodeMethodNotAllowed
+	case http.StatusConflict:
+		return CodeConflict
+	case http.StatusRequestEntityTooLarge:
+		return CodePayloadTooLarge
+	case http.StatusUnsupportedMediaType:
+		return CodeUnsupportedMediaType
+	case http.StatusUnprocessableEntity:
+		return CodeValidationFailed
+	case http.StatusTooManyRequests:
+		return CodeRateLimited
+	case http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusGatewayTimeout:
+		return CodeUnavailable
+	}
+	if status >= http.StatusInternalServerError {
+		return CodeInternal
+	}
+	return CodeBadRequest
+}
@@ -0,0 +1,87 @@
+package client
+
+import "context"
+
+// Page - страница списка. Total заполняется, если сервер знает общее число записей,
+// NextCursor - если список поддерживает курсоры и есть следующая страница.
+type Page[T any] struct {
+	Items      []T
+	Limit      int
+	Offset     int
+	Total      *int64
+	NextCursor string
+}
+
+// pageSize - размер страницы итераторов, наибольший для /api/v1
+const pageSize = 100
+
+// Iterator перебирает все записи списка, запрашивая страницы по мере необходимости.
+//
+//	it := c.IterateNews(ctx, client.NewsListOptions{Tag: "go"})
+//	for it.Next() {
+//		news := it.Value()
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type Iterator[T any] struct {
+	ctx context.Context
+	// fetch получает следующую страницу и сообщает, последняя ли она
+	fetch func(ctx context.Context, limit int) ([]T, bool, error)
+	// key возвращает курсор записи, nil - список перебирается без курсоров
+	key    func(T) string
+	limit  int
+	cursor string
+	items  []T
+	value  T
+	done   bool
+	err    error
+}
+
+func newIterator[T any](ctx context.Context, limit int, fetch func(ctx context.Context, limit int) ([]T, bool, error)) *Iterator[T] {
+	if limit <= 0 {
+		limit = pageSize
+	}
+	return &Iterator[T]{ctx: ctx, fetch: fetch, limit: limit}
+}
+
+// Next переходит к следующей записи и возвращает false, когда записи закончились или произошла ошибка
+func (it *Iterator[T]) Next() bool {
+	if len(it.items) == 0 {
+		if it.done || it.err != nil {
+			return false
+		}
+
+		items, last, err := it.fetch(it.ctx, it.limit)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.items, it.done = items, last
+		if len(it.items) == 0 {
+			return false
+		}
+	}
+
+	it.value, it.items = it.items[0], it.items[1:]
+	if it.key != nil {
+		it.cursor = it.key(it.value)
+	}
+	return true
+}
+
+// Value возвращает текущую запись
+func (it *Iterator[T]) Value() T {
+	return it.value
+}
+
+// Cursor возвращает курсор текущей записи: с него можно продолжить перебор новым итератором
+// через Cursor в параметрах списка. Для списков без курсоров (категории) пустой.
+func (it *Iterator[T]) Cursor() string {
+	return it.cursor
+}
+
+// Err возвращает ошибку, на которой остановился перебор
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
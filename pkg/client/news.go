@@ -0,0 +1,128 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// ListNews возвращает страницу новостей
+// GET /api/v1/news
+func (c *Client) ListNews(ctx context.Context, opts NewsListOptions) (*Page[News], error) {
+	return c.listNews(ctx, "/api/v1/news", opts)
+}
+
+// ListPrivateNews возвращает страницу новостей с исходным Markdown (content_source), нужен WithAPIKey
+// GET /api/v1/private/news
+func (c *Client) ListPrivateNews(ctx context.Context, opts NewsListOptions) (*Page[News], error) {
+	return c.listNews(ctx, "/api/v1/private/news", opts)
+}
+
+// IterateNews перебирает все новости по курсору страниц, начиная с opts.Cursor или opts.Offset,
+// страницами по opts.Limit (по умолчанию 100)
+func (c *Client) IterateNews(ctx context.Context, opts NewsListOptions) *Iterator[News] {
+	return c.iterateNews(ctx, "/api/v1/news", opts)
+}
+
+// IteratePrivateNews перебирает все новости закрытого списка
+func (c *Client) IteratePrivateNews(ctx context.Context, opts NewsListOptions) *Iterator[News] {
+	return c.iterateNews(ctx, "/api/v1/private/news", opts)
+}
+
+func (c *Client) iterateNews(ctx context.Context, path string, opts NewsListOptions) *Iterator[News] {
+	it := newIterator(ctx, opts.Limit, func(ctx context.Context, limit int) ([]News, bool, error) {
+		opts.Limit = limit
+		page, err := c.listNews(ctx, path, opts)
+		if err != nil {
+			return nil, false, err
+		}
+		// следующие страницы запрашиваются только по курсору
+		opts.Cursor, opts.Offset = page.NextCursor, 0
+		return page.Items, page.NextCursor == "", nil
+	})
+	it.cursor = opts.Cursor
+	it.key = func(news News) string { return strconv.FormatInt(news.ID, 10) }
+	return it
+}
+
+func (c *Client) listNews(ctx context.Context, path string, opts NewsListOptions) (*Page[News], error) {
+	query := url.Values{}
+	setInt(query, "limit", int64(opts.Limit))
+	setInt(query, "offset", int64(opts.Offset))
+	setString(query, "cursor", opts.Cursor)
+	setInt(query, "category_id", opts.CategoryID)
+	setString(query, "tag", opts.Tag)
+	setString(query, "format", opts.Format)
+
+	var resp response[[]News]
+	if err := c.do(ctx, http.MethodGet, path, query, nil, &resp); err != nil {
+		return nil, err
+	}
+	return newPage(resp), nil
+}
+
+// GetNewsBySlug возвращает новость по текущему или прежнему slug
+// GET /api/v1/news/by-slug/:slug
+func (c *Client) GetNewsBySlug(ctx context.Context, slug string) (*News, error) {
+	var resp response[News]
+	if err := c.do(ctx, http.MethodGet, "/api/v1/news/by-slug/"+url.PathEscape(slug), nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Data, nil
+}
+
+// GetRelatedNews возвращает новости, похожие на новость id, limit 0 - по умолчанию сервера
+// GET /api/v1/news/:id/related
+func (c *Client) GetRelatedNews(ctx context.Context, id int64, limit int) ([]News, error) {
+	query := url.Values{}
+	setInt(query, "limit", int64(limit))
+
+	var resp response[[]News]
+	if err := c.do(ctx, http.MethodGet, newsPath(id)+"/related", query, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+// GetPopularNews возвращает самые просматриваемые новости за период
+// GET /api/v1/news/popular
+func (c *Client) GetPopularNews(ctx context.Context, opts PopularNewsOptions) ([]News, error) {
+	return c.popularNews(ctx, "/api/v1/news/popular", opts)
+}
+
+// GetPrivatePopularNews возвращает популярные новости с числом просмотров (Views), нужен WithAPIKey
+// GET /api/v1/private/news/popular
+func (c *Client) GetPrivatePopularNews(ctx context.Context, opts PopularNewsOptions) ([]News, error) {
+	return c.popularNews(ctx, "/api/v1/private/news/popular", opts)
+}
+
+func (c *Client) popularNews(ctx context.Context, path string, opts PopularNewsOptions) ([]News, error) {
+	query := url.Values{}
+	setString(query, "window", opts.Window)
+	setInt(query, "category_id", opts.CategoryID)
+	setInt(query, "limit", int64(opts.Limit))
+	setString(query, "format", opts.Format)
+
+	var resp response[[]News]
+	if err := c.do(ctx, http.MethodGet, path, query, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+// UpdateNews изменяет новость; HTML очищается по строгому списку разрешенных тегов
+// PATCH /api/v1/news/:id
+func (c *Client) UpdateNews(ctx context.Context, id int64, update NewsUpdate) error {
+	return c.do(ctx, http.MethodPatch, newsPath(id), nil, update, nil)
+}
+
+// UpdatePrivateNews изменяет новость от имени редактора (мягкая очистка HTML), нужен WithAPIKey
+// PATCH /api/v1/private/news/:id
+func (c *Client) UpdatePrivateNews(ctx context.Context, id int64, update NewsUpdate) error {
+	return c.do(ctx, http.MethodPatch, "/api/v1/private/news/"+strconv.FormatInt(id, 10), nil, update, nil)
+}
+
+func newsPath(id int64) string {
+	return "/api/v1/news/" + strconv.FormatInt(id, 10)
+}
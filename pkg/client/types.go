@@ -0,0 +1,151 @@
+package client
+
+import "time"
+
+// News - новость
+type News struct {
+	ID      int64  `json:"id"`
+	Title   string `json:"title"`
+	Slug    string `json:"slug"`
+	Content string `json:"content"`
+	// ContentFormat - формат, в котором автор передал содержимое: html или markdown
+	ContentFormat string `json:"content_format"`
+	// ContentSource - исходный Markdown, только в закрытых списках
+	ContentSource string    `json:"content_source,omitempty"`
+	Categories    []int64   `json:"categories"`
+	Tags          []string  `json:"tags"`
+	Media         []Media   `json:"media"`
+	Locale        string    `json:"locale,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	// Views - просмотры за период, только в закрытом списке популярных новостей
+	Views *int64 `json:"views,omitempty"`
+}
+
+// Media - изображение новости
+type Media struct {
+	ID              int64  `json:"id"`
+	URL             string `json:"url"`
+	ThumbnailURL    string `json:"thumbnail_url"`
+	ContentType     string `json:"content_type"`
+	Width           int    `json:"width"`
+	Height          int    `json:"height"`
+	ThumbnailWidth  int    `json:"thumbnail_width"`
+	ThumbnailHeight int    `json:"thumbnail_height"`
+	Alt             string `json:"alt"`
+	Position        int    `json:"position"`
+	IsCover         bool   `json:"is_cover"`
+}
+
+// NewsUpdate - изменение новости, незаданные (nil) поля не меняются
+type NewsUpdate struct {
+	Title   *string `json:"title,omitempty"`
+	Content *string `json:"content,omitempty"`
+	// ContentFormat - html или markdown
+	ContentFormat *string `json:"content_format,omitempty"`
+	// Categories - ID категорий, nil - без изменений, пустой срез удаляет все категории
+	Categories []int64 `json:"categories"`
+	// Tags - теги, nil - без изменений, пустой срез удаляет все теги
+	Tags []string `json:"tags"`
+}
+
+// NewsListOptions - параметры списка новостей, нулевые значения не передаются
+type NewsListOptions struct {
+	// Limit - размер страницы (до 100), Offset - смещение
+	Limit  int
+	Offset int
+	// Cursor - Page.NextCursor предыдущей страницы, задается вместо Offset
+	Cursor string
+	// CategoryID - новости категории и ее дочерних категорий
+	CategoryID int64
+	Tag        string
+	// Format - html, text или, для закрытых списков, markdown-source
+	Format string
+}
+
+// PopularNewsOptions - параметры списка популярных новостей
+type PopularNewsOptions struct {
+	// Window - период: 1h, 24h (по умолчанию), 7d, 30d
+	Window     string
+	CategoryID int64
+	Limit      int
+	Format     string
+}
+
+// Category - категория
+type Category struct {
+	ID          int64     `json:"id"`
+	Name        string    `json:"name"`
+	Slug        string    `json:"slug"`
+	Description string    `json:"description"`
+	ParentID    *int64    `json:"parent_id"`
+	Position    int       `json:"position"`
+	Locale      string    `json:"locale,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// CategoryNode - категория с дочерними категориями
+type CategoryNode struct {
+	Category
+	Children []CategoryNode `json:"children"`
+}
+
+// CategoryInput - создание или изменение категории
+type CategoryInput struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// ParentID - родитель; при изменении nil - без изменений, 0 - перенос в корень
+	ParentID *int64 `json:"parent_id,omitempty"`
+}
+
+// CategoryMove - перенос категории в дереве
+type CategoryMove struct {
+	// ParentID - новый родитель, nil или 0 - корень
+	ParentID *int64 `json:"parent_id"`
+	// Position - позиция среди дочерних категорий нового родителя, nil - последней
+	Position *int `json:"position,omitempty"`
+}
+
+// ListOptions - параметры страницы списка
+type ListOptions struct {
+	Limit  int
+	Offset int
+}
+
+// response - ответ API: данные в data, параметры страницы в meta
+type response[T any] struct {
+	Data T     `json:"data"`
+	Meta *meta `json:"meta"`
+}
+
+type meta struct {
+	Limit      int    `json:"limit"`
+	Offset     int    `json:"offset"`
+	Total      *int64 `json:"total"`
+	NextCursor string `json:"next_cursor"`
+}
+
+// newPage переводит ответ со списком в страницу
+func newPage[T any](r response[[]T]) *Page[T] {
+	page := &Page[T]{Items: r.Data}
+	if r.Meta != nil {
+		page.Limit, page.Offset, page.Total, page.NextCursor = r.Meta.Limit, r.Meta.Offset, r.Meta.Total, r.Meta.NextCursor
+	}
+	return page
+}
+
+// String возвращает указатель на s для полей NewsUpdate
+func String(s string) *string {
+	return &s
+}
+
+// Int64 возвращает указатель на v для ParentID
+func Int64(v int64) *int64 {
+	return &v
+}
+
+// Int возвращает указатель на v для Position
+func Int(v int) *int {
+	return &v
+}
@@ -3,6 +3,8 @@ package config
 import (
 	"fmt"
 	"log"
+	"net"
+	"strings"
 
 	"github.com/spf13/viper"
 )
@@ -107,8 +109,71 @@ type Config struct {
 	DataSourceName     string
 	Listen             string
 	SecretKey          string
+
+	DBSlowQueryThreshold int
+	DBLogQueryArgs       bool
+
+	DBReplicaDataSourceNames     []string
+	DBReplicaHealthCheckInterval int
+
+	DBTxAttempts  int
+	DBAutoMigrate bool
+
+	StreamBufferSize        int
+	StreamHeartbeatInterval int
+
+	WebhookPollInterval int
+	WebhookMaxAttempts  int
+	WebhookBackoffBase  int
+	WebhookBackoffMax   int
+	WebhookTimeout      int
+
+	PublicBaseURL string
+	FeedItems     int
+	FeedTitle     string
+
+	SanitizeStrictTags  []string
+	SanitizeRelaxedTags []string
+
+	MediaStorage       string
+	MediaLocalDir      string
+	MediaBaseURL       string
+	MediaMaxSize       int64
+	MediaThumbnailSize int
+	S3Endpoint         string
+	S3Region           string
+	S3Bucket           string
+	S3AccessKey        string
+	S3SecretKey        string
+	S3PathStyle        bool
+	S3PublicURL        string
+
+	Locales         []string
+	LocaleFallbacks string
+
+	RelatedCacheTTL        int
+	RelatedCacheMaxEntries int
+
+	ViewsDedupWindow   int
+	ViewsFlushInterval int
+
+	ReaderJWTSecret       string
+	CommentsPremoderation bool
+	CommentsRateLimit     int
+	CommentsRateWindow    int
+	CommentsMaxLength     int
+	CommentsBlockedWords  []string
+	CommentsAllowLinks    bool
+
+	DocsAssetsURL string
 }
 
+// Хранилища изображений новостей (MEDIA_STORAGE)
+const (
+	MediaStorageLocal = "local"
+	MediaStorageS3    = "s3"
+)
+
 func Load() (*Config, error) {
 	viper.SetConfigFile(".env")
 	viper.AutomaticEnv()
@@ -146,5 +211,115 @@ func Load() (*Config, error) {
 		DataSourceName:     fmt.Sprintf("host=%v user=%v password=%v dbname=%v port=%v sslmode=disable TimeZone=Europe/Moscow", viper.GetString("DB_HOST"), viper.GetString("DB_USER"), viper.GetString("DB_PASSWORD"), viper.GetString("DB_NAME"), viper.GetInt("DB_PORT")),
 		Listen:             fmt.Sprintf("%v:%v", viper.GetString("SERVER_HOST"), viper.GetInt("SERVER_PORT")),
 		SecretKey:          viper.GetString("SECRET_KEY"),
+
+		DBSlowQueryThreshold: viper.GetInt("DB_SLOW_QUERY_THRESHOLD"),
+		DBLogQueryArgs:       viper.GetBool("DB_LOG_QUERY_ARGS"),
+
+		DBReplicaDataSourceNames:     replicaDataSourceNames(viper.GetString("DB_REPLICA_HOSTS")),
+		DBReplicaHealthCheckInterval: viper.GetInt("DB_REPLICA_HEALTH_CHECK_INTERVAL"),
+
+		DBTxAttempts:  viper.GetInt("DB_TX_ATTEMPTS"),
+		DBAutoMigrate: viper.GetBool("DB_AUTO_MIGRATE"),
+
+		StreamBufferSize:        viper.GetInt("STREAM_BUFFER_SIZE"),
+		StreamHeartbeatInterval: viper.GetInt("STREAM_HEARTBEAT_INTERVAL"),
+
+		WebhookPollInterval: viper.GetInt("WEBHOOK_POLL_INTERVAL"),
+		WebhookMaxAttempts:  viper.GetInt("WEBHOOK_MAX_ATTEMPTS"),
+		WebhookBackoffBase:  viper.GetInt("WEBHOOK_BACKOFF_BASE"),
+		WebhookBackoffMax:   viper.GetInt("WEBHOOK_BACKOFF_MAX"),
+		WebhookTimeout:      viper.GetInt("WEBHOOK_TIMEOUT"),
+
+		PublicBaseURL: publicBaseURL(),
+		FeedItems:     viper.GetInt("FEED_ITEMS"),
+		FeedTitle:     viper.GetString("FEED_TITLE"),
+
+		SanitizeStrictTags:  splitList(viper.GetString("SANITIZE_STRICT_TAGS")),
+		SanitizeRelaxedTags: splitList(viper.GetString("SANITIZE_RELAXED_TAGS")),
+
+		MediaStorage:       valueOr(strings.ToLower(viper.GetString("MEDIA_STORAGE")), MediaStorageLocal),
+		MediaLocalDir:      valueOr(viper.GetString("MEDIA_LOCAL_DIR"), "uploads"),
+		MediaBaseURL:       valueOr(strings.TrimRight(viper.GetString("MEDIA_BASE_URL"), "/"), "/media"),
+		MediaMaxSize:       viper.GetInt64("MEDIA_MAX_SIZE"),
+		MediaThumbnailSize: viper.GetInt("MEDIA_THUMBNAIL_SIZE"),
+		S3Endpoint:         viper.GetString("S3_ENDPOINT"),
+		S3Region:           viper.GetString("S3_REGION"),
+		S3Bucket:           viper.GetString("S3_BUCKET"),
+		S3AccessKey:        viper.GetString("S3_ACCESS_KEY"),
+		S3SecretKey:        viper.GetString("S3_SECRET_KEY"),
+		S3PathStyle:        viper.GetBool("S3_PATH_STYLE"),
+		S3PublicURL:        viper.GetString("S3_PUBLIC_URL"),
+
+		Locales:         splitList(valueOr(viper.GetString("LOCALES"), "ru")),
+		LocaleFallbacks: viper.GetString("LOCALE_FALLBACKS"),
+
+		RelatedCacheTTL:        viper.GetInt("RELATED_CACHE_TTL"),
+		RelatedCacheMaxEntries: viper.GetInt("RELATED_CACHE_MAX_ENTRIES"),
+
+		ViewsDedupWindow:   viper.GetInt("VIEWS_DEDUP_WINDOW"),
+		ViewsFlushInterval: viper.GetInt("VIEWS_FLUSH_INTERVAL"),
+
+		ReaderJWTSecret:       viper.GetString("READER_JWT_SECRET"),
+		CommentsPremoderation: viper.GetBool("COMMENTS_PREMODERATION"),
+		CommentsRateLimit:     viper.GetInt("COMMENTS_RATE_LIMIT"),
+		CommentsRateWindow:    viper.GetInt("COMMENTS_RATE_WINDOW"),
+		CommentsMaxLength:     viper.GetInt("COMMENTS_MAX_LENGTH"),
+		CommentsBlockedWords:  splitList(viper.GetString("COMMENTS_BLOCKED_WORDS")),
+		CommentsAllowLinks:    viper.GetBool("COMMENTS_ALLOW_LINKS"),
+
+		DocsAssetsURL: strings.TrimRight(viper.GetString("DOCS_ASSETS_URL"), "/"),
 	}, nil
 }
+
+// publicBaseURL возвращает адрес публичного сайта для абсолютных ссылок (ленты, sitemap).
+// Берется из PUBLIC_BASE_URL, иначе из server.host в config/config.local.yaml.
+func publicBaseURL() string {
+	baseURL := viper.GetString("PUBLIC_BASE_URL")
+	if baseURL == "" {
+		v := viper.New()
+		v.SetConfigFile("config/config.local.yaml")
+		if err := v.ReadInConfig(); err == nil {
+			baseURL = v.GetString("server.host")
+		}
+	}
+	return strings.TrimRight(baseURL, "/")
+}
+
+// valueOr возвращает value, а если оно пустое - def
+func valueOr(value, def string) string {
+	if value == "" {
+		return def
+	}
+	return value
+}
+
+// splitList разбирает список значений через запятую, пустые значения пропускаются
+func splitList(value string) []string {
+	var result []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// replicaDataSourceNames строит DSN реплик из списка "host:port" через запятую.
+// Пользователь, пароль и имя базы совпадают с primary.
+func replicaDataSourceNames(hosts string) []string {
+	var dsns []string
+	for _, host := range strings.Split(hosts, ",") {
+		host = strings.TrimSpace(host)
+		if host == "" {
+			continue
+		}
+
+		port := viper.GetString("DB_PORT")
+		if h, p, err := net.SplitHostPort(host); err == nil {
+			host, port = h, p
+		}
+
+		dsns = append(dsns, fmt.Sprintf("host=%v user=%v password=%v dbname=%v port=%v sslmode=disable TimeZone=Europe/Moscow", host, viper.GetString("DB_USER"), viper.GetString("DB_PASSWORD"), viper.GetString("DB_NAME"), port))
+	}
+	return dsns
+}
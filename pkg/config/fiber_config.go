@@ -7,10 +7,14 @@ import (
 )
 
 // FiberConfig func for configuration Fiber app.
+// bodyLimit - максимальный размер тела запроса в байтах, <= 0 - значение Fiber по умолчанию.
+// errorHandler формирует ответы на все ошибки обработчиков и middleware.
 // See: https://docs.gofiber.io/api/fiber#config
-func FiberConfig(readTimeout int) fiber.Config {
+func FiberConfig(readTimeout, bodyLimit int, errorHandler fiber.ErrorHandler) fiber.Config {
 	// Return Fiber configuration.
 	return fiber.Config{
-		ReadTimeout: time.Second * time.Duration(readTimeout),
+		ReadTimeout:  time.Second * time.Duration(readTimeout),
+		BodyLimit:    bodyLimit,
+		ErrorHandler: errorHandler,
 	}
 }
@@ -0,0 +1,248 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"gopkg.in/reform.v1"
+)
+
+// PrimaryKey - ключ значения контекста, которое направляет запросы на primary. Для context.Context его задает
+// WithPrimary, для контекста запроса Fiber (fasthttp.RequestCtx) - fiber.Ctx.Locals(PrimaryKey{}, true).
+type PrimaryKey struct{}
+
+// WithPrimary возвращает контекст, запросы с которым всегда выполняются на primary.
+// Используется для чтения сразу после записи, когда реплика может отставать.
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, PrimaryKey{}, true)
+}
+
+// isPrimaryForced проверяет, запрошено ли чтение с primary. Запросы с контекстом транзакции
+// тоже читают с primary, даже если выполняются не через нее.
+func isPrimaryForced(ctx context.Context) bool {
+	forced, _ := ctx.Value(PrimaryKey{}).(bool)
+	return forced || reform.TXFromContext(ctx) != nil
+}
+
+// ReplicaConfig настройки маршрутизации запросов на реплики
+type ReplicaConfig struct {
+	// HealthCheckInterval - период проверки доступности реплик
+	HealthCheckInterval time.Duration
+	// HealthCheckTimeout - таймаут одной проверки
+	HealthCheckTimeout time.Duration
+}
+
+type replica struct {
+	db      *sql.DB
+	name    string
+	healthy atomic.Bool
+}
+
+// ReplicaDB реализует reform.DBInterface: читающие запросы вне транзакций распределяются
+// по репликам (round-robin), а запись и транзакции выполняются на primary.
+// Недоступные реплики исключаются до следующей успешной проверки, при их отсутствии чтение идет на primary.
+type ReplicaDB struct {
+	primary  *sql.DB
+	replicas []*replica
+	next     atomic.Uint64
+	config   ReplicaConfig
+	logger   *zap.SugaredLogger
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewReplicaDB создает маршрутизатор запросов. Имена реплик используются только в логах.
+func NewReplicaDB(primary *sql.DB, replicas map[string]*sql.DB, config ReplicaConfig, logger *zap.SugaredLogger) *ReplicaDB {
+	if config.HealthCheckInterval <= 0 {
+		config.HealthCheckInterval = 10 * time.Second
+	}
+	if config.HealthCheckTimeout <= 0 {
+		config.HealthCheckTimeout = 2 * time.Second
+	}
+
+	db := &ReplicaDB{
+		primary: primary,
+		config:  config,
+		logger:  logger,
+		stop:    make(chan struct{}),
+	}
+	for name, r := range replicas {
+		rep := &replica{db: r, name: name}
+		rep.healthy.Store(true)
+		db.replicas = append(db.replicas, rep)
+	}
+	return db
+}
+
+// Проверка, что ReplicaDB реализует интерфейс
+var _ reform.DBInterface = (*ReplicaDB)(nil)
+
+// Primary возвращает подключение к primary
+func (db *ReplicaDB) Primary() *sql.DB {
+	return db.primary
+}
+
+// Start запускает периодическую проверку реплик
+func (db *ReplicaDB) Start() {
+	db.checkReplicas()
+
+	db.wg.Add(1)
+	go func() {
+		defer db.wg.Done()
+
+		ticker := time.NewTicker(db.config.HealthCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				db.checkReplicas()
+			case <-db.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Close останавливает проверки и закрывает подключения к репликам
+func (db *ReplicaDB) Close() error {
+	close(db.stop)
+	db.wg.Wait()
+
+	var err error
+	for _, r := range db.replicas {
+		if e := r.db.Close(); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+func (db *ReplicaDB) checkReplicas() {
+	for _, r := range db.replicas {
+		ctx, cancel := context.WithTimeout(context.Background(), db.config.HealthCheckTimeout)
+		err := r.db.PingContext(ctx)
+		cancel()
+
+		db.setHealthy(r, err)
+	}
+}
+
+func (db *ReplicaDB) setHealthy(r *replica, err error) {
+	healthy := err == nil
+	if r.healthy.Swap(healthy) == healthy {
+		return
+	}
+
+	if healthy {
+		db.logger.Infow("Replica is back online", "replica", r.name)
+	} else {
+		db.logger.Warnw("Replica is unavailable, reads are routed to other nodes", "replica", r.name, "error", err)
+	}
+}
+
+// reader выбирает реплику для запроса или nil, если запрос должен выполниться на primary
+func (db *ReplicaDB) reader(ctx context.Context, query string) *replica {
+	if len(db.replicas) == 0 || isPrimaryForced(ctx) || !isReadOnly(query) {
+		return nil
+	}
+
+	n := uint64(len(db.replicas))
+	start := db.next.Add(1)
+	for i := uint64(0); i < n; i++ {
+		r := db.replicas[(start+i)%n]
+		if r.healthy.Load() {
+			return r
+		}
+	}
+	return nil
+}
+
+// ExecContext всегда выполняется на primary
+func (db *ReplicaDB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return db.primary.ExecContext(ctx, query, args...)
+}
+
+// QueryContext выполняет читающий запрос на реплике, при ошибке соединения повторяет его на primary
+func (db *ReplicaDB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	if r := db.reader(ctx, query); r != nil {
+		rows, err := r.db.QueryContext(ctx, query, args...)
+		if err == nil || !isConnectionError(err) {
+			return rows, err
+		}
+		db.setHealthy(r, err)
+	}
+
+	return db.primary.QueryContext(ctx, query, args...)
+}
+
+// QueryRowContext выполняет читающий запрос на реплике.
+// Ошибки sql.Row откладываются до Scan, поэтому переключение на primary здесь происходит только по результатам проверок.
+func (db *ReplicaDB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	if r := db.reader(ctx, query); r != nil {
+		return r.db.QueryRowContext(ctx, query, args...)
+	}
+
+	return db.primary.QueryRowContext(ctx, query, args...)
+}
+
+// BeginTx всегда начинает транзакцию на primary
+func (db *ReplicaDB) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	return db.primary.BeginTx(ctx, opts)
+}
+
+// Exec выполняется на primary.
+//
+// Deprecated: используется только для совместимости с reform.DBInterface.
+func (db *ReplicaDB) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return db.ExecContext(context.Background(), query, args...)
+}
+
+// Query выполняется на реплике.
+//
+// Deprecated: используется только для совместимости с reform.DBInterface.
+func (db *ReplicaDB) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return db.QueryContext(context.Background(), query, args...)
+}
+
+// QueryRow выполняется на реплике.
+//
+// Deprecated: используется только для совместимости с reform.DBInterface.
+func (db *ReplicaDB) QueryRow(query string, args ...interface{}) *sql.Row {
+	return db.QueryRowContext(context.Background(), query, args...)
+}
+
+// Begin начинает транзакцию на primary.
+//
+// Deprecated: используется только для совместимости с reform.DBInterface.
+func (db *ReplicaDB) Begin() (*sql.Tx, error) {
+	return db.BeginTx(context.Background(), nil)
+}
+
+// isReadOnly проверяет, что запрос только читает данные и может быть выполнен на реплике.
+// INSERT ... RETURNING, CTE и SELECT ... FOR UPDATE выполняются на primary.
+func isReadOnly(query string) bool {
+	q := strings.ToUpper(strings.TrimSpace(query))
+	if !strings.HasPrefix(q, "SELECT") {
+		return false
+	}
+	return !strings.Contains(q, "FOR UPDATE") && !strings.Contains(q, "FOR SHARE")
+}
+
+// isConnectionError проверяет, что ошибка вызвана недоступностью сервера, а не самим запросом
+func isConnectionError(err error) bool {
+	if errors.Is(err, driver.ErrBadConn) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
@@ -0,0 +1,175 @@
+// Package locale выбирает язык ответа по ?lang и Accept-Language и строит цепочки замены
+// для отсутствующих переводов.
+package locale
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Locales - языки сервера. Default - язык исходных записей ("News", "Categories"),
+// для остальных языков Supported хранятся переводы.
+type Locales struct {
+	Default   string
+	Supported []string
+	// Fallbacks - языки, перевод на которые показывается при отсутствии перевода на ключевой язык,
+	// в порядке предпочтения. Default всегда завершает цепочку.
+	Fallbacks map[string][]string
+}
+
+// New создает Locales из списка языков (первый - язык по умолчанию) и цепочек замены
+// вида "uk:ru,en;be:ru". Языки цепочек должны входить в список.
+func New(supported []string, fallbacks string) (*Locales, error) {
+	l := &Locales{Fallbacks: make(map[string][]string)}
+	for _, tag := range supported {
+		tag = Normalize(tag)
+		if tag != "" && !l.IsSupported(tag) {
+			l.Supported = append(l.Supported, tag)
+		}
+	}
+	if len(l.Supported) == 0 {
+		return nil, fmt.Errorf("locale: no locales configured")
+	}
+	l.Default = l.Supported[0]
+
+	for _, entry := range strings.Split(fallbacks, ";") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		tag, chain, ok := strings.Cut(entry, ":")
+		tag = Normalize(tag)
+		if !ok || !l.IsSupported(tag) {
+			return nil, fmt.Errorf("locale: invalid fallback %q, expected supported locale followed by \":\"", entry)
+		}
+		for _, item := range strings.Split(chain, ",") {
+			item = Normalize(item)
+			if item == "" {
+				continue
+			}
+			if !l.IsSupported(item) {
+				return nil, fmt.Errorf("locale: fallback %q of %q is not a supported locale", item, tag)
+			}
+			l.Fallbacks[tag] = append(l.Fallbacks[tag], item)
+		}
+	}
+	return l, nil
+}
+
+// Normalize приводит тег языка к виду "en" или "en-GB": "_" заменяется на "-",
+// язык в нижнем регистре, регион из двух букв - в верхнем
+func Normalize(tag string) string {
+	parts := strings.Split(strings.ReplaceAll(strings.TrimSpace(tag), "_", "-"), "-")
+	for i, part := range parts {
+		if i > 0 && len(part) == 2 {
+			parts[i] = strings.ToUpper(part)
+		} else {
+			parts[i] = strings.ToLower(part)
+		}
+	}
+	return strings.Join(parts, "-")
+}
+
+// IsSupported проверяет, входит ли нормализованный тег в список языков
+func (l *Locales) IsSupported(tag string) bool {
+	for _, supported := range l.Supported {
+		if supported == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// Match возвращает язык сервера для запрошенного тега: совпадающий, язык без региона ("en-US" - "en")
+// или первый язык с тем же основным языком ("en" - "en-GB")
+func (l *Locales) Match(tag string) (string, bool) {
+	tag = Normalize(tag)
+	if tag == "" {
+		return "", false
+	}
+	if l.IsSupported(tag) {
+		return tag, true
+	}
+
+	base, _, _ := strings.Cut(tag, "-")
+	if l.IsSupported(base) {
+		return base, true
+	}
+	for _, supported := range l.Supported {
+		if strings.HasPrefix(supported, base+"-") {
+			return supported, true
+		}
+	}
+	return "", false
+}
+
+// Negotiate выбирает язык ответа: ?lang, если язык поддерживается, затем языки Accept-Language
+// по убыванию q, иначе Default
+func (l *Locales) Negotiate(lang, acceptLanguage string) string {
+	if tag, ok := l.Match(lang); ok {
+		return tag
+	}
+	for _, tag := range parseAcceptLanguage(acceptLanguage) {
+		if tag == "*" {
+			return l.Default
+		}
+		if matched, ok := l.Match(tag); ok {
+			return matched
+		}
+	}
+	return l.Default
+}
+
+// Chain возвращает языки, перевод на которые подходит для tag, в порядке предпочтения:
+// tag, его цепочка замены и Default
+func (l *Locales) Chain(tag string) []string {
+	chain := []string{tag}
+	for _, item := range append(l.Fallbacks[tag], l.Default) {
+		seen := false
+		for _, existing := range chain {
+			seen = seen || existing == item
+		}
+		if !seen {
+			chain = append(chain, item)
+		}
+	}
+	return chain
+}
+
+// parseAcceptLanguage возвращает теги заголовка Accept-Language по убыванию q, теги с q=0 пропускаются
+func parseAcceptLanguage(header string) []string {
+	type weighted struct {
+		tag string
+		q   float64
+	}
+
+	var tags []weighted
+	for _, item := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(item, ";")
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			name, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if ok && strings.EqualFold(name, "q") {
+				if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		if q > 0 {
+			tags = append(tags, weighted{tag: tag, q: q})
+		}
+	}
+
+	sort.SliceStable(tags, func(i, j int) bool { return tags[i].q > tags[j].q })
+	result := make([]string, len(tags))
+	for i, tag := range tags {
+		result[i] = tag.tag
+	}
+	return result
+}
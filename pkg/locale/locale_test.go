@@ -0,0 +1,59 @@
+package locale
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNegotiate(t *testing.T) {
+	l, err := New([]string{"ru", "en", "en_gb", "uk"}, "uk:ru")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		lang, header, want string
+	}{
+		{"", "", "ru"},
+		{"en", "uk", "en"},
+		{"EN-gb", "", "en-GB"},
+		{"de", "uk,en;q=0.9", "uk"},
+		{"", "de-DE,en-US;q=0.8,ru;q=0.5", "en"},
+		{"", "de, en;q=0, ru;q=0.1", "ru"},
+		{"", "fr, *;q=0.5", "ru"},
+		{"", "en;q=0.4, uk;q=0.7", "uk"},
+	}
+	for _, tt := range tests {
+		if got := l.Negotiate(tt.lang, tt.header); got != tt.want {
+			t.Errorf("Negotiate(%q, %q) = %q, want %q", tt.lang, tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestChain(t *testing.T) {
+	l, err := New([]string{"ru", "en", "uk", "be"}, "be:uk,en; uk:ru,en")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string][]string{
+		"ru": {"ru"},
+		"en": {"en", "ru"},
+		"uk": {"uk", "ru", "en"},
+		"be": {"be", "uk", "en", "ru"},
+	}
+	for tag, want := range tests {
+		if got := l.Chain(tag); !reflect.DeepEqual(got, want) {
+			t.Errorf("Chain(%q) = %v, want %v", tag, got, want)
+		}
+	}
+}
+
+func TestNewRejectsUnknownFallback(t *testing.T) {
+	if _, err := New([]string{"ru", "en"}, "en:de"); err == nil {
+		t.Error("expected error for unsupported fallback locale")
+	}
+	if _, err := New(nil, ""); err == nil {
+		t.Error("expected error without locales")
+	}
+}
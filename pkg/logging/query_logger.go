@@ -0,0 +1,204 @@
+package logging
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"gopkg.in/reform.v1"
+)
+
+const (
+	// maxQueryStats ограничивает количество различных запросов в статистике
+	maxQueryStats = 1000
+	// maxQuerySamples - количество последних замеров, по которым считается p95
+	maxQuerySamples = 1024
+)
+
+var (
+	queryPlaceholderRe = regexp.MustCompile(`\$\d+|@P\d+`)
+	queryStringRe      = regexp.MustCompile(`'(?:[^']|'')*'`)
+	queryNumberRe      = regexp.MustCompile(`\b\d+(?:\.\d+)?\b`)
+	queryListRe        = regexp.MustCompile(`\?(?:\s*,\s*\?)+`)
+	querySpaceRe       = regexp.MustCompile(`\s+`)
+)
+
+// QueryLoggerConfig настройки логгера SQL запросов
+type QueryLoggerConfig struct {
+	// SlowThreshold - запросы дольше этого времени логируются с уровнем warn
+	SlowThreshold time.Duration
+	// LogArgs включает логирование значений аргументов запросов
+	LogArgs bool
+}
+
+// QueryStat агрегированная статистика по нормализованному запросу
+type QueryStat struct {
+	Query   string  `json:"query"`
+	Count   int64   `json:"count"`
+	Errors  int64   `json:"errors"`
+	Slow    int64   `json:"slow"`
+	TotalMs float64 `json:"total_ms"`
+	AvgMs   float64 `json:"avg_ms"`
+	P95Ms   float64 `json:"p95_ms"`
+	MaxMs   float64 `json:"max_ms"`
+}
+
+type queryStat struct {
+	count   int64
+	errors  int64
+	slow    int64
+	total   time.Duration
+	max     time.Duration
+	samples []time.Duration
+	next    int
+}
+
+// QueryLogger реализует reform.Logger поверх zap и собирает статистику запросов
+type QueryLogger struct {
+	logger *zap.Logger
+	config QueryLoggerConfig
+
+	mu    sync.Mutex
+	stats map[string]*queryStat
+}
+
+// NewQueryLogger создает логгер SQL запросов
+func NewQueryLogger(logger *zap.Logger, config QueryLoggerConfig) *QueryLogger {
+	return &QueryLogger{
+		logger: logger.Named("reform"),
+		config: config,
+		stats:  make(map[string]*queryStat),
+	}
+}
+
+// Проверка, что QueryLogger реализует интерфейс
+var _ reform.Logger = (*QueryLogger)(nil)
+
+// Before ничего не делает: запрос логируется после выполнения вместе с длительностью
+func (l *QueryLogger) Before(query string, args []interface{}) {}
+
+// After логирует выполненный запрос и обновляет статистику
+func (l *QueryLogger) After(query string, args []interface{}, d time.Duration, err error) {
+	slow := l.config.SlowThreshold > 0 && d >= l.config.SlowThreshold
+
+	fields := []zap.Field{
+		zap.String("query", query),
+		zap.Duration("duration", d),
+	}
+	if l.config.LogArgs {
+		fields = append(fields, zap.Any("args", args))
+	} else {
+		fields = append(fields, zap.Int("args_count", len(args)))
+	}
+
+	switch {
+	case err != nil:
+		l.logger.Warn("query failed", append(fields, zap.Error(err))...)
+	case slow:
+		l.logger.Warn("slow query", fields...)
+	default:
+		l.logger.Debug("query", fields...)
+	}
+
+	l.record(NormalizeQuery(query), d, err != nil, slow)
+}
+
+func (l *QueryLogger) record(query string, d time.Duration, failed, slow bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	s := l.stats[query]
+	if s == nil {
+		if len(l.stats) >= maxQueryStats {
+			return
+		}
+		s = &queryStat{}
+		l.stats[query] = s
+	}
+
+	s.count++
+	s.total += d
+	if d > s.max {
+		s.max = d
+	}
+	if failed {
+		s.errors++
+	}
+	if slow {
+		s.slow++
+	}
+
+	if len(s.samples) < maxQuerySamples {
+		s.samples = append(s.samples, d)
+	} else {
+		s.samples[s.next] = d
+		s.next = (s.next + 1) % maxQuerySamples
+	}
+}
+
+// Stats возвращает статистику запросов, отсортированную по суммарному времени выполнения
+func (l *QueryLogger) Stats() []QueryStat {
+	l.mu.Lock()
+	res := make([]QueryStat, 0, len(l.stats))
+	for query, s := range l.stats {
+		samples := make([]time.Duration, len(s.samples))
+		copy(samples, s.samples)
+		res = append(res, QueryStat{
+			Query:   query,
+			Count:   s.count,
+			Errors:  s.errors,
+			Slow:    s.slow,
+			TotalMs: durationMs(s.total),
+			AvgMs:   durationMs(s.total / time.Duration(s.count)),
+			P95Ms:   durationMs(percentile(samples, 0.95)),
+			MaxMs:   durationMs(s.max),
+		})
+	}
+	l.mu.Unlock()
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].TotalMs > res[j].TotalMs
+	})
+	return res
+}
+
+// Reset очищает накопленную статистику
+func (l *QueryLogger) Reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.stats = make(map[string]*queryStat)
+}
+
+// NormalizeQuery приводит запрос к виду без конкретных значений,
+// чтобы одинаковые запросы с разными параметрами попадали в одну группу статистики
+func NormalizeQuery(query string) string {
+	query = queryStringRe.ReplaceAllString(query, "?")
+	query = queryPlaceholderRe.ReplaceAllString(query, "?")
+	query = queryNumberRe.ReplaceAllString(query, "?")
+	query = queryListRe.ReplaceAllString(query, "?, ...")
+	query = querySpaceRe.ReplaceAllString(query, " ")
+	return strings.TrimSpace(query)
+}
+
+// percentile возвращает значение перцентиля p (0..1) по методу ближайшего ранга
+func percentile(samples []time.Duration, p float64) time.Duration {
+	if len(samples) == 0 {
+		return 0
+	}
+
+	sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+	rank := int(math.Ceil(float64(len(samples))*p)) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return samples[rank]
+}
+
+func durationMs(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
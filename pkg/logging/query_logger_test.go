@@ -0,0 +1,40 @@
+package logging
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestNormalizeQuery(t *testing.T) {
+	query := `SELECT "Id" FROM "News"
+		WHERE "Id" IN ($1, $2, $3) AND "Title" = 'foo' LIMIT 10`
+
+	assert.Equal(t, `SELECT "Id" FROM "News" WHERE "Id" IN (?, ...) AND "Title" = ? LIMIT ?`, NormalizeQuery(query))
+}
+
+func TestQueryLoggerStats(t *testing.T) {
+	l := NewQueryLogger(zap.NewNop(), QueryLoggerConfig{SlowThreshold: 50 * time.Millisecond})
+
+	for i := 1; i <= 100; i++ {
+		l.After(`SELECT 1 WHERE "Id" = $1`, []interface{}{i}, time.Duration(i)*time.Millisecond, nil)
+	}
+	l.After(`UPDATE "News" SET "Title" = $1`, nil, time.Millisecond, errors.New("failed"))
+
+	stats := l.Stats()
+	assert.Len(t, stats, 2)
+
+	assert.Equal(t, `SELECT ? WHERE "Id" = ?`, stats[0].Query)
+	assert.Equal(t, int64(100), stats[0].Count)
+	assert.Equal(t, int64(51), stats[0].Slow)
+	assert.Equal(t, 95.0, stats[0].P95Ms)
+	assert.Equal(t, 100.0, stats[0].MaxMs)
+
+	assert.Equal(t, int64(1), stats[1].Errors)
+
+	l.Reset()
+	assert.Empty(t, l.Stats())
+}
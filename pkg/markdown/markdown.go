@@ -0,0 +1,83 @@
+package markdown
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+)
+
+// md - конвертер Markdown (CommonMark с таблицами, зачеркиванием и автоссылками).
+// Встроенный HTML не выводится: результат дополнительно очищается политикой sanitize.
+var md = goldmark.New(
+	goldmark.WithExtensions(
+		extension.NewTable(extension.WithTableCellAlignMethod(extension.TableCellAlignAttribute)),
+		extension.Strikethrough,
+		extension.Linkify,
+	),
+	goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+)
+
+// Render преобразует Markdown в HTML. Заголовки получают якоря id из их текста.
+func Render(source string) (string, error) {
+	src := []byte(source)
+	ctx := parser.NewContext(parser.WithIDs(newHeadingIDs()))
+
+	var buf bytes.Buffer
+	if err := md.Renderer().Render(&buf, src, md.Parser().Parse(text.NewReader(src), parser.WithContext(ctx))); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// headingIDs формирует якоря заголовков: буквы и цифры любого алфавита в нижнем регистре,
+// пробелы и знаки препинания заменяются дефисом, повторы получают суффикс -1, -2...
+type headingIDs struct {
+	used map[string]bool
+}
+
+func newHeadingIDs() *headingIDs {
+	return &headingIDs{used: make(map[string]bool)}
+}
+
+func (s *headingIDs) Generate(value []byte, kind ast.NodeKind) []byte {
+	var sb strings.Builder
+	dash := false
+	for _, r := range strings.TrimSpace(string(value)) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_':
+			if dash && sb.Len() > 0 {
+				sb.WriteByte('-')
+			}
+			dash = false
+			sb.WriteRune(unicode.ToLower(r))
+		default:
+			dash = true
+		}
+	}
+
+	id := sb.String()
+	if id == "" {
+		id = "heading"
+		if kind != ast.KindHeading {
+			id = "id"
+		}
+	}
+
+	candidate := id
+	for i := 1; s.used[candidate]; i++ {
+		candidate = id + "-" + strconv.Itoa(i)
+	}
+	s.used[candidate] = true
+	return []byte(candidate)
+}
+
+func (s *headingIDs) Put(value []byte) {
+	s.used[string(value)] = true
+}
@@ -0,0 +1,33 @@
+package markdown
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderHeadingAnchors(t *testing.T) {
+	html, err := Render("# Новости дня\n\n## Hello, World!\n\n## Новости дня\n")
+	require.NoError(t, err)
+
+	assert.Contains(t, html, `<h1 id="новости-дня">Новости дня</h1>`)
+	assert.Contains(t, html, `<h2 id="hello-world">Hello, World!</h2>`)
+	assert.Contains(t, html, `<h2 id="новости-дня-1">Новости дня</h2>`)
+}
+
+func TestRenderTable(t *testing.T) {
+	html, err := Render("| Команда | Очки |\n|:--|--:|\n| Спартак | 3 |\n")
+	require.NoError(t, err)
+
+	assert.Contains(t, html, `<th align="left">Команда</th>`)
+	assert.Contains(t, html, `<td align="right">3</td>`)
+}
+
+func TestRenderOmitsRawHTML(t *testing.T) {
+	html, err := Render("text <script>alert(1)</script> ~~old~~")
+	require.NoError(t, err)
+
+	assert.NotContains(t, html, "<script>")
+	assert.Contains(t, html, "<del>old</del>")
+}
@@ -0,0 +1,105 @@
+// Package media определяет тип и размеры загруженных изображений и формирует их миниатюры.
+package media
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+
+	xdraw "golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// MaxPixels - наибольшее число пикселей изображения: больше не декодируется, чтобы
+// маленький файл с огромными размерами не занял всю память
+const MaxPixels = 40_000_000
+
+// extensions - поддерживаемые типы изображений и расширения файлов для них
+var extensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+var (
+	// ErrUnsupportedType - содержимое файла не является изображением поддерживаемого типа
+	ErrUnsupportedType = errors.New("unsupported media type, allowed: JPEG, PNG, GIF, WebP")
+	// ErrTooLarge - размеры изображения больше MaxPixels
+	ErrTooLarge = errors.New("image dimensions are too large")
+)
+
+// Info - тип и размеры изображения
+type Info struct {
+	ContentType string
+	Extension   string
+	Width       int
+	Height      int
+}
+
+// Inspect определяет тип изображения по содержимому (заголовок Content-Type клиента не учитывается)
+// и читает его размеры без декодирования всего изображения
+func Inspect(data []byte) (Info, error) {
+	contentType := http.DetectContentType(data)
+	ext, ok := extensions[contentType]
+	if !ok {
+		return Info{}, ErrUnsupportedType
+	}
+
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || "image/"+format != contentType {
+		return Info{}, ErrUnsupportedType
+	}
+	if config.Width <= 0 || config.Height <= 0 {
+		return Info{}, ErrUnsupportedType
+	}
+	if int64(config.Width)*int64(config.Height) > MaxPixels {
+		return Info{}, ErrTooLarge
+	}
+
+	return Info{ContentType: contentType, Extension: ext, Width: config.Width, Height: config.Height}, nil
+}
+
+// Thumbnail уменьшает изображение так, чтобы оно помещалось в квадрат maxSize x maxSize; меньшие не увеличиваются.
+// Изображения с прозрачностью (PNG, GIF) сохраняются в PNG, остальные - в JPEG; у GIF берется первый кадр.
+// Возвращает данные миниатюры, ее тип и размеры.
+func Thumbnail(data []byte, info Info, maxSize int) ([]byte, Info, error) {
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, Info{}, ErrUnsupportedType
+	}
+
+	width, height := fit(src.Bounds().Dx(), src.Bounds().Dy(), maxSize)
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	xdraw.CatmullRom.Scale(dst, dst.Bounds(), src, src.Bounds(), xdraw.Src, nil)
+
+	var buf bytes.Buffer
+	thumb := Info{Width: width, Height: height}
+	switch info.ContentType {
+	case "image/png", "image/gif":
+		thumb.ContentType, thumb.Extension = "image/png", ".png"
+		err = png.Encode(&buf, dst)
+	default:
+		thumb.ContentType, thumb.Extension = "image/jpeg", ".jpg"
+		err = jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 85})
+	}
+	if err != nil {
+		return nil, Info{}, err
+	}
+	return buf.Bytes(), thumb, nil
+}
+
+// fit возвращает размеры, уменьшенные с сохранением пропорций до maxSize по большей стороне
+func fit(width, height, maxSize int) (int, int) {
+	if maxSize <= 0 || width <= maxSize && height <= maxSize {
+		return width, height
+	}
+	if width >= height {
+		return maxSize, max(1, height*maxSize/width)
+	}
+	return max(1, width*maxSize/height), maxSize
+}
@@ -0,0 +1,50 @@
+package media
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func encodePNG(t *testing.T, width, height int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	img.Set(0, 0, color.RGBA{R: 255, A: 255})
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func TestInspectAndThumbnail(t *testing.T) {
+	data := encodePNG(t, 800, 400)
+
+	info, err := Inspect(data)
+	require.NoError(t, err)
+	assert.Equal(t, Info{ContentType: "image/png", Extension: ".png", Width: 800, Height: 400}, info)
+
+	thumb, thumbInfo, err := Thumbnail(data, info, 320)
+	require.NoError(t, err)
+	assert.Equal(t, Info{ContentType: "image/png", Extension: ".png", Width: 320, Height: 160}, thumbInfo)
+
+	decoded, err := png.Decode(bytes.NewReader(thumb))
+	require.NoError(t, err)
+	assert.Equal(t, 320, decoded.Bounds().Dx())
+
+	// маленькие изображения не увеличиваются
+	_, thumbInfo, err = Thumbnail(encodePNG(t, 100, 50), Info{ContentType: "image/png"}, 320)
+	require.NoError(t, err)
+	assert.Equal(t, 100, thumbInfo.Width)
+}
+
+func TestInspectRejectsNonImages(t *testing.T) {
+	_, err := Inspect([]byte("<html><script>alert(1)</script></html>"))
+	assert.ErrorIs(t, err, ErrUnsupportedType)
+
+	// заголовок PNG без корректного содержимого
+	_, err = Inspect(append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 32)...))
+	assert.ErrorIs(t, err, ErrUnsupportedType)
+}
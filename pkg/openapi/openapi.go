@@ -0,0 +1,351 @@
+// Package openapi описывает API документом OpenAPI 3.1. Схемы тел запросов и ответов
+// строятся по типам Go: имена полей берутся из тегов json, ограничения - из тегов validate
+// (см. pkg/validate), поэтому описание не расходится с тем, что сервер принимает и отдает.
+package openapi
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Version - версия спецификации OpenAPI документа
+const Version = "3.1.0"
+
+// Document - документ OpenAPI
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Servers    []Server            `json:"servers,omitempty"`
+	Tags       []Tag               `json:"tags,omitempty"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+
+	// names - имена схем, заданные Register, owners - типы схем компонентов по именам
+	names  map[reflect.Type]string
+	owners map[string]reflect.Type
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+type Server struct {
+	URL string `json:"url"`
+}
+
+type Tag struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// PathItem - операции пути по методам HTTP в нижнем регистре
+type PathItem map[string]*Operation
+
+type Operation struct {
+	Tags        []string              `json:"tags,omitempty"`
+	Summary     string                `json:"summary,omitempty"`
+	Description string                `json:"description,omitempty"`
+	OperationID string                `json:"operationId"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]Response   `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+	Deprecated  bool                  `json:"deprecated,omitempty"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required,omitempty"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Headers     map[string]Header    `json:"headers,omitempty"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type Header struct {
+	Description string  `json:"description,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas,omitempty"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+type SecurityScheme struct {
+	Type        string `json:"type"`
+	Scheme      string `json:"scheme,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// Schema - JSON Schema значения. Type - строка или, для значений, допускающих null, список типов.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 any                `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	MinLength            *int64             `json:"minLength,omitempty"`
+	MaxLength            *int64             `json:"maxLength,omitempty"`
+	MinItems             *int64             `json:"minItems,omitempty"`
+	MaxItems             *int64             `json:"maxItems,omitempty"`
+	Minimum              *int64             `json:"minimum,omitempty"`
+	Maximum              *int64             `json:"maximum,omitempty"`
+}
+
+// New создает пустой документ
+func New(info Info) *Document {
+	return &Document{
+		OpenAPI:    Version,
+		Info:       info,
+		Paths:      map[string]PathItem{},
+		Components: Components{Schemas: map[string]*Schema{}, SecuritySchemes: map[string]SecurityScheme{}},
+		names:      map[reflect.Type]string{},
+		owners:     map[string]reflect.Type{},
+	}
+}
+
+var pathParam = regexp.MustCompile(`:([A-Za-z0-9_]+)`)
+
+// Path переводит путь маршрута Fiber (/news/:id) в путь OpenAPI (/news/{id})
+func Path(fiberPath string) string {
+	return pathParam.ReplaceAllString(fiberPath, "{$1}")
+}
+
+// Add добавляет операцию по методу и пути в формате Fiber. Параметры пути, не описанные
+// в op.Parameters, добавляются строковыми.
+func (d *Document) Add(method, path string, op *Operation) {
+	for _, match := range pathParam.FindAllStringSubmatch(path, -1) {
+		declared := slices.ContainsFunc(op.Parameters, func(p Parameter) bool { return p.In == "path" && p.Name == match[1] })
+		if !declared {
+			op.Parameters = append(op.Parameters, Parameter{Name: match[1], In: "path", Required: true, Schema: String()})
+		}
+	}
+
+	path = Path(path)
+	item := d.Paths[path]
+	if item == nil {
+		item = PathItem{}
+		d.Paths[path] = item
+	}
+	method = strings.ToLower(method)
+	if item[method] != nil {
+		panic(fmt.Sprintf("openapi: duplicate operation %s %s", method, path))
+	}
+	item[method] = op
+}
+
+// Register задает имя схемы компонента для типа v. Без этого схема называется именем типа,
+// поэтому типы с одинаковыми именами из разных пакетов нужно назвать явно.
+func (d *Document) Register(name string, v any) {
+	d.names[reflect.TypeOf(v)] = name
+}
+
+// Schema возвращает схему типа v. Именованные структуры добавляются в components/schemas
+// и возвращаются ссылкой, обобщенные структуры описываются на месте.
+func (d *Document) Schema(v any) *Schema {
+	return d.schema(reflect.TypeOf(v))
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func (d *Document) schema(t reflect.Type) *Schema {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t.Kind() == reflect.Struct:
+		if t.Name() == "" || strings.Contains(t.Name(), "[") {
+			return d.object(t)
+		}
+		return d.component(t)
+	case t.Kind() == reflect.Slice || t.Kind() == reflect.Array:
+		return Array(d.schema(t.Elem()))
+	case t.Kind() == reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: d.schema(t.Elem())}
+	case t.Kind() == reflect.Interface:
+		return &Schema{}
+	case t.Kind() == reflect.String:
+		return String()
+	case t.Kind() == reflect.Bool:
+		return Boolean()
+	case t.Kind() == reflect.Int64 || t.Kind() == reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case t.Kind() >= reflect.Int && t.Kind() <= reflect.Uint32:
+		return Integer()
+	case t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64:
+		return &Schema{Type: "number"}
+	}
+	panic("openapi: unsupported type " + t.String())
+}
+
+func (d *Document) component(t reflect.Type) *Schema {
+	name, ok := d.names[t]
+	if !ok {
+		name = t.Name()
+	}
+	ref := &Schema{Ref: "#/components/schemas/" + name}
+
+	if owner, ok := d.owners[name]; ok {
+		if owner != t {
+			panic(fmt.Sprintf("openapi: schema name %s is already used by %s, register %s with another name", name, owner, t))
+		}
+		return ref
+	}
+	// владелец запоминается до построения схемы: рекурсивные типы ссылаются на себя
+	d.owners[name] = t
+	d.Components.Schemas[name] = d.object(t)
+	return ref
+}
+
+func (d *Document) object(t reflect.Type) *Schema {
+	object := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	d.fields(object, t)
+	return object
+}
+
+func (d *Document) fields(object *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" || !field.IsExported() && !field.Anonymous {
+			continue
+		}
+		// поля встроенной структуры без тега json становятся полями объекта
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			d.fields(object, field.Type)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		schema := d.schema(field.Type)
+		rules := strings.Split(field.Tag.Get("validate"), ",")
+		constrain(schema, rules)
+		if field.Type.Kind() == reflect.Pointer && !strings.Contains(options, "omitempty") {
+			schema = nullable(schema)
+		}
+		object.Properties[name] = schema
+		if slices.Contains(rules, "required") {
+			object.Required = append(object.Required, name)
+		}
+	}
+}
+
+// constrain переносит правила pkg/validate в ограничения схемы
+func constrain(schema *Schema, rules []string) {
+	for i, rule := range rules {
+		key, param, _ := strings.Cut(strings.TrimSpace(rule), "=")
+		switch key {
+		case "min", "max":
+			limit, err := strconv.ParseInt(param, 10, 64)
+			if err != nil {
+				panic(fmt.Sprintf("openapi: invalid %s=%s", key, param))
+			}
+			setBound(schema, key, limit)
+		case "oneof":
+			for _, value := range strings.Fields(param) {
+				schema.Enum = append(schema.Enum, value)
+			}
+		case "dive":
+			if schema.Items != nil {
+				constrain(schema.Items, rules[i+1:])
+			}
+			return
+		}
+	}
+}
+
+func setBound(schema *Schema, key string, limit int64) {
+	var min, max **int64
+	switch typeName(schema) {
+	case "string":
+		min, max = &schema.MinLength, &schema.MaxLength
+	case "array":
+		min, max = &schema.MinItems, &schema.MaxItems
+	case "integer", "number":
+		min, max = &schema.Minimum, &schema.Maximum
+	default:
+		return
+	}
+	if key == "min" {
+		*min = &limit
+	} else {
+		*max = &limit
+	}
+}
+
+func typeName(schema *Schema) string {
+	switch t := schema.Type.(type) {
+	case string:
+		return t
+	case []string:
+		return t[0]
+	}
+	return ""
+}
+
+// nullable разрешает значению схемы быть null
+func nullable(schema *Schema) *Schema {
+	if t, ok := schema.Type.(string); ok {
+		schema.Type = []string{t, "null"}
+		if schema.Enum != nil {
+			schema.Enum = append(schema.Enum, nil)
+		}
+	}
+	return schema
+}
+
+// String возвращает схему строки
+func String() *Schema {
+	return &Schema{Type: "string"}
+}
+
+// Integer возвращает схему целого числа
+func Integer() *Schema {
+	return &Schema{Type: "integer"}
+}
+
+// Boolean возвращает схему логического значения
+func Boolean() *Schema {
+	return &Schema{Type: "boolean"}
+}
+
+// Array возвращает схему массива элементов items
+func Array(items *Schema) *Schema {
+	return &Schema{Type: "array", Items: items}
+}
+
+// Object возвращает схему объекта со свойствами properties
+func Object(properties map[string]*Schema, required ...string) *Schema {
+	return &Schema{Type: "object", Properties: properties, Required: required}
+}
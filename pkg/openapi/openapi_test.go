@@ -0,0 +1,70 @@
+package openapi
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type node struct {
+	Name     string `json:"name" validate:"required,max=100"`
+	ParentID *int64 `json:"parent_id"`
+	Children []node `json:"children"`
+}
+
+type request struct {
+	Format    *string   `json:"format" validate:"omitempty,oneof=html markdown"`
+	Ids       []int64   `json:"ids" validate:"dive,min=1"`
+	CreatedAt time.Time `json:"created_at"`
+	Secret    string    `json:"-"`
+}
+
+type page[T any] struct {
+	Data T `json:"data"`
+}
+
+func TestSchema(t *testing.T) {
+	doc := New(Info{Title: "test", Version: "1"})
+	doc.Register("Node", node{})
+
+	assert.Equal(t, &Schema{Ref: "#/components/schemas/Node"}, doc.Schema(page[node]{}).Properties["data"])
+	assert.Equal(t, &Schema{
+		Type:     "object",
+		Required: []string{"name"},
+		Properties: map[string]*Schema{
+			"name":      {Type: "string", MaxLength: ptr(100)},
+			"parent_id": {Type: []string{"integer", "null"}, Format: "int64"},
+			"children":  {Type: "array", Items: &Schema{Ref: "#/components/schemas/Node"}},
+		},
+	}, doc.Components.Schemas["Node"])
+
+	doc.Schema(&request{})
+	assert.Equal(t, &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"format":     {Type: []string{"string", "null"}, Enum: []any{"html", "markdown", nil}},
+			"ids":        {Type: "array", Items: &Schema{Type: "integer", Format: "int64", Minimum: ptr(1)}},
+			"created_at": {Type: "string", Format: "date-time"},
+		},
+	}, doc.Components.Schemas["request"])
+}
+
+func TestAdd(t *testing.T) {
+	doc := New(Info{Title: "test", Version: "1"})
+	doc.Add("GET", "/news/:id/related", &Operation{OperationID: "related"})
+
+	body, err := json.Marshal(doc.Paths)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"/news/{id}/related": {"get": {
+		"operationId": "related",
+		"parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "string"}}],
+		"responses": null
+	}}}`, string(body))
+	assert.Panics(t, func() { doc.Add("get", "/news/:id/related", &Operation{}) })
+}
+
+func ptr(v int64) *int64 {
+	return &v
+}
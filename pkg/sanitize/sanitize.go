@@ -0,0 +1,203 @@
+package sanitize
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/microcosm-cc/bluemonday"
+	"golang.org/x/net/html"
+)
+
+// Политики очистки HTML
+const (
+	// Strict - для публичных правок: базовое форматирование, списки, цитаты, код и ссылки с rel="nofollow"
+	Strict = "strict"
+	// Relaxed - для редакторов: дополнительно заголовки, изображения и таблицы
+	Relaxed = "relaxed"
+)
+
+// DefaultStrictTags теги, разрешенные политикой Strict по умолчанию
+var DefaultStrictTags = []string{
+	"p", "br", "b", "strong", "i", "em", "u", "s", "del", "sub", "sup",
+	"ul", "ol", "li", "blockquote", "code", "pre", "a",
+}
+
+// DefaultRelaxedTags теги, разрешенные политикой Relaxed по умолчанию
+var DefaultRelaxedTags = append(append([]string{}, DefaultStrictTags...),
+	"h1", "h2", "h3", "h4", "h5", "h6", "hr", "span", "div", "figure", "figcaption", "img",
+	"table", "thead", "tbody", "tfoot", "tr", "th", "td",
+)
+
+var (
+	headingIDPattern     = regexp.MustCompile(`^[\p{L}\p{N}_-]+$`)
+	codeLanguagePattern  = regexp.MustCompile(`^language-[\w+-]+$`)
+	dimensionPattern     = regexp.MustCompile(`^[0-9]{1,4}$`)
+	tableAlignPattern    = regexp.MustCompile(`^(left|center|right)$`)
+	listStartPattern     = regexp.MustCompile(`^[0-9]{1,6}$`)
+	headingElements      = []string{"h1", "h2", "h3", "h4", "h5", "h6"}
+	tableCellElements    = []string{"th", "td"}
+	plainTextSkipElement = map[string]bool{"script": true, "style": true, "template": true, "iframe": true, "noscript": true}
+)
+
+// blockElements - элементы, границы которых в тексте заменяются переводом строки
+var blockElements = map[string]bool{
+	"p": true, "br": true, "div": true, "li": true, "blockquote": true, "pre": true, "hr": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"tr": true, "table": true, "ul": true, "ol": true, "figure": true, "figcaption": true,
+}
+
+// Config списки разрешенных тегов политик; пустой список означает значения по умолчанию.
+// Атрибуты для каждого тега фиксированы, ссылки допускают только http, https, mailto и относительные адреса.
+type Config struct {
+	StrictTags  []string
+	RelaxedTags []string
+}
+
+// Sanitizer очищает HTML по именованным политикам
+type Sanitizer struct {
+	policies map[string]*bluemonday.Policy
+}
+
+func New(cfg Config) *Sanitizer {
+	if len(cfg.StrictTags) == 0 {
+		cfg.StrictTags = DefaultStrictTags
+	}
+	if len(cfg.RelaxedTags) == 0 {
+		cfg.RelaxedTags = DefaultRelaxedTags
+	}
+
+	strict := newPolicy(cfg.StrictTags)
+	strict.RequireNoFollowOnLinks(true)
+	strict.RequireNoReferrerOnLinks(true)
+
+	return &Sanitizer{
+		policies: map[string]*bluemonday.Policy{
+			Strict:  strict,
+			Relaxed: newPolicy(cfg.RelaxedTags),
+		},
+	}
+}
+
+// newPolicy создает политику с разрешенными тегами и фиксированным набором их атрибутов
+func newPolicy(tags []string) *bluemonday.Policy {
+	allowed := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		allowed[strings.ToLower(strings.TrimSpace(tag))] = true
+	}
+	only := func(elements ...string) []string {
+		var result []string
+		for _, e := range elements {
+			if allowed[e] {
+				result = append(result, e)
+			}
+		}
+		return result
+	}
+
+	p := bluemonday.NewPolicy()
+	p.AllowStandardURLs()
+	for tag := range allowed {
+		if tag != "" {
+			p.AllowElements(tag)
+		}
+	}
+
+	if e := only("a"); len(e) > 0 {
+		p.AllowAttrs("href", "title").OnElements(e...)
+	}
+	if e := only("img"); len(e) > 0 {
+		p.AllowAttrs("src", "alt", "title").OnElements(e...)
+		p.AllowAttrs("width", "height").Matching(dimensionPattern).OnElements(e...)
+	}
+	if e := only(headingElements...); len(e) > 0 {
+		p.AllowAttrs("id").Matching(headingIDPattern).OnElements(e...)
+	}
+	if e := only("code"); len(e) > 0 {
+		p.AllowAttrs("class").Matching(codeLanguagePattern).OnElements(e...)
+	}
+	if e := only("ol"); len(e) > 0 {
+		p.AllowAttrs("start").Matching(listStartPattern).OnElements(e...)
+	}
+	if e := only(tableCellElements...); len(e) > 0 {
+		p.AllowAttrs("colspan", "rowspan").Matching(dimensionPattern).OnElements(e...)
+		p.AllowAttrs("align").Matching(tableAlignPattern).OnElements(e...)
+	}
+
+	return p
+}
+
+// HasPolicy проверяет, что политика с таким именем существует
+func (s *Sanitizer) HasPolicy(policy string) bool {
+	_, ok := s.policies[policy]
+	return ok
+}
+
+// Sanitize удаляет из HTML все теги и атрибуты, не разрешенные политикой
+func (s *Sanitizer) Sanitize(policy, content string) (string, error) {
+	p, ok := s.policies[policy]
+	if !ok {
+		return "", fmt.Errorf("unknown sanitize policy %q", policy)
+	}
+	return p.Sanitize(content), nil
+}
+
+// PlainText возвращает текст HTML без разметки: границы блоков заменяются переводами строк,
+// пробелы внутри строк схлопываются, содержимое script и style отбрасывается.
+// Переводы строк в тексте сохраняются только внутри pre.
+func PlainText(content string) string {
+	var sb strings.Builder
+	skip, pre := 0, 0
+
+	tokenizer := html.NewTokenizer(strings.NewReader(content))
+	for {
+		tt := tokenizer.Next()
+		switch tt {
+		case html.ErrorToken:
+			return normalizeText(sb.String())
+		case html.TextToken:
+			if skip == 0 {
+				text := string(tokenizer.Text())
+				if pre == 0 {
+					text = strings.ReplaceAll(text, "\n", " ")
+				}
+				sb.WriteString(text)
+			}
+		case html.StartTagToken, html.EndTagToken, html.SelfClosingTagToken:
+			name, _ := tokenizer.TagName()
+			tag := string(name)
+			if plainTextSkipElement[tag] {
+				if tt == html.StartTagToken {
+					skip++
+				} else if skip > 0 {
+					skip--
+				}
+				continue
+			}
+			if tag == "pre" {
+				if tt == html.StartTagToken {
+					pre++
+				} else if tt == html.EndTagToken && pre > 0 {
+					pre--
+				}
+			}
+			if blockElements[tag] {
+				sb.WriteByte('\n')
+			} else if tag == "td" || tag == "th" || tag == "img" {
+				sb.WriteByte(' ')
+			}
+		}
+	}
+}
+
+// normalizeText схлопывает пробелы в строках и удаляет пустые строки
+func normalizeText(text string) string {
+	lines := strings.Split(text, "\n")
+	result := lines[:0]
+	for _, line := range lines {
+		if line = strings.Join(strings.Fields(line), " "); line != "" {
+			result = append(result, line)
+		}
+	}
+	return strings.Join(result, "\n")
+}
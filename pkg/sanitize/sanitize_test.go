@@ -0,0 +1,58 @@
+package sanitize
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSanitizePolicies(t *testing.T) {
+	s := New(Config{})
+
+	tests := []struct {
+		name     string
+		policy   string
+		input    string
+		expected string
+	}{
+		{"Basic formatting is kept", Strict, `<p><b>foo</b> <em>bar</em></p>`, `<p><b>foo</b> <em>bar</em></p>`},
+		{"Script is removed with content", Strict, `<p>a</p><script>alert(1)</script>`, `<p>a</p>`},
+		{"Event handlers are removed", Relaxed, `<img src="/a.png" onerror="alert(1)">`, `<img src="/a.png">`},
+		{"javascript URLs are removed", Relaxed, `<a href="javascript:alert(1)">x</a>`, `x`},
+		{"Strict links get nofollow", Strict, `<a href="https://example.com">x</a>`, `<a href="https://example.com" rel="nofollow noreferrer">x</a>`},
+		{"Strict drops headings and images", Strict, `<h2 id="a">T</h2><img src="/a.png">`, `T`},
+		{"Relaxed keeps headings with anchors", Relaxed, `<h2 id="заголовок-1" style="color:red">T</h2>`, `<h2 id="заголовок-1">T</h2>`},
+		{"Code language class", Relaxed, `<pre><code class="language-go" data-x="1">x</code></pre>`, `<pre><code class="language-go">x</code></pre>`},
+		{"Other classes are removed", Strict, `<code class="evil">x</code>`, `<code>x</code>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := s.Sanitize(tt.policy, tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+
+	_, err := s.Sanitize("unknown", "x")
+	assert.Error(t, err)
+}
+
+func TestSanitizeConfiguredTags(t *testing.T) {
+	s := New(Config{StrictTags: []string{"b", " I "}})
+
+	result, err := s.Sanitize(Strict, `<b>a</b><i>b</i><p>c</p>`)
+	require.NoError(t, err)
+	assert.Equal(t, `<b>a</b><i>b</i>c`, result)
+}
+
+func TestPlainText(t *testing.T) {
+	input := `<h1>Title</h1><p>First   <b>bold</b>&amp;more</p><ul><li>one</li><li>two</li></ul>` +
+		`<style>p{color:red}</style><p>a<br>b</p>`
+
+	assert.Equal(t, "Title\nFirst bold&more\none\ntwo\na\nb", PlainText(input))
+	assert.Equal(t, "", PlainText(""))
+	assert.Equal(t, "plain text", PlainText("  plain \n\n text "))
+	assert.Equal(t, "a\nb", PlainText("<pre>a\nb</pre>"))
+}
@@ -0,0 +1,69 @@
+package slug
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// MaxLength - максимальная длина slug без суффикса коллизии
+const MaxLength = 80
+
+// cyrillic транслитерация кириллицы (русский и украинский алфавиты), близкая к ГОСТ 7.79-2000 (схема Б)
+var cyrillic = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e", 'ж': "zh",
+	'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+	'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts",
+	'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu",
+	'я': "ya", 'і': "i", 'ї': "yi", 'є': "ye", 'ґ': "g",
+}
+
+// Make формирует slug из строки: нижний регистр, транслитерация кириллицы, латиница без диакритики,
+// цифры; остальные символы заменяются дефисом. Результат может быть пустым.
+func Make(value string) string {
+	var sb strings.Builder
+	dash := false
+	write := func(s string) {
+		if s == "" {
+			return
+		}
+		if dash && sb.Len() > 0 {
+			sb.WriteByte('-')
+		}
+		dash = false
+		sb.WriteString(s)
+	}
+
+	for _, r := range norm.NFC.String(strings.ToLower(value)) {
+		if t, ok := cyrillic[r]; ok {
+			write(t)
+			continue
+		}
+		// NFD отделяет диакритические знаки от латинских букв: "é" -> "e" + U+0301
+		for _, d := range norm.NFD.String(string(r)) {
+			switch {
+			case d >= 'a' && d <= 'z' || d >= '0' && d <= '9':
+				write(string(d))
+			case unicode.Is(unicode.Mn, d), d == '\'', d == '’':
+				// диакритика и апостроф не разделяют слово
+			default:
+				dash = true
+			}
+		}
+	}
+
+	return truncate(sb.String(), MaxLength)
+}
+
+// truncate обрезает slug до max символов по границе слова, если она есть
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	s = s[:max]
+	if i := strings.LastIndexByte(s, '-'); i > max/2 {
+		s = s[:i]
+	}
+	return strings.Trim(s, "-")
+}
@@ -0,0 +1,32 @@
+package slug
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMake(t *testing.T) {
+	cases := map[string]string{
+		"Новости дня":              "novosti-dnya",
+		"Щука, ёж и объявление":    "shchuka-ezh-i-obyavlenie",
+		"Чемпионат мира — 2026!":   "chempionat-mira-2026",
+		"  Hello, World!  ":        "hello-world",
+		"Café Crème":               "cafe-creme",
+		"Київ і Львів":             "kiyiv-i-lviv",
+		"Don't stop":               "dont-stop",
+		"!!!":                      "",
+		"Хоккей: ЦСКА — СКА (3:2)": "khokkey-tsska-ska-3-2",
+		"Юбилейный выпуск \"Итоги года\"": "yubileynyy-vypusk-itogi-goda",
+	}
+	for input, expected := range cases {
+		assert.Equal(t, expected, Make(input), input)
+	}
+}
+
+func TestMakeTruncatesAtWordBoundary(t *testing.T) {
+	s := Make(strings.Repeat("слово ", 30))
+	assert.LessOrEqual(t, len(s), MaxLength)
+	assert.True(t, strings.HasSuffix(s, "slovo"), s)
+}
@@ -0,0 +1,68 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Local хранит файлы в каталоге Dir, файлы раздаются по адресу BaseURL (например, "/media")
+type Local struct {
+	Dir     string
+	BaseURL string
+}
+
+var _ Storage = (*Local)(nil)
+
+// NewLocal создает хранилище в каталоге dir
+func NewLocal(dir, baseURL string) *Local {
+	return &Local{Dir: dir, BaseURL: strings.TrimRight(baseURL, "/")}
+}
+
+// Put записывает файл через временный файл и переименование, чтобы читатели не видели его частично записанным
+func (s *Local) Put(_ context.Context, key, _ string, data []byte) error {
+	if err := checkKey(key); err != nil {
+		return err
+	}
+
+	filename := filepath.Join(s.Dir, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(filename), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(filename), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filename)
+}
+
+func (s *Local) Delete(_ context.Context, key string) error {
+	if err := checkKey(key); err != nil {
+		return err
+	}
+
+	err := os.Remove(filepath.Join(s.Dir, filepath.FromSlash(key)))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func (s *Local) URL(key string) string {
+	return s.BaseURL + "/" + key
+}
@@ -0,0 +1,32 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalPutAndDelete(t *testing.T) {
+	dir := t.TempDir()
+	s := NewLocal(dir, "/media/")
+	ctx := context.Background()
+
+	require.NoError(t, s.Put(ctx, "news/1/photo.jpg", "image/jpeg", []byte("jpeg")))
+	data, err := os.ReadFile(filepath.Join(dir, "news", "1", "photo.jpg"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("jpeg"), data)
+	assert.Equal(t, "/media/news/1/photo.jpg", s.URL("news/1/photo.jpg"))
+
+	require.NoError(t, s.Delete(ctx, "news/1/photo.jpg"))
+	require.NoError(t, s.Delete(ctx, "news/1/photo.jpg"))
+	_, err = os.Stat(filepath.Join(dir, "news", "1", "photo.jpg"))
+	assert.True(t, os.IsNotExist(err))
+
+	for _, key := range []string{"", "/etc/passwd", "../x", "news/../../x", "news//x"} {
+		assert.ErrorIs(t, s.Put(ctx, key, "", nil), ErrInvalidKey, key)
+	}
+}
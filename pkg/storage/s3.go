@@ -0,0 +1,203 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// S3Config - параметры S3-совместимого хранилища (AWS S3, MinIO, Yandex Object Storage...)
+type S3Config struct {
+	// Endpoint - адрес API, например "https://s3.eu-central-1.amazonaws.com" или "http://minio:9000"
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	// PathStyle - адреса вида endpoint/bucket/key вместо bucket.endpoint/key (нужно MinIO)
+	PathStyle bool
+	// PublicURL - адрес, по которому файлы доступны читателям (CDN), по умолчанию адрес объекта в API
+	PublicURL string
+}
+
+// S3 хранит файлы в бакете S3-совместимого хранилища. Запросы подписываются AWS Signature Version 4.
+type S3 struct {
+	config S3Config
+	client *http.Client
+	// now - текущее время для подписи, подменяется в тестах
+	now func() time.Time
+}
+
+var _ Storage = (*S3)(nil)
+
+// NewS3 создает S3 хранилище; client = nil - http.Client с таймаутом 30 секунд
+func NewS3(config S3Config, client *http.Client) *S3 {
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	if config.Region == "" {
+		config.Region = "us-east-1"
+	}
+	config.Endpoint = strings.TrimRight(config.Endpoint, "/")
+	config.PublicURL = strings.TrimRight(config.PublicURL, "/")
+	return &S3{config: config, client: client, now: time.Now}
+}
+
+func (s *S3) Put(ctx context.Context, key, contentType string, data []byte) error {
+	if err := checkKey(key); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.objectURL(key), bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	return s.do(req, data, http.StatusOK)
+}
+
+func (s *S3) Delete(ctx context.Context, key string) error {
+	if err := checkKey(key); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, s.objectURL(key), nil)
+	if err != nil {
+		return err
+	}
+	// S3 отвечает 204 и для отсутствующего объекта, часть совместимых хранилищ - 404
+	return s.do(req, nil, http.StatusNoContent, http.StatusOK, http.StatusNotFound)
+}
+
+func (s *S3) URL(key string) string {
+	if s.config.PublicURL != "" {
+		return s.config.PublicURL + "/" + escapePath(key)
+	}
+	return s.objectURL(key)
+}
+
+// objectURL возвращает адрес объекта в API хранилища
+func (s *S3) objectURL(key string) string {
+	if s.config.PathStyle {
+		return s.config.Endpoint + "/" + s.config.Bucket + "/" + escapePath(key)
+	}
+
+	endpoint, err := url.Parse(s.config.Endpoint)
+	if err != nil || endpoint.Host == "" {
+		return s.config.Endpoint + "/" + s.config.Bucket + "/" + escapePath(key)
+	}
+	return endpoint.Scheme + "://" + s.config.Bucket + "." + endpoint.Host + "/" + escapePath(key)
+}
+
+// do подписывает и выполняет запрос, ответ с другим статусом возвращается как ошибка с телом ответа
+func (s *S3) do(req *http.Request, payload []byte, okStatuses ...int) error {
+	s.sign(req, payload)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	for _, status := range okStatuses {
+		if resp.StatusCode == status {
+			return nil
+		}
+	}
+	return fmt.Errorf("storage: s3 %s %s: %s: %s", req.Method, req.URL.Path, resp.Status, strings.TrimSpace(string(body)))
+}
+
+// sign добавляет к запросу заголовки x-amz-date, x-amz-content-sha256 и Authorization (AWS Signature Version 4).
+// Подписываются host и все заголовки запроса.
+func (s *S3) sign(req *http.Request, payload []byte) {
+	now := s.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+
+	payloadHash := sha256Hex(payload)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		canonicalURI(req.URL),
+		req.URL.Query().Encode(),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + s.config.Region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, sha256Hex([]byte(canonicalRequest))}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.config.SecretKey), date)
+	key = hmacSHA256(key, s.config.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.config.AccessKey, scope, signedHeaders, signature))
+}
+
+// canonicalURI возвращает путь запроса, закодированный по правилам подписи S3
+func canonicalURI(u *url.URL) string {
+	if u.Path == "" {
+		return "/"
+	}
+	return escapePath(u.Path)
+}
+
+// escapePath кодирует все символы пути, кроме незарезервированных (RFC 3986) и "/"
+func escapePath(p string) string {
+	var sb strings.Builder
+	for i := 0; i < len(p); i++ {
+		c := p[i]
+		if 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' ||
+			c == '-' || c == '_' || c == '.' || c == '~' || c == '/' {
+			sb.WriteByte(c)
+		} else {
+			fmt.Fprintf(&sb, "%%%02X", c)
+		}
+	}
+	return sb.String()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,35 @@
+// Package storage содержит хранилища загруженных файлов: локальный каталог и S3-совместимое хранилище.
+package storage
+
+import (
+	"context"
+	"errors"
+	"path"
+	"strings"
+)
+
+// Storage хранит файлы по ключу вида "news/1/5f2c...jpg" и формирует их публичные адреса
+type Storage interface {
+	// Put сохраняет файл, существующий файл с тем же ключом заменяется
+	Put(ctx context.Context, key, contentType string, data []byte) error
+	// Delete удаляет файл, отсутствие файла ошибкой не считается
+	Delete(ctx context.Context, key string) error
+	// URL возвращает публичный адрес файла
+	URL(key string) string
+}
+
+// ErrInvalidKey - ключ пустой, абсолютный или выходит за пределы хранилища
+var ErrInvalidKey = errors.New("storage: invalid key")
+
+// checkKey проверяет, что ключ - относительный путь без "." и ".." в сегментах
+func checkKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || path.Clean(key) != key {
+		return ErrInvalidKey
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "." || segment == ".." {
+			return ErrInvalidKey
+		}
+	}
+	return nil
+}
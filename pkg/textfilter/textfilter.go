@@ -0,0 +1,69 @@
+// Package textfilter проверяет пользовательский текст на запрещенные слова и ссылки
+package textfilter
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Причины, по которым текст не прошел проверку
+const (
+	ReasonBlockedWord = "blocked_word"
+	ReasonLink        = "link"
+)
+
+// linkPattern находит адреса: со схемой, с www. и домены в распространенных зонах
+var linkPattern = regexp.MustCompile(`(?i)https?://|\bwww\.|\b[a-z0-9-]+\.(?:com|net|org|info|biz|io|me|co|ru|su|ua|by|kz|xyz|online|site|top|link|club)\b|[\p{L}\d-]+\.рф`)
+
+// Filter проверяет текст по списку запрещенных слов и, если ссылки запрещены, на ссылки
+type Filter struct {
+	words      map[string]bool
+	prefixes   []string
+	allowLinks bool
+}
+
+// New создает фильтр. Слова сравниваются без учета регистра и различия "е" и "ё";
+// слово со звездочкой в конце ("спам*") запрещает все слова с этим началом.
+func New(words []string, allowLinks bool) *Filter {
+	f := &Filter{words: make(map[string]bool), allowLinks: allowLinks}
+	for _, word := range words {
+		word = normalize(strings.TrimSpace(word))
+		if prefix, ok := strings.CutSuffix(word, "*"); ok {
+			if prefix != "" {
+				f.prefixes = append(f.prefixes, prefix)
+			}
+			continue
+		}
+		if word != "" {
+			f.words[word] = true
+		}
+	}
+	return f
+}
+
+// Check возвращает причину, по которой текст не прошел проверку, или пустую строку
+func (f *Filter) Check(text string) string {
+	words := strings.FieldsFunc(normalize(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		if f.words[word] {
+			return ReasonBlockedWord
+		}
+		for _, prefix := range f.prefixes {
+			if strings.HasPrefix(word, prefix) {
+				return ReasonBlockedWord
+			}
+		}
+	}
+
+	if !f.allowLinks && linkPattern.MatchString(text) {
+		return ReasonLink
+	}
+	return ""
+}
+
+func normalize(s string) string {
+	return strings.ReplaceAll(strings.ToLower(s), "ё", "е")
+}
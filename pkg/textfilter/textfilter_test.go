@@ -0,0 +1,31 @@
+package textfilter
+
+import "testing"
+
+func TestCheck(t *testing.T) {
+	f := New([]string{"Спам", "казино*", " ", "*"}, false)
+
+	tests := []struct {
+		text, want string
+	}{
+		{"Отличная новость", ""},
+		{"Это СПАМ!", ReasonBlockedWord},
+		{"спамер пишет", ""},
+		{"лучшие казиноонлайн", ReasonBlockedWord},
+		{"подробности на https://example.org/page", ReasonLink},
+		{"заходите www.example", ReasonLink},
+		{"пишите на shop.ru", ReasonLink},
+		{"сайт новости.рф", ReasonLink},
+		{"счет 2.1 в пользу хозяев", ""},
+		{"т.е. все ясно", ""},
+	}
+	for _, tt := range tests {
+		if got := f.Check(tt.text); got != tt.want {
+			t.Errorf("Check(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+
+	if got := New(nil, true).Check("https://example.org"); got != "" {
+		t.Errorf("links allowed: Check = %q, want empty", got)
+	}
+}
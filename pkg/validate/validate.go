@@ -0,0 +1,252 @@
+// Package validate проверяет поля структур по тегам `validate:"..."`.
+//
+// Поддерживаемые правила, через запятую:
+//   - required - значение задано: строка не пустая после удаления пробелов, указатель и срез не nil
+//   - omitempty - остальные правила не проверяются для пустого значения
+//   - min=N, max=N - длина строки в символах (не байтах), длина среза или значение числа
+//   - oneof=a b c - строка равна одному из значений
+//   - dive - следующие правила применяются к каждому элементу среза
+//
+// Имя поля в ошибках берется из тега json. Теги разбираются один раз для каждого типа;
+// некорректный тег возвращается ошибкой из Struct и Check.
+package validate
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Коды ошибок полей, совпадают с названиями правил
+const (
+	CodeRequired = "required"
+	CodeMin      = "min"
+	CodeMax      = "max"
+	CodeOneOf    = "oneof"
+)
+
+// FieldError - ошибка одного поля
+type FieldError struct {
+	Field   string
+	Code    string
+	Message string
+}
+
+type rule struct {
+	key     string
+	limit   int64
+	allowed []string
+}
+
+type fieldRules struct {
+	index int
+	name  string
+	rules []rule
+}
+
+type compiled struct {
+	fields []fieldRules
+	err    error
+}
+
+// cache - разобранные правила по типам структур
+var cache sync.Map
+
+// Struct проверяет поля структуры (или указателя на нее) и возвращает все ошибки полей, nil - ошибок нет.
+// Ошибка означает некорректный тег или не структуру - ошибку программы, а не данных.
+func Struct(v any) ([]FieldError, error) {
+	value := reflect.Indirect(reflect.ValueOf(v))
+	if value.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("validate: %T is not a struct", v)
+	}
+
+	fields, err := rulesFor(value.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []FieldError
+	for _, field := range fields {
+		errs = append(errs, check(field.name, value.Field(field.index), field.rules)...)
+	}
+	return errs, nil
+}
+
+// Check проверяет теги структуры v без проверки значений, чтобы найти ошибки в тегах при запуске
+func Check(v any) error {
+	typ := reflect.TypeOf(v)
+	for typ != nil && typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if typ == nil || typ.Kind() != reflect.Struct {
+		return fmt.Errorf("validate: %T is not a struct", v)
+	}
+	_, err := rulesFor(typ)
+	return err
+}
+
+func rulesFor(typ reflect.Type) ([]fieldRules, error) {
+	if cached, ok := cache.Load(typ); ok {
+		return cached.(compiled).fields, cached.(compiled).err
+	}
+
+	fields, err := compile(typ)
+	cache.Store(typ, compiled{fields: fields, err: err})
+	return fields, err
+}
+
+func compile(typ reflect.Type) ([]fieldRules, error) {
+	var fields []fieldRules
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		tag := field.Tag.Get("validate")
+		if tag == "" || !field.IsExported() {
+			continue
+		}
+
+		rules, err := parseRules(field.Type, strings.Split(tag, ","))
+		if err != nil {
+			return nil, fmt.Errorf("validate: %s.%s: %w", typ, field.Name, err)
+		}
+		fields = append(fields, fieldRules{index: i, name: fieldName(field), rules: rules})
+	}
+	return fields, nil
+}
+
+// parseRules разбирает правила и проверяет, что они применимы к типу поля
+func parseRules(typ reflect.Type, tags []string) ([]rule, error) {
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	var rules []rule
+	for i, tag := range tags {
+		key, param, _ := strings.Cut(strings.TrimSpace(tag), "=")
+		switch key {
+		case "":
+		case "omitempty", CodeRequired:
+			rules = append(rules, rule{key: key})
+		case CodeMin, CodeMax:
+			limit, err := strconv.ParseInt(param, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s=%s", key, param)
+			}
+			if !isBounded(typ.Kind()) {
+				return nil, fmt.Errorf("%s is not supported for %s", key, typ.Kind())
+			}
+			rules = append(rules, rule{key: key, limit: limit})
+		case CodeOneOf:
+			if typ.Kind() != reflect.String {
+				return nil, fmt.Errorf("oneof is supported only for strings")
+			}
+			rules = append(rules, rule{key: key, allowed: strings.Fields(param)})
+		case "dive":
+			if typ.Kind() != reflect.Slice {
+				return nil, fmt.Errorf("dive is supported only for slices")
+			}
+			elemRules, err := parseRules(typ.Elem(), tags[i+1:])
+			if err != nil {
+				return nil, err
+			}
+			return append(append(rules, rule{key: key}), elemRules...), nil
+		default:
+			return nil, fmt.Errorf("unknown rule %s", key)
+		}
+	}
+	return rules, nil
+}
+
+func isBounded(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	}
+	return false
+}
+
+func fieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return field.Name
+	}
+	return name
+}
+
+func check(name string, value reflect.Value, rules []rule) []FieldError {
+	if value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			if hasRule(rules, CodeRequired) {
+				return []FieldError{{Field: name, Code: CodeRequired, Message: name + " is required"}}
+			}
+			return nil
+		}
+		value = value.Elem()
+	}
+
+	for i, rule := range rules {
+		switch rule.key {
+		case "omitempty":
+			if isEmpty(value) {
+				return nil
+			}
+		case CodeRequired:
+			if isEmpty(value) {
+				return []FieldError{{Field: name, Code: CodeRequired, Message: name + " is required"}}
+			}
+		case CodeMin, CodeMax:
+			if err := checkBound(name, value, rule); err != nil {
+				return []FieldError{*err}
+			}
+		case CodeOneOf:
+			if !slices.Contains(rule.allowed, value.String()) {
+				return []FieldError{{Field: name, Code: CodeOneOf, Message: fmt.Sprintf("%s must be one of: %s", name, strings.Join(rule.allowed, ", "))}}
+			}
+		case "dive":
+			var errs []FieldError
+			for j := 0; j < value.Len(); j++ {
+				errs = append(errs, check(fmt.Sprintf("%s[%d]", name, j), value.Index(j), rules[i+1:])...)
+			}
+			return errs
+		}
+	}
+	return nil
+}
+
+func checkBound(name string, value reflect.Value, rule rule) *FieldError {
+	var actual int64
+	var unit string
+	switch value.Kind() {
+	case reflect.String:
+		actual, unit = int64(utf8.RuneCountInString(value.String())), " characters"
+	case reflect.Slice, reflect.Map:
+		actual, unit = int64(value.Len()), " items"
+	default:
+		actual = value.Int()
+	}
+
+	if rule.key == CodeMin && actual < rule.limit {
+		return &FieldError{Field: name, Code: CodeMin, Message: fmt.Sprintf("%s must be at least %d%s", name, rule.limit, unit)}
+	}
+	if rule.key == CodeMax && actual > rule.limit {
+		return &FieldError{Field: name, Code: CodeMax, Message: fmt.Sprintf("%s must be at most %d%s", name, rule.limit, unit)}
+	}
+	return nil
+}
+
+func isEmpty(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.String:
+		return strings.TrimSpace(value.String()) == ""
+	case reflect.Slice, reflect.Map:
+		return value.IsNil()
+	}
+	return value.IsZero()
+}
+
+func hasRule(rules []rule, key string) bool {
+	return slices.ContainsFunc(rules, func(r rule) bool { return r.key == key })
+}
@@ -0,0 +1,71 @@
+package validate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type request struct {
+	Name   string   `json:"name" validate:"required,max=5"`
+	Title  *string  `json:"Title" validate:"omitempty,max=3"`
+	Format string   `json:"format" validate:"omitempty,oneof=html markdown"`
+	Tags   []string `json:"tags" validate:"dive,required,max=4"`
+	Ids    []int64  `json:"ids" validate:"dive,min=1"`
+	Note   string
+}
+
+func TestStruct(t *testing.T) {
+	title := "Тест"
+	errs, err := Struct(&request{
+		Name:   "  ",
+		Title:  &title,
+		Format: "text",
+		Tags:   []string{"спорт", "", "кино"},
+		Ids:    []int64{1, 0},
+	})
+
+	assert.Equal(t, []FieldError{
+		{Field: "name", Code: CodeRequired, Message: "name is required"},
+		{Field: "Title", Code: CodeMax, Message: "Title must be at most 3 characters"},
+		{Field: "format", Code: CodeOneOf, Message: "format must be one of: html, markdown"},
+		{Field: "tags[0]", Code: CodeMax, Message: "tags[0] must be at most 4 characters"},
+		{Field: "tags[1]", Code: CodeRequired, Message: "tags[1] is required"},
+		{Field: "ids[1]", Code: CodeMin, Message: "ids[1] must be at least 1"},
+	}, errs)
+	assert.NoError(t, err)
+}
+
+func TestStructValid(t *testing.T) {
+	// длина считается в символах: "Новос" - 5 символов, 10 байт
+	errs, err := Struct(request{Name: "Новос", Format: "markdown", Tags: []string{"кино"}})
+	assert.NoError(t, err)
+	assert.Empty(t, errs)
+}
+
+func TestStructInvalidTags(t *testing.T) {
+	for name, v := range map[string]any{
+		"неизвестное правило": struct {
+			Name string `validate:"email"`
+		}{},
+		"oneof для числа": struct {
+			Count int `validate:"oneof=1 2"`
+		}{},
+		"dive для строки": struct {
+			Name string `validate:"dive,max=3"`
+		}{},
+		"max для bool": struct {
+			Flags []bool `validate:"dive,max=1"`
+		}{},
+		"нечисловой max": struct {
+			Name *string `validate:"omitempty,max=ten"`
+		}{},
+		"не структура": "text",
+	} {
+		_, err := Struct(v)
+		assert.Error(t, err, name)
+		assert.Error(t, Check(v), name)
+	}
+
+	assert.NoError(t, Check(&request{}))
+}
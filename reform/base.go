@@ -180,6 +180,18 @@ const (
 	EmptyLists
 )
 
+// SavepointMethod is a method of creating, releasing and rolling back to savepoints.
+type SavepointMethod int
+
+const (
+	// Savepoint is a method using "SAVEPOINT", "RELEASE SAVEPOINT" and "ROLLBACK TO SAVEPOINT" SQL syntax.
+	Savepoint SavepointMethod = iota
+
+	// SaveTransaction is a method using "SAVE TRANSACTION" and "ROLLBACK TRANSACTION" SQL syntax.
+	// Savepoints are not released explicitly.
+	SaveTransaction
+)
+
 // Dialect represents differences in various SQL dialects.
 type Dialect interface {
 	// String returns dialect name.
@@ -207,6 +219,15 @@ type Dialect interface {
 	DefaultValuesMethod() DefaultValuesMethod
 }
 
+// SavepointDialect is an optional interface for Dialect with non-standard savepoint syntax.
+// Dialects which do not implement it use Savepoint method.
+type SavepointDialect interface {
+	Dialect
+
+	// SavepointMethod returns a method of creating, releasing and rolling back to savepoints.
+	SavepointMethod() SavepointMethod
+}
+
 // SetPK sets record's primary key, if possible.
 //
 // Deprecated: prefer direct field assignment where possible.
@@ -3,6 +3,7 @@ package reform
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"time"
 )
 
@@ -28,6 +29,35 @@ var _ DBInterface = (*sql.DB)(nil)
 type DB struct {
 	*Querier
 	db DBInterface
+
+	// TXAttempts is a maximum number of attempts for InTransaction and InTransactionContext
+	// when transaction fails with serialization failure (SQLSTATE 40001).
+	// Zero or one disables retries. Function passed to those methods should be safe to call several times.
+	TXAttempts int
+}
+
+type txKey struct{}
+
+// ContextWithTX returns a copy of ctx carrying given transaction.
+// DB's InTransactionContext and QuerierFromContext methods use it to join that transaction.
+func ContextWithTX(ctx context.Context, tx *TX) context.Context {
+	return context.WithValue(ctx, txKey{}, tx)
+}
+
+// TXFromContext returns transaction carried by ctx, or nil.
+func TXFromContext(ctx context.Context) *TX {
+	tx, _ := ctx.Value(txKey{}).(*TX)
+	return tx
+}
+
+// QuerierFromContext returns Querier with given context for a transaction carried by ctx
+// (see InTransactionContext), or for DB itself if there is no such transaction.
+// Code that accepts only context can use it to join the caller's transaction transparently.
+func (db *DB) QuerierFromContext(ctx context.Context) *Querier {
+	if tx := TXFromContext(ctx); tx != nil {
+		return tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
 }
 
 // NewDB creates new DB object for given SQL database connection.
@@ -76,11 +106,39 @@ func (db *DB) InTransaction(f func(t *TX) error) error {
 
 // InTransactionContext wraps function execution in transaction with given context and options (can be nil),
 // rolling back it in case of error or panic, committing otherwise.
+//
+// Transaction's context carries that transaction (see ContextWithTX), so nested calls with it
+// (or a context derived from it) do not start a new transaction, but use a savepoint within
+// the outer one with TX.InTransaction; opts are ignored in that case.
+//
+// If transaction fails with serialization failure, it is retried up to TXAttempts times.
 func (db *DB) InTransactionContext(ctx context.Context, opts *sql.TxOptions, f func(t *TX) error) error {
+	if tx := TXFromContext(ctx); tx != nil {
+		return tx.InTransaction(f)
+	}
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = db.inTransactionContext(ctx, opts, f)
+		if attempt >= db.TXAttempts || !IsSerializationFailure(err) {
+			return err
+		}
+
+		// give conflicting transactions time to finish
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(time.Duration(attempt) * 10 * time.Millisecond):
+		}
+	}
+}
+
+func (db *DB) inTransactionContext(ctx context.Context, opts *sql.TxOptions, f func(t *TX) error) error {
 	tx, err := db.BeginTx(ctx, opts)
 	if err != nil {
 		return err
 	}
+	tx.ctx = ContextWithTX(ctx, tx)
 
 	var committed bool
 	defer func() {
@@ -100,6 +158,21 @@ func (db *DB) InTransactionContext(ctx context.Context, opts *sql.TxOptions, f f
 	return err
 }
 
+// IsSerializationFailure returns true if err is a serialization failure (SQLSTATE 40001)
+// reported by the database driver. Such transactions can be safely retried.
+func IsSerializationFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	// lib/pq, pgx and other drivers which expose SQLSTATE
+	var sqlState interface{ SQLState() string }
+	if errors.As(err, &sqlState) {
+		return sqlState.SQLState() == "40001"
+	}
+	return false
+}
+
 // check interfaces
 var (
 	_ DBTX        = (*DB)(nil)
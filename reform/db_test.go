@@ -1,8 +1,10 @@
 package reform_test
 
 import (
+	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/AlekSi/pointer"
@@ -171,3 +173,92 @@ func TestInTransaction(t *testing.T) {
 	assert.NoError(t, db.Reload(person))
 	assert.NoError(t, db.Delete(person))
 }
+
+func TestNestedInTransaction(t *testing.T) {
+	db := setupDB(t)
+	defer teardown(t, db)
+
+	person1 := &Person{ID: 42, Email: pointer.ToString(gofakeit.Email())}
+	person2 := &Person{ID: 43, Email: pointer.ToString(gofakeit.Email())}
+
+	err := db.InTransactionContext(context.Background(), nil, func(tx *reform.TX) error {
+		assert.NoError(t, insertPersonWithID(t, tx.Querier, person1))
+
+		// error in nested transaction rolls back only its changes
+		err := tx.InTransaction(func(tx *reform.TX) error {
+			assert.NoError(t, insertPersonWithID(t, tx.Querier, person2))
+			return errors.New("epic error")
+		})
+		assert.EqualError(t, err, "epic error")
+		assert.Equal(t, tx.Reload(person2), reform.ErrNoRows)
+
+		// panic in nested transaction
+		assert.Panics(t, func() {
+			_ = tx.InTransaction(func(tx *reform.TX) error {
+				assert.NoError(t, insertPersonWithID(t, tx.Querier, person2))
+				panic("epic panic!")
+			})
+		})
+		assert.Equal(t, tx.Reload(person2), reform.ErrNoRows)
+
+		// transaction is carried by context, so nested call joins it
+		assert.Equal(t, tx, reform.TXFromContext(tx.Context()))
+		err = db.InTransactionContext(tx.Context(), nil, func(nested *reform.TX) error {
+			assert.Equal(t, tx, nested)
+			return insertPersonWithID(t, db.QuerierFromContext(tx.Context()), person2)
+		})
+		assert.NoError(t, err)
+		assert.NoError(t, tx.Reload(person2))
+
+		return errors.New("rollback all")
+	})
+	assert.EqualError(t, err, "rollback all")
+	assert.Equal(t, db.Reload(person1), reform.ErrNoRows)
+	assert.Equal(t, db.Reload(person2), reform.ErrNoRows)
+}
+
+type serializationFailure struct{}
+
+func (serializationFailure) Error() string    { return "could not serialize access" }
+func (serializationFailure) SQLState() string { return "40001" }
+
+func TestInTransactionRetry(t *testing.T) {
+	db := setupDB(t)
+	defer teardown(t, db)
+
+	var attempts int
+	err := db.InTransaction(func(tx *reform.TX) error {
+		attempts++
+		return fmt.Errorf("wrapped: %w", serializationFailure{})
+	})
+	assert.True(t, reform.IsSerializationFailure(err))
+	assert.Equal(t, 1, attempts)
+
+	db.TXAttempts = 3
+	attempts = 0
+	err = db.InTransaction(func(tx *reform.TX) error {
+		attempts++
+		if attempts < 2 {
+			return serializationFailure{}
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, attempts)
+
+	attempts = 0
+	err = db.InTransaction(func(tx *reform.TX) error {
+		attempts++
+		return serializationFailure{}
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 3, attempts)
+
+	attempts = 0
+	err = db.InTransaction(func(tx *reform.TX) error {
+		attempts++
+		return errors.New("epic error")
+	})
+	assert.EqualError(t, err, "epic error")
+	assert.Equal(t, 1, attempts)
+}
@@ -41,10 +41,14 @@ func (mssql) DefaultValuesMethod() reform.DefaultValuesMethod {
 	return reform.DefaultValues
 }
 
+func (mssql) SavepointMethod() reform.SavepointMethod {
+	return reform.SaveTransaction
+}
+
 // Dialect implements reform.Dialect for Microsoft SQL Server.
 //
 // Deprecated: Use sqlserver.Dialect instead. https://github.com/denisenkom/go-mssqldb#deprecated
 var Dialect mssql
 
 // check interface
-var _ reform.Dialect = Dialect
+var _ reform.SavepointDialect = Dialect
@@ -41,8 +41,12 @@ func (sqlserver) DefaultValuesMethod() reform.DefaultValuesMethod {
 	return reform.DefaultValues
 }
 
+func (sqlserver) SavepointMethod() reform.SavepointMethod {
+	return reform.SaveTransaction
+}
+
 // Dialect implements reform.Dialect for Microsoft SQL Server.
 var Dialect sqlserver
 
 // check interface
-var _ reform.Dialect = Dialect
+var _ reform.SavepointDialect = Dialect
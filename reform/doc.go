@@ -55,6 +55,27 @@
 // contexts is not recommended.
 //
 //
+// Nested transactions
+//
+// TX.InTransaction wraps function execution in a savepoint within the current transaction.
+// Transaction started by InTransactionContext is carried by its context, so code that receives
+// only context can join it with DB.QuerierFromContext, and nested InTransactionContext calls
+// use savepoints instead of starting a new transaction:
+//
+//  err := DB.InTransactionContext(ctx, nil, func(tx *reform.TX) error {
+//      // both calls use tx
+//      if err := updatePerson(tx.Context(), person); err != nil {
+//          return err
+//      }
+//      return DB.InTransactionContext(tx.Context(), nil, func(tx *reform.TX) error {
+//          // changes are rolled back to savepoint on error
+//          return tx.Insert(project)
+//      })
+//  })
+//
+// Set DB.TXAttempts to retry transactions failed with serialization failure (SQLSTATE 40001).
+//
+//
 // Tagging
 //
 // reform allows one to add tags (comments) to generated queries with WithTag Querier method.
@@ -144,6 +144,27 @@ var persons = []reform.Struct{
 	},
 }
 
+func ExampleTail_Build() {
+	// optional filters are added only when needed, placeholders are numbered by Build
+	tb := reform.NewTail().Where(reform.IsNull("end"))
+	if name := "Sweet Lightfoot"; name != "" {
+		tb.Where(reform.Ne("name", name))
+	}
+	tb.Where(reform.In("id", "baron", "queen", "traveler", "lightfoot")).OrderBy("start")
+
+	tail, args := tb.Build(DB.Dialect, 1)
+	projects, err := DB.SelectAllFrom(ProjectTable, tail, args...)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, p := range projects {
+		fmt.Println(p.(*Project).Name)
+	}
+	// Output:
+	// Thirsty Queen
+	// Kosher Traveler
+}
+
 func ExampleQuerier_InsertMulti() {
 	// insert up to 3 structs at once
 	const batchSize = 3
@@ -0,0 +1,88 @@
+package reform_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"gopkg.in/reform.v1"
+	"gopkg.in/reform.v1/dialects/postgresql"
+	"gopkg.in/reform.v1/dialects/sqlserver"
+)
+
+// recordingTX is a TXInterface test double which records executed queries.
+type recordingTX struct {
+	queries []string
+}
+
+func (tx *recordingTX) ExecContext(_ context.Context, query string, _ ...interface{}) (sql.Result, error) {
+	tx.queries = append(tx.queries, query)
+	return driver.RowsAffected(0), nil
+}
+
+func (tx *recordingTX) QueryContext(context.Context, string, ...interface{}) (*sql.Rows, error) {
+	return nil, errors.New("not supported")
+}
+
+func (tx *recordingTX) QueryRowContext(context.Context, string, ...interface{}) *sql.Row {
+	return nil
+}
+
+func (tx *recordingTX) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return tx.ExecContext(context.Background(), query, args...)
+}
+
+func (tx *recordingTX) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return tx.QueryContext(context.Background(), query, args...)
+}
+
+func (tx *recordingTX) QueryRow(query string, args ...interface{}) *sql.Row {
+	return tx.QueryRowContext(context.Background(), query, args...)
+}
+
+func (tx *recordingTX) Commit() error   { return nil }
+func (tx *recordingTX) Rollback() error { return nil }
+
+// outOfTreeDialect is a Dialect which does not implement SavepointDialect.
+type outOfTreeDialect struct {
+	reform.Dialect
+}
+
+func TestSavepointMethod(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		dialect reform.Dialect
+		queries []string
+	}{
+		"Default": {
+			dialect: outOfTreeDialect{postgresql.Dialect},
+			queries: []string{
+				`SAVEPOINT "reform_savepoint_1"`, `RELEASE SAVEPOINT "reform_savepoint_1"`,
+				`SAVEPOINT "reform_savepoint_2"`, `ROLLBACK TO SAVEPOINT "reform_savepoint_2"`,
+			},
+		},
+		"SaveTransaction": {
+			dialect: sqlserver.Dialect,
+			queries: []string{
+				`SAVE TRANSACTION [reform_savepoint_1]`,
+				`SAVE TRANSACTION [reform_savepoint_2]`, `ROLLBACK TRANSACTION [reform_savepoint_2]`,
+			},
+		},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			rec := new(recordingTX)
+			tx := reform.NewTXFromInterface(rec, tc.dialect, nil)
+			assert.NoError(t, tx.InTransaction(func(*reform.TX) error { return nil }))
+			assert.EqualError(t, tx.InTransaction(func(*reform.TX) error { return errors.New("fail") }), "fail")
+			assert.Equal(t, tc.queries, rec.queries)
+		})
+	}
+}
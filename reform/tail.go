@@ -0,0 +1,379 @@
+package reform
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Cond is a condition for WHERE clause built by Tail.
+//
+// Conditions are rendered lazily, so placeholders are numbered only when tail is built
+// and depend on Dialect and start index passed to Tail.Build.
+type Cond interface {
+	render(b *tailBuilder)
+}
+
+// tailBuilder accumulates SQL and args while rendering Tail.
+type tailBuilder struct {
+	dialect Dialect
+	sql     strings.Builder
+	args    []interface{}
+	next    int
+}
+
+// arg appends argument and writes placeholder for it.
+func (b *tailBuilder) arg(arg interface{}) {
+	b.sql.WriteString(b.dialect.Placeholder(b.next))
+	b.args = append(b.args, arg)
+	b.next++
+}
+
+// column writes quoted column name. Qualified names like "table.column" are quoted part by part.
+func (b *tailBuilder) column(column string) {
+	parts := strings.Split(column, ".")
+	for i, p := range parts {
+		parts[i] = b.dialect.QuoteIdentifier(p)
+	}
+	b.sql.WriteString(strings.Join(parts, "."))
+}
+
+type compareCond struct {
+	column string
+	op     string
+	arg    interface{}
+}
+
+func (c compareCond) render(b *tailBuilder) {
+	b.column(c.column)
+	b.sql.WriteString(" " + c.op + " ")
+	b.arg(c.arg)
+}
+
+// Eq returns "column = arg" condition. For nil arg it returns "column IS NULL" condition.
+func Eq(column string, arg interface{}) Cond {
+	if arg == nil {
+		return IsNull(column)
+	}
+	return compareCond{column, "=", arg}
+}
+
+// Ne returns "column <> arg" condition. For nil arg it returns "column IS NOT NULL" condition.
+func Ne(column string, arg interface{}) Cond {
+	if arg == nil {
+		return IsNotNull(column)
+	}
+	return compareCond{column, "<>", arg}
+}
+
+// Lt returns "column < arg" condition.
+func Lt(column string, arg interface{}) Cond {
+	return compareCond{column, "<", arg}
+}
+
+// Le returns "column <= arg" condition.
+func Le(column string, arg interface{}) Cond {
+	return compareCond{column, "<=", arg}
+}
+
+// Gt returns "column > arg" condition.
+func Gt(column string, arg interface{}) Cond {
+	return compareCond{column, ">", arg}
+}
+
+// Ge returns "column >= arg" condition.
+func Ge(column string, arg interface{}) Cond {
+	return compareCond{column, ">=", arg}
+}
+
+// Like returns "column LIKE pattern" condition.
+func Like(column string, pattern string) Cond {
+	return compareCond{column, "LIKE", pattern}
+}
+
+type nullCond struct {
+	column string
+	not    bool
+}
+
+func (c nullCond) render(b *tailBuilder) {
+	b.column(c.column)
+	if c.not {
+		b.sql.WriteString(" IS NOT NULL")
+	} else {
+		b.sql.WriteString(" IS NULL")
+	}
+}
+
+// IsNull returns "column IS NULL" condition.
+func IsNull(column string) Cond {
+	return nullCond{column: column}
+}
+
+// IsNotNull returns "column IS NOT NULL" condition.
+func IsNotNull(column string) Cond {
+	return nullCond{column: column, not: true}
+}
+
+type inCond struct {
+	column string
+	args   []interface{}
+	not    bool
+}
+
+func (c inCond) render(b *tailBuilder) {
+	// "IN ()" is not valid SQL; empty list matches nothing, and empty "NOT IN" list matches everything
+	if len(c.args) == 0 {
+		if c.not {
+			b.sql.WriteString("1 = 1")
+		} else {
+			b.sql.WriteString("1 = 0")
+		}
+		return
+	}
+
+	b.column(c.column)
+	if c.not {
+		b.sql.WriteString(" NOT IN (")
+	} else {
+		b.sql.WriteString(" IN (")
+	}
+	for i, arg := range c.args {
+		if i != 0 {
+			b.sql.WriteString(", ")
+		}
+		b.arg(arg)
+	}
+	b.sql.WriteString(")")
+}
+
+// In returns "column IN (args...)" condition with one placeholder per argument.
+// For empty args it returns condition which is always false.
+func In(column string, args ...interface{}) Cond {
+	return inCond{column: column, args: args}
+}
+
+// NotIn returns "column NOT IN (args...)" condition with one placeholder per argument.
+// For empty args it returns condition which is always true.
+func NotIn(column string, args ...interface{}) Cond {
+	return inCond{column: column, args: args, not: true}
+}
+
+type exprCond struct {
+	expr string
+	args []interface{}
+}
+
+func (c exprCond) render(b *tailBuilder) {
+	expr := c.expr
+	var i int
+	for {
+		p := strings.IndexByte(expr, '?')
+		if p < 0 {
+			break
+		}
+		b.sql.WriteString(expr[:p])
+		if i < len(c.args) {
+			b.arg(c.args[i])
+		} else {
+			// leave extra markers as is, query will fail with clear error from database
+			b.sql.WriteByte('?')
+		}
+		i++
+		expr = expr[p+1:]
+	}
+	b.sql.WriteString(expr)
+}
+
+// Expr returns raw SQL condition. Each "?" in expr is replaced with a dialect's placeholder
+// for a corresponding argument. Identifiers in expr are not quoted.
+func Expr(expr string, args ...interface{}) Cond {
+	return exprCond{expr: expr, args: args}
+}
+
+type groupCond struct {
+	op    string
+	conds []Cond
+}
+
+func (c groupCond) render(b *tailBuilder) {
+	if len(c.conds) == 1 {
+		c.conds[0].render(b)
+		return
+	}
+
+	b.sql.WriteString("(")
+	for i, cond := range c.conds {
+		if i != 0 {
+			b.sql.WriteString(" " + c.op + " ")
+		}
+		cond.render(b)
+	}
+	b.sql.WriteString(")")
+}
+
+// And returns condition which is true when all given conditions are true.
+// For empty conds it returns condition which is always true.
+func And(conds ...Cond) Cond {
+	if len(conds) == 0 {
+		return Expr("1 = 1")
+	}
+	return groupCond{"AND", conds}
+}
+
+// Or returns condition which is true when any of given conditions is true.
+// For empty conds it returns condition which is always false.
+func Or(conds ...Cond) Cond {
+	if len(conds) == 0 {
+		return Expr("1 = 0")
+	}
+	return groupCond{"OR", conds}
+}
+
+type notCond struct {
+	cond Cond
+}
+
+func (c notCond) render(b *tailBuilder) {
+	b.sql.WriteString("NOT (")
+	c.cond.render(b)
+	b.sql.WriteString(")")
+}
+
+// Not returns negation of given condition.
+func Not(cond Cond) Cond {
+	return notCond{cond}
+}
+
+type orderBy struct {
+	column string
+	desc   bool
+}
+
+// Tail builds tail of SQL query (WHERE, ORDER BY and LIMIT/OFFSET clauses) and its args
+// for Querier methods which accept tail and args, like SelectAllFrom, Count, UpdateView and DeleteFrom.
+// Zero value is an empty tail ready to use.
+//
+// See Tail.Build example for idiomatic usage.
+type Tail struct {
+	where   []Cond
+	orderBy []orderBy
+	limit   int
+	offset  int
+}
+
+// NewTail returns a new empty Tail.
+func NewTail() *Tail {
+	return new(Tail)
+}
+
+// Where adds given conditions to WHERE clause. All conditions are joined with AND.
+func (t *Tail) Where(conds ...Cond) *Tail {
+	t.where = append(t.where, conds...)
+	return t
+}
+
+// OrderBy adds ascending sorting by given columns to ORDER BY clause.
+func (t *Tail) OrderBy(columns ...string) *Tail {
+	for _, c := range columns {
+		t.orderBy = append(t.orderBy, orderBy{column: c})
+	}
+	return t
+}
+
+// OrderByDesc adds descending sorting by given columns to ORDER BY clause.
+func (t *Tail) OrderByDesc(columns ...string) *Tail {
+	for _, c := range columns {
+		t.orderBy = append(t.orderBy, orderBy{column: c, desc: true})
+	}
+	return t
+}
+
+// Limit sets maximum number of rows. Zero or negative value removes limit.
+func (t *Tail) Limit(limit int) *Tail {
+	t.limit = limit
+	return t
+}
+
+// Offset sets number of rows to skip. Zero or negative value removes offset.
+func (t *Tail) Offset(offset int) *Tail {
+	t.offset = offset
+	return t
+}
+
+// BuildWhere returns only WHERE clause of the tail and its args.
+// Placeholders are numbered starting from start. Use it for Count, UpdateView and DeleteFrom,
+// where ORDER BY and LIMIT clauses are not allowed or not portable.
+//
+// UpdateView uses placeholders for updated columns first, so start should be len(columns) + 1.
+func (t *Tail) BuildWhere(dialect Dialect, start int) (string, []interface{}) {
+	b := &tailBuilder{dialect: dialect, next: start}
+	t.buildWhere(b)
+	return b.sql.String(), b.args
+}
+
+// Build returns full tail and its args. Placeholders are numbered starting from start, which is 1
+// for all Querier's select methods.
+//
+// For dialects with SelectTop method (SQL Server) LIMIT and OFFSET are rendered as
+// OFFSET ... ROWS FETCH NEXT ... ROWS ONLY, which requires ORDER BY clause.
+func (t *Tail) Build(dialect Dialect, start int) (string, []interface{}) {
+	b := &tailBuilder{dialect: dialect, next: start}
+	t.buildWhere(b)
+
+	if len(t.orderBy) != 0 {
+		t.space(b)
+		b.sql.WriteString("ORDER BY ")
+		for i, o := range t.orderBy {
+			if i != 0 {
+				b.sql.WriteString(", ")
+			}
+			b.column(o.column)
+			if o.desc {
+				b.sql.WriteString(" DESC")
+			}
+		}
+	}
+
+	switch dialect.SelectLimitMethod() {
+	case Limit:
+		if t.limit > 0 {
+			t.space(b)
+			b.sql.WriteString("LIMIT " + strconv.Itoa(t.limit))
+		}
+		if t.offset > 0 {
+			t.space(b)
+			b.sql.WriteString("OFFSET " + strconv.Itoa(t.offset))
+		}
+
+	case SelectTop:
+		if t.limit > 0 || t.offset > 0 {
+			t.space(b)
+			b.sql.WriteString("OFFSET " + strconv.Itoa(t.offset) + " ROWS")
+		}
+		if t.limit > 0 {
+			b.sql.WriteString(" FETCH NEXT " + strconv.Itoa(t.limit) + " ROWS ONLY")
+		}
+	}
+
+	return b.sql.String(), b.args
+}
+
+func (t *Tail) buildWhere(b *tailBuilder) {
+	if len(t.where) == 0 {
+		return
+	}
+
+	b.sql.WriteString("WHERE ")
+	for i, cond := range t.where {
+		if i != 0 {
+			b.sql.WriteString(" AND ")
+		}
+		cond.render(b)
+	}
+}
+
+func (t *Tail) space(b *tailBuilder) {
+	if b.sql.Len() != 0 {
+		b.sql.WriteString(" ")
+	}
+}
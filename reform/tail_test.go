@@ -0,0 +1,122 @@
+package reform_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gopkg.in/reform.v1"
+	"gopkg.in/reform.v1/dialects/mysql"
+	"gopkg.in/reform.v1/dialects/postgresql"
+	"gopkg.in/reform.v1/dialects/sqlserver"
+	. "gopkg.in/reform.v1/internal/test/models"
+)
+
+func TestTailBuild(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Empty", func(t *testing.T) {
+		t.Parallel()
+
+		tail, args := reform.NewTail().Build(postgresql.Dialect, 1)
+		assert.Equal(t, "", tail)
+		assert.Nil(t, args)
+	})
+
+	t.Run("Full", func(t *testing.T) {
+		t.Parallel()
+
+		tb := reform.NewTail().
+			Where(reform.Eq("name", "Alice"), reform.In("id", 1, 2, 3)).
+			Where(reform.Or(reform.IsNull("email"), reform.Like("email", "%@example.com"))).
+			OrderByDesc("created_at").
+			OrderBy("people.id").
+			Limit(10).
+			Offset(20)
+
+		tail, args := tb.Build(postgresql.Dialect, 1)
+		expected := `WHERE "name" = $1 AND "id" IN ($2, $3, $4) AND ("email" IS NULL OR "email" LIKE $5) ` +
+			`ORDER BY "created_at" DESC, "people"."id" LIMIT 10 OFFSET 20`
+		assert.Equal(t, expected, tail)
+		assert.Equal(t, []interface{}{"Alice", 1, 2, 3, "%@example.com"}, args)
+
+		tail, args = tb.Build(mysql.Dialect, 1)
+		expected = "WHERE `name` = ? AND `id` IN (?, ?, ?) AND (`email` IS NULL OR `email` LIKE ?) " +
+			"ORDER BY `created_at` DESC, `people`.`id` LIMIT 10 OFFSET 20"
+		assert.Equal(t, expected, tail)
+		assert.Equal(t, []interface{}{"Alice", 1, 2, 3, "%@example.com"}, args)
+
+		tail, _ = tb.Build(sqlserver.Dialect, 1)
+		expected = "WHERE [name] = @P1 AND [id] IN (@P2, @P3, @P4) AND ([email] IS NULL OR [email] LIKE @P5) " +
+			"ORDER BY [created_at] DESC, [people].[id] OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY"
+		assert.Equal(t, expected, tail)
+	})
+
+	t.Run("Start", func(t *testing.T) {
+		t.Parallel()
+
+		tb := reform.NewTail().Where(reform.Gt("id", 5), reform.Expr("lower(name) = lower(?)", "bob")).OrderBy("id")
+
+		tail, args := tb.BuildWhere(postgresql.Dialect, 3)
+		assert.Equal(t, `WHERE "id" > $3 AND lower(name) = lower($4)`, tail)
+		assert.Equal(t, []interface{}{5, "bob"}, args)
+	})
+
+	t.Run("Special", func(t *testing.T) {
+		t.Parallel()
+
+		tb := reform.NewTail().Where(
+			reform.In("id"),
+			reform.NotIn("id"),
+			reform.Eq("email", nil),
+			reform.Ne("email", nil),
+			reform.Not(reform.And(reform.Ge("id", 1), reform.Le("id", 2))),
+		)
+		tail, args := tb.BuildWhere(postgresql.Dialect, 1)
+		expected := `WHERE 1 = 0 AND 1 = 1 AND "email" IS NULL AND "email" IS NOT NULL AND NOT (("id" >= $1 AND "id" <= $2))`
+		assert.Equal(t, expected, tail)
+		assert.Equal(t, []interface{}{1, 2}, args)
+	})
+}
+
+func TestTailQuerier(t *testing.T) {
+	db, tx := setupTX(t)
+	defer teardown(t, db)
+
+	q := tx.Querier
+
+	t.Run("SelectAllFrom", func(t *testing.T) {
+		tail, args := reform.NewTail().
+			Where(reform.In("id", 1, 2, 102), reform.Ne("name", "Garrick Muller")).
+			OrderByDesc("id").
+			Build(q.Dialect, 1)
+		structs, err := q.SelectAllFrom(PersonTable, tail, args...)
+		require.NoError(t, err)
+		require.Len(t, structs, 2)
+		assert.Equal(t, int32(102), structs[0].(*Person).ID)
+		assert.Equal(t, int32(1), structs[1].(*Person).ID)
+	})
+
+	t.Run("Count", func(t *testing.T) {
+		tail, args := reform.NewTail().Where(reform.Eq("name", "Elfrieda Abbott")).BuildWhere(q.Dialect, 1)
+		count, err := q.Count(PersonTable, tail, args...)
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+	})
+
+	t.Run("UpdateViewAndDeleteFrom", func(t *testing.T) {
+		columns := []string{"name"}
+		tail, args := reform.NewTail().Where(reform.In("id", 102, 103)).BuildWhere(q.Dialect, len(columns)+1)
+		ra, err := q.UpdateView(&Person{Name: "Elfrieda"}, columns, tail, args...)
+		require.NoError(t, err)
+		assert.Equal(t, uint(2), ra)
+
+		tail, args = reform.NewTail().Where(reform.Eq("name", "Elfrieda")).BuildWhere(q.Dialect, 1)
+		ra, err = q.DeleteFrom(PersonTable, tail, args...)
+		require.NoError(t, err)
+		assert.Equal(t, uint(2), ra)
+	})
+
+	require.NoError(t, tx.Rollback())
+}
@@ -3,6 +3,7 @@ package reform
 import (
 	"context"
 	"database/sql"
+	"strconv"
 	"time"
 )
 
@@ -26,7 +27,8 @@ var _ TXInterface = (*sql.Tx)(nil)
 // TX represents a SQL database transaction.
 type TX struct {
 	*Querier
-	tx TXInterface
+	tx         TXInterface
+	savepoints int
 }
 
 // NewTX creates new TX object for given SQL database transaction.
@@ -67,6 +69,62 @@ func (tx *TX) Rollback() error {
 	return err
 }
 
+// InTransaction wraps function execution in a nested transaction implemented with a savepoint,
+// rolling back to it in case of error or panic, releasing it otherwise.
+// The same TX is passed to f; the outer transaction stays active in both cases.
+func (tx *TX) InTransaction(f func(t *TX) error) error {
+	tx.savepoints++
+	name := "reform_savepoint_" + strconv.Itoa(tx.savepoints)
+
+	if err := tx.savepoint("SAVEPOINT", "SAVE TRANSACTION", name); err != nil {
+		return err
+	}
+
+	var released bool
+	defer func() {
+		if !released {
+			// always return f() or release error, not possible rollback error
+			_ = tx.savepoint("ROLLBACK TO SAVEPOINT", "ROLLBACK TRANSACTION", name)
+		}
+	}()
+
+	err := f(tx)
+	if err == nil {
+		err = tx.savepoint("RELEASE SAVEPOINT", "", name)
+	}
+	if err == nil {
+		released = true
+	}
+	return err
+}
+
+// savepoint executes savepoint command for dialect's SavepointMethod.
+// Empty command means that it is not needed for that method.
+func (tx *TX) savepoint(savepointCommand, saveTransactionCommand, name string) error {
+	method := Savepoint
+	if d, ok := tx.Dialect.(SavepointDialect); ok {
+		method = d.SavepointMethod()
+	}
+
+	var command string
+	switch method {
+	case Savepoint:
+		command = savepointCommand
+	case SaveTransaction:
+		command = saveTransactionCommand
+	}
+	if command == "" {
+		return nil
+	}
+
+	query := command + " " + tx.QuoteIdentifier(name)
+	tx.logBefore(query, nil)
+	start := time.Now()
+	_, err := tx.tx.ExecContext(tx.ctx, query)
+	tx.logAfter(query, nil, time.Since(start), err)
+	return err
+}
+
 // check interfaces
 var (
 	_ DBTX        = (*TX)(nil)